@@ -0,0 +1,187 @@
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Cache key modes for Options.CacheKey.
+const (
+	// CacheKeyMTime fingerprints files by size and modification time.
+	CacheKeyMTime = "mtime"
+	// CacheKeyGit fingerprints tracked files by their git blob IDs, so
+	// checkouts that reset modification times still hit the cache.
+	// Untracked and locally modified files fall back to CacheKeyMTime.
+	CacheKeyGit = "git"
+)
+
+// scanCached returns the result cached in opts.CacheDir for the current
+// state of the tree, scanning and storing it on a miss.
+func scanCached(ctx context.Context, opts Options) (Result, error) {
+	key, err := scanCacheKey(ctx, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	cachePath := filepath.Join(opts.CacheDir, key+".json")
+	if payload, err := os.ReadFile(cachePath); err == nil {
+		var result Result
+		if json.Unmarshal(payload, &result) == nil {
+			result.CacheHit = true
+			return result, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return Result{}, fmt.Errorf("read scan cache: %w", err)
+	}
+
+	uncached := opts
+	uncached.CacheDir = ""
+	result, err := ScanContext(ctx, uncached)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := writeScanCache(opts.CacheDir, cachePath, result); err != nil {
+		return Result{}, fmt.Errorf("write scan cache: %w", err)
+	}
+	return result, nil
+}
+
+// writeScanCache stores result at cachePath through a temporary file, so a
+// concurrent scan never reads a partial entry. The profile describes the
+// scan that produced the entry and is not cached.
+func writeScanCache(cacheDir string, cachePath string, result Result) error {
+	result.Profile = Profile{}
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(cacheDir, ".scan-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(payload); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cachePath)
+}
+
+// scanCacheKey hashes the rules version, the options that shape a result and
+// a fingerprint of every path the scan could read: its relative path, type,
+// and size and modification time or, under CacheKeyGit, blob ID. Paths the
+// scan skips (hidden, excluded or beyond MaxDepth), files outside the
+// include globs and the cache directory itself are left out.
+func scanCacheKey(ctx context.Context, opts Options) (string, error) {
+	keyed := opts
+	keyed.CacheDir = ""
+	keyed.Workers = 0
+	keyed.ParallelCatalogs = false
+	optsPayload, err := json.Marshal(keyed)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "rules:%d\noptions:%s\n", RulesVersion, optsPayload)
+
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return "", err
+	}
+	cacheDir, err := filepath.Abs(opts.CacheDir)
+	if err != nil {
+		return "", err
+	}
+	var blobIDs map[string]string
+	if opts.CacheKey == CacheKeyGit {
+		blobIDs = gitBlobIDs(ctx, root)
+	}
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+		if entry.IsDir() && (path == cacheDir || isSkippedHiddenDir(rel, entry.Name(), opts.IncludeHidden) || exceedsMaxDepth(rel, opts.MaxDepth)) {
+			return filepath.SkipDir
+		}
+		if matchesAny(rel, opts.Exclude) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() && len(opts.Include) > 0 && !matchesAny(rel, opts.Include) && !isInsideAssetCatalog(path) {
+			return nil
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return infoErr
+		}
+		stamp := ""
+		switch {
+		case blobIDs[filepath.ToSlash(rel)] != "":
+			stamp = "git:" + blobIDs[filepath.ToSlash(rel)]
+		case info.Mode().IsRegular():
+			stamp = strconv.FormatInt(info.Size(), 10) + ":" + strconv.FormatInt(info.ModTime().UnixNano(), 10)
+		case info.Mode()&fs.ModeSymlink != 0:
+			stamp, _ = os.Readlink(path)
+		}
+		fmt.Fprintf(hash, "%q %s %q\n", filepath.ToSlash(rel), info.Mode().Type(), stamp)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// gitBlobIDs maps the slash-separated paths, relative to root, of files
+// tracked in the git index to their blob IDs. Files whose work tree content
+// differs from the index are left out, and so is everything when root is
+// not inside a git work tree or git is unavailable.
+func gitBlobIDs(ctx context.Context, root string) map[string]string {
+	staged, err := exec.CommandContext(ctx, "git", "-C", root, "ls-files", "--stage", "-z").Output()
+	if err != nil {
+		return nil
+	}
+	modified, err := exec.CommandContext(ctx, "git", "-C", root, "ls-files", "--modified", "-z").Output()
+	if err != nil {
+		return nil
+	}
+	ids := make(map[string]string)
+	for _, record := range strings.Split(string(staged), "\x00") {
+		// Records read "<mode> <object> <stage>\t<path>"; unmerged entries
+		// have a non-zero stage and fall back to modification times.
+		meta, path, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || fields[2] != "0" {
+			continue
+		}
+		ids[path] = fields[1]
+	}
+	for _, path := range strings.Split(string(modified), "\x00") {
+		delete(ids, path)
+	}
+	return ids
+}
@@ -0,0 +1,157 @@
+package assets
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScan_CacheDirReusesResultUntilSourcesChange(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, set := range []string{"icon.imageset", "unused.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, set), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	swiftPath := filepath.Join(root, "App", "View.swift")
+	if err := os.WriteFile(swiftPath, []byte(`let image = UIImage(named: "icon")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+	// The cache lives inside the scanned tree; its own entries must not
+	// invalidate the key.
+	opts := Options{Root: root, Workers: 2, CacheDir: filepath.Join(root, "cache")}
+
+	first, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("first scan: %v", err)
+	}
+	if first.CacheHit {
+		t.Fatalf("expected the first scan to miss the cache")
+	}
+	second, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("second scan: %v", err)
+	}
+	if !second.CacheHit {
+		t.Fatalf("expected the second scan to hit the cache")
+	}
+	first.Profile, second.Profile, second.CacheHit = Profile{}, Profile{}, false
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected cached result to match the scan\nscan:   %#v\ncached: %#v", first, second)
+	}
+
+	if err := os.WriteFile(swiftPath, []byte(`let image = UIImage(named: "icon"); let color = UIColor(named: "unused")`), 0o644); err != nil {
+		t.Fatalf("rewrite swift source: %v", err)
+	}
+	third, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("third scan: %v", err)
+	}
+	if third.CacheHit || len(third.UnusedAssets) != 0 {
+		t.Fatalf("expected a changed source to rescan with no unused assets, got hit=%v unused=%v", third.CacheHit, third.UnusedAssets)
+	}
+
+	opts.DynamicNames = true
+	fourth, err := Scan(opts)
+	if err != nil {
+		t.Fatalf("fourth scan: %v", err)
+	}
+	if fourth.CacheHit {
+		t.Fatalf("expected changed options to miss the cache")
+	}
+}
+
+func TestScan_GitCacheKeyHitsAcrossCheckoutThatResetsMtimes(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(catalog, "icon.imageset", "Contents.json"), []byte(`{"images":[]}`), 0o644); err != nil {
+		t.Fatalf("write Contents.json: %v", err)
+	}
+	swiftPath := filepath.Join(root, "App", "View.swift")
+	if err := os.WriteFile(swiftPath, []byte(`let image = UIImage(named: "icon")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.email", "tests@example.com"},
+		{"config", "user.name", "xcwrap tests"},
+		{"add", "."},
+		{"commit", "--quiet", "-m", "initial"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", root}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v, output=%s", args, err, out)
+		}
+	}
+	// resetMtimes stands in for a fresh CI checkout, which writes every
+	// file with the current time.
+	checkout := time.Now().Add(time.Hour)
+	resetMtimes := func() {
+		t.Helper()
+		checkout = checkout.Add(time.Hour)
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() && entry.Name() == ".git" {
+				return err
+			}
+			return os.Chtimes(path, checkout, checkout)
+		})
+		if err != nil {
+			t.Fatalf("reset mtimes: %v", err)
+		}
+	}
+	scan := func(cacheKey string, cacheDir string) Result {
+		t.Helper()
+		result, err := Scan(Options{Root: root, Workers: 2, CacheDir: cacheDir, CacheKey: cacheKey})
+		if err != nil {
+			t.Fatalf("scan with %s cache key: %v", cacheKey, err)
+		}
+		return result
+	}
+
+	gitCache, mtimeCache := t.TempDir(), t.TempDir()
+	if scan(CacheKeyGit, gitCache).CacheHit || scan(CacheKeyMTime, mtimeCache).CacheHit {
+		t.Fatalf("expected the first scans to miss the cache")
+	}
+	resetMtimes()
+	if !scan(CacheKeyGit, gitCache).CacheHit {
+		t.Fatalf("expected the git cache key to hit after mtimes were reset")
+	}
+	if scan(CacheKeyMTime, mtimeCache).CacheHit {
+		t.Fatalf("expected the mtime cache key to miss after mtimes were reset")
+	}
+
+	// Untracked files fall back to their modification time.
+	if err := os.WriteFile(filepath.Join(root, "App", "New.swift"), []byte(`let x = 1`), 0o644); err != nil {
+		t.Fatalf("write untracked source: %v", err)
+	}
+	if scan(CacheKeyGit, gitCache).CacheHit {
+		t.Fatalf("expected a new untracked file to miss the git cache")
+	}
+	if !scan(CacheKeyGit, gitCache).CacheHit {
+		t.Fatalf("expected an unchanged tree with an untracked file to hit the git cache")
+	}
+	resetMtimes()
+	if scan(CacheKeyGit, gitCache).CacheHit {
+		t.Fatalf("expected a touched untracked file to miss the git cache")
+	}
+
+	// Tracked files edited in the work tree no longer match their blob.
+	if err := os.WriteFile(swiftPath, []byte(`let image = UIImage(named: "icon!")`), 0o644); err != nil {
+		t.Fatalf("edit tracked source: %v", err)
+	}
+	if result := scan(CacheKeyGit, gitCache); result.CacheHit || len(result.UnusedAssets) != 1 {
+		t.Fatalf("expected an edited tracked file to rescan, got hit=%v unused=%v", result.CacheHit, result.UnusedAssets)
+	}
+}
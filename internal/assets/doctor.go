@@ -0,0 +1,99 @@
+package assets
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// Diagnosis summarizes what a scan of Options.Root would walk, to help spot
+// a wrong root or exclude patterns that skip too much or nothing at all.
+type Diagnosis struct {
+	// Catalogs is the number of asset catalogs a scan would discover.
+	Catalogs int
+	// ExcludeMatches maps every Options.Exclude pattern to the number of
+	// files and directories it skipped; unmatched patterns map to 0.
+	ExcludeMatches map[string]int
+	// SourceFiles counts the files a scan would read for references, keyed
+	// by lowercase extension.
+	SourceFiles map[string]int
+}
+
+// Diagnose walks opts.Root the way Scan does without reading any file, and
+// reports catalog, exclude and source file counts.
+func Diagnose(opts Options) (Diagnosis, error) {
+	diagnosis := Diagnosis{
+		ExcludeMatches: make(map[string]int, len(opts.Exclude)),
+		SourceFiles:    make(map[string]int),
+	}
+	for _, pattern := range opts.Exclude {
+		diagnosis.ExcludeMatches[pattern] = 0
+	}
+
+	err := filepath.WalkDir(opts.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, relErr := filepath.Rel(opts.Root, path)
+		if relErr != nil {
+			return relErr
+		}
+		if d.IsDir() && (isSkippedHiddenDir(rel, d.Name(), opts.IncludeHidden) || exceedsMaxDepth(rel, opts.MaxDepth)) {
+			return filepath.SkipDir
+		}
+		excluded := false
+		for _, pattern := range opts.Exclude {
+			if matchesAny(rel, []string{pattern}) {
+				diagnosis.ExcludeMatches[pattern]++
+				excluded = true
+			}
+		}
+		if excluded {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if len(opts.Include) > 0 && !matchesAny(rel, opts.Include) {
+			return nil
+		}
+
+		if d.IsDir() {
+			if strings.HasSuffix(d.Name(), ".xcassets") {
+				if (len(opts.Catalogs) == 0 || matchesAny(rel, opts.Catalogs)) && (opts.IncludeBundles || !isInsideBundle(rel)) {
+					diagnosis.Catalogs++
+				}
+				// Files inside catalogs are never scanned for references.
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if matchesAny(rel, opts.Generated) {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		_, isSource := sourceExtensions[ext]
+		_, isDocC := doccExtensions[ext]
+		_, isHTML := htmlExtensions[ext]
+		if isSource || (isDocC && opts.DocC) || (isHTML && opts.HTML) {
+			diagnosis.SourceFiles[ext]++
+		}
+		return nil
+	})
+	if err != nil {
+		return Diagnosis{}, err
+	}
+	return diagnosis, nil
+}
+
+// isInsideBundle reports whether rel lies below a .bundle directory, where
+// catalog discovery stops unless Options.IncludeBundles is set.
+func isInsideBundle(rel string) bool {
+	parts := splitPathSegments(rel)
+	for _, part := range parts[:max(len(parts)-1, 0)] {
+		if strings.HasSuffix(part, ".bundle") {
+			return true
+		}
+	}
+	return false
+}
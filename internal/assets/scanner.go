@@ -1,60 +1,406 @@
 package assets
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"github.com/bmatcuk/doublestar/v4"
 	"io/fs"
+	"maps"
+	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
 
+// RulesVersion identifies the detection rule set. Bump it whenever a change
+// can alter which assets a scan reports as used, so consumers can tell
+// reports apart and invalidate cached results.
+const RulesVersion = 27
+
+// testSourcePatterns classify source files whose references only count
+// toward test-scope usage.
+var testSourcePatterns = []string{
+	"**/*Tests/**",
+	"**/*Test*.swift",
+}
+
 var sourceExtensions = map[string]struct{}{
 	".swift":      {},
 	".m":          {},
+	".mm":         {},
 	".h":          {},
+	".pch":        {},
 	".xib":        {},
 	".storyboard": {},
+	".plist":      {},
+	".xcconfig":   {},
+	".pbxproj":    {},
+}
+
+// objcExtensions are the Objective-C and Objective-C++ sources that
+// Objective-C-only extractors run on.
+var objcExtensions = map[string]struct{}{
+	".m":  {},
+	".mm": {},
+	".h":  {},
+}
+
+// doccExtensions are DocC documentation sources, scanned only when
+// Options.DocC is set.
+var doccExtensions = map[string]struct{}{
+	".md":       {},
+	".tutorial": {},
+}
+
+// htmlExtensions are help-book and onboarding pages, scanned only when
+// Options.HTML is set.
+var htmlExtensions = map[string]struct{}{
+	".html": {},
+	".htm":  {},
+}
+
+// buildConfigExtensions are scanned only for app icon names. Binary or
+// otherwise non-UTF-8 files with these extensions are skipped.
+var buildConfigExtensions = map[string]struct{}{
+	".plist":    {},
+	".xcconfig": {},
+	".pbxproj":  {},
 }
 
 var swiftResourceRefRe = regexp.MustCompile(`\b(?:(?:UI|NS)?(?:Image|Color)|(?:NS)?DataAsset)\s*\(\s*resource\s*:\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
-var ibImageStateRefRe = regexp.MustCompile(`\b(?:image|selectedImage|highlightedImage)\s*=\s*"([A-Za-z0-9._ -]+)"`)
-var ibNamedAssetTagRefRe = regexp.MustCompile(`<(image|color)\b[^>]*\bname\s*=\s*"([A-Za-z0-9._ -]+)"`)
-var swiftNamedImageAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)?Image\s*\(\s*(?:named|name)\s*:\s*"([A-Za-z0-9._ -]+)"`)
-var swiftNamedColorAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)?Color\s*\(\s*(?:named|name)\s*:\s*"([A-Za-z0-9._ -]+)"`)
-var swiftNamedDataAssetRefRe = regexp.MustCompile(`\b(?:NS)?DataAsset\s*\(\s*(?:named|name)\s*:\s*"([A-Za-z0-9._ -]+)"`)
-var swiftUIImageAssetRefRe = regexp.MustCompile(`\bImage\s*\(\s*"([A-Za-z0-9._ -]+)"(?:\s*,[^)]*)?\)`)
-var swiftUIColorAssetRefRe = regexp.MustCompile(`\bColor\s*\(\s*"([A-Za-z0-9._ -]+)"(?:\s*,[^)]*)?\)`)
+
+// swiftSDKImageArguments lists UIKit and SwiftUI initializers whose labeled
+// arguments take a UIImage or ImageResource. Their declarations live in the
+// SDK rather than in scanned sources, so a bare `.member` passed there is
+// resolved as a generated image symbol, e.g. UIAction(title:image: .settings).
+var swiftSDKImageArguments = map[string][]string{
+	"Button":          {"image"},
+	"Label":           {"image"},
+	"UIAction":        {"image"},
+	"UIBarButtonItem": {"image", "landscapeImagePhone"},
+	"UICommand":       {"image"},
+	"UIImageView":     {"image", "highlightedImage"},
+	"UIKeyCommand":    {"image"},
+	"UIMenu":          {"image"},
+	"UITabBarItem":    {"image", "selectedImage"},
+}
+var swiftSDKImageCallRe = regexp.MustCompile(`\b(` + strings.Join(slices.Sorted(maps.Keys(swiftSDKImageArguments)), "|") + `)\s*\(`)
+var swiftLabeledMemberArgumentRe = regexp.MustCompile(`(?:^|,)\s*([A-Za-z_][A-Za-z0-9_]*)\s*:\s*\.([A-Za-z_][A-Za-z0-9_]*)\b`)
+
+// ibImageAttributes lists the Interface Builder attributes whose value names
+// an image set, including bar appearance images on navigation, tab and tool
+// bars.
+var ibImageAttributes = []string{
+	"image",
+	"selectedImage",
+	"highlightedImage",
+	"backgroundImage",
+	"shadowImage",
+	"backIndicatorImage",
+	"backIndicatorTransitionMaskImage",
+	"selectionIndicatorImage",
+}
+var ibImageStateRefRe = regexp.MustCompile(`\b(?:` + strings.Join(ibImageAttributes, "|") + `)\s*=\s*"([^"\\\n\r]+)"`)
+var ibRuntimeAttributeRe = regexp.MustCompile(`<userDefinedRuntimeAttribute\b[^>]*>`)
+var ibRuntimeAttributeTypeRe = regexp.MustCompile(`\btype\s*=\s*"([^"]*)"`)
+var ibRuntimeAttributeKeyPathRe = regexp.MustCompile(`\bkeyPath\s*=\s*"([^"]*)"`)
+var ibRuntimeAttributeValueRe = regexp.MustCompile(`\bvalue\s*=\s*"([^"\\\n\r]+)"`)
+var swiftInspectableStringRe = regexp.MustCompile(`@IBInspectable\s+(?:(?:public|open|internal|private|fileprivate|dynamic|@objc)\s+)*var\s+([A-Za-z_][A-Za-z0-9_]*)\s*:\s*String\b`)
+var objcInspectableStringRe = regexp.MustCompile(`@property\s*(?:\([^)]*\))?\s*IBInspectable\s+NSString\s*\*\s*(?:(?:_Nullable|_Nonnull|nullable|nonnull)\s+)?([A-Za-z_][A-Za-z0-9_]*)`)
+var inspectableImageKeyRe = regexp.MustCompile(`(?i)image|icon`)
+var swiftODRRequestRe = regexp.MustCompile(`\bNSBundleResourceRequest\s*\(\s*tags\s*:\s*(?:Set\s*(?:<String>)?\s*\(\s*)?\[([^\]]*)\]`)
+var objcODRRequestRe = regexp.MustCompile(`\binitWithTags\s*:\s*\[\s*NSSet\s+setWith(?:Object|Objects|Array)\s*:([^;]*)`)
+var odrTagLiteralRe = regexp.MustCompile(`"([^"\\\n\r]+)"`)
+var ibNamedAssetTagRefRe = regexp.MustCompile(`<(image|color)\b[^>]*\bname\s*=\s*"([^"\\\n\r]+)"`)
+var swiftNamedImageAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)?Image\s*\(\s*(?:named|name)\s*:\s*"`)
+var swiftNamedColorAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)?Color\s*\(\s*(?:named|name)\s*:\s*"`)
+var swiftNamedDataAssetRefRe = regexp.MustCompile(`\b(?:NS)?DataAsset\s*\(\s*(?:named|name)\s*:\s*"`)
+var swiftWatchImageNamedRefRe = regexp.MustCompile(`\bset(?:Background)?ImageNamed\s*\(\s*"`)
+var swiftTextureNameRefRe = regexp.MustCompile(`(?:\.newTexture\s*\(\s*name|\bMDLTexture\s*\(\s*named)\s*:\s*"`)
+var swiftUIImageAssetRefRe = regexp.MustCompile(`\bImage\s*\(\s*"`)
+var swiftUIColorAssetRefRe = regexp.MustCompile(`\bColor\s*\(\s*"`)
 var swiftResourceParameterRe = regexp.MustCompile(`(?:^|[,(])\s*([A-Za-z_][A-Za-z0-9_]*|_)\s*(?:[A-Za-z_][A-Za-z0-9_]*)?\s*:\s*(?:\[[ \t]*)?(ImageResource|ColorResource)(?:[ \t]*\])?\s*[!?]?`)
-var objcImageNamedAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)Image\s+imageNamed:\s*@\"([A-Za-z0-9._ -]+)\"`)
+var swiftPositionalResourceParameterRe = regexp.MustCompile(`\b(?:func\s+([A-Za-z_][A-Za-z0-9_]*)|init[?!]?)\s*(?:<[^<>(){}]*>)?\s*\(\s*_\s+[A-Za-z_][A-Za-z0-9_]*\s*:\s*(ImageResource|ColorResource)\b`)
+var swiftLabelMemberPairRe = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\s*:\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
+var swiftCalleeMemberPairRe = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\s*\(\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*[,)]`)
+
+// xcodeAssetSymbolDefinitionRe matches a symbol definition in the
+// GeneratedAssetSymbols.swift file Xcode writes when "Generate Asset Symbols"
+// is on, e.g. static let hero = DeveloperToolsSupport.ImageResource(name:
+// "hero", bundle: resourceBundle).
+var xcodeAssetSymbolDefinitionRe = regexp.MustCompile(`\bstatic\s+let\s+[A-Za-z_][A-Za-z0-9_]*\s*=\s*(?:DeveloperToolsSupport\.)?(?:ImageResource|ColorResource)\(\s*name:\s*"[^"\\\n\r]*"\s*,\s*bundle:\s*resourceBundle\s*\)`)
+var swiftGeneratedAccessorDefinitionRe = regexp.MustCompile(`\b(?:let|var)\s+([A-Za-z_][A-Za-z0-9_]*)\b[^\n\r]*?\bnamed?\s*:\s*"([^"\\\n\r]+)"`)
+
+// swiftImageNameConstantRe matches an NSImage.Name constant holding a literal
+// name, e.g. `static let hero = NSImage.Name("hero")` in an extension of
+// NSImage.Name, or `static let hero: NSImage.Name = "hero"`.
+var swiftImageNameConstantRe = regexp.MustCompile(`\bstatic\s+(?:let|var)\s+([A-Za-z_][A-Za-z0-9_]*)\s*(?::\s*NSImage\.Name\s*=\s*|=\s*NSImage\.Name\s*\(\s*(?:rawValue\s*:\s*)?)"([^"\\\n\r]+)"`)
+
+// swiftImageNamedMemberRe matches `NSImage(named: .hero)` and
+// `NSImage(named: NSImage.Name.hero)` lookups of NSImage.Name constants.
+var swiftImageNamedMemberRe = regexp.MustCompile(`\bNSImage\s*\(\s*named\s*:\s*(?:NSImage\.Name)?\.([A-Za-z_][A-Za-z0-9_]*)\s*\)`)
+var swiftRawValueLoadRe = regexp.MustCompile(`\b((?:UI|NS)?Image|(?:UI|NS)?Color)\s*\(\s*(?:named\s*:\s*)?([A-Za-z_][A-Za-z0-9_]*)(?:\.([A-Za-z_][A-Za-z0-9_]*))?\.rawValue\b`)
+var swiftStringEnumRe = regexp.MustCompile(`\benum\s+([A-Za-z_][A-Za-z0-9_]*)\s*:\s*String\b[^{]*\{`)
+var swiftEnumCaseDeclRe = regexp.MustCompile(`(?m)^[ \t]*(?:indirect[ \t]+)?case[ \t]+([A-Za-z_][A-Za-z0-9_]*(?:[ \t]*=[ \t]*"[^"\\\n\r]*")?(?:[ \t]*,[ \t]*[A-Za-z_][A-Za-z0-9_]*(?:[ \t]*=[ \t]*"[^"\\\n\r]*")?)*)[ \t]*(?://[^\n\r]*)?\r?$`)
+var swiftEnumCaseItemRe = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)(?:\s*=\s*"([^"\\\n\r]*)")?`)
+var swiftTypedValueDeclRe = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\s*:\s*([A-Za-z_][A-Za-z0-9_]*)\b`)
+var swiftAllCasesLoopRe = regexp.MustCompile(`\bfor\s+([A-Za-z_][A-Za-z0-9_]*)\s+in\s+([A-Za-z_][A-Za-z0-9_]*)\.allCases\b`)
+var swiftTypeDeclarationRe = regexp.MustCompile(`\b(?:struct|class|enum|actor|extension)\s+([A-Za-z_][A-Za-z0-9_.]*)`)
+var objcImageNamedAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)Image\s+imageNamed:\s*@\"([^"\\\n\r]+)\"`)
 var objcImageNamedVariableRefRe = regexp.MustCompile(`\b(?:UI|NS)Image\s+imageNamed:\s*([A-Za-z_][A-Za-z0-9_]*)`)
-var objcStringLiteralRe = regexp.MustCompile(`@\"([A-Za-z0-9._ -]+)\"`)
-var objcColorNamedAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)Color\s+colorNamed:\s*@\"([A-Za-z0-9._ -]+)\"`)
-var objcDataAssetNameRefRe = regexp.MustCompile(`\b(?:NS)?DataAsset\b[^\n\r;]*\binitWithName:\s*@\"([A-Za-z0-9._ -]+)\"`)
-var swiftTypedResourceVarRe = regexp.MustCompile(`\b(?:var|let)\s+([A-Za-z_][A-Za-z0-9_]*)\s*:\s*(?:\[[ \t]*)?(?:ImageResource|ColorResource)(?:[ \t]*\])?`)
-var swiftTypedResourceVarInitRe = regexp.MustCompile(`\b(?:var|let)\s+([A-Za-z_][A-Za-z0-9_]*)\s*=\s*\[\s*(?:ImageResource|ColorResource)\s*\]\s*\(\s*\)`)
+var objcStringLiteralRe = regexp.MustCompile(`@\"([^"\\\n\r]+)\"`)
+var objcColorNamedAssetRefRe = regexp.MustCompile(`\b(?:UI|NS)Color\s+colorNamed:\s*@\"([^"\\\n\r]+)\"`)
+var objcDataAssetNameRefRe = regexp.MustCompile(`\b(?:NS)?DataAsset\b[^\n\r;]*\binitWithName:\s*@\"([^"\\\n\r]+)\"`)
+var objcWatchImageNamedRefRe = regexp.MustCompile(`\bset(?:Background)?ImageNamed:\s*@\"([^"\\\n\r]+)\"`)
+var objcTextureNameRefRe = regexp.MustCompile(`(?:\bnewTextureWithName|\bMDLTexture\s+textureNamed)\s*:\s*@\"([^"\\\n\r]+)\"`)
+var swiftTypedResourceVarRe = regexp.MustCompile(`\b(?:var|let)\s+([A-Za-z_][A-Za-z0-9_]*)\s*:\s*(?:\[[ \t]*(?:[A-Za-z_][A-Za-z0-9_.]*[ \t]*:[ \t]*)?)?(?:ImageResource|ColorResource)(?:[ \t]*\])?`)
+var swiftTypedResourceVarInitRe = regexp.MustCompile(`\b(?:var|let)\s+([A-Za-z_][A-Za-z0-9_]*)\s*=\s*\[\s*(?:[A-Za-z_][A-Za-z0-9_.]*\s*:\s*)?(?:ImageResource|ColorResource)\s*\]\s*\(\s*\)`)
 var swiftTypedResourceScalarVarRe = regexp.MustCompile(`\b(?:var|let)\s+([A-Za-z_][A-Za-z0-9_]*)\s*:\s*(?:ImageResource|ColorResource)\s*[!?]?`)
 var swiftResourceReturnTypeRe = regexp.MustCompile(`(?:func|var)\s+[A-Za-z_][A-Za-z0-9_]*[^{\n\r]*->\s*(?:ImageResource|ColorResource)|\bvar\s+[A-Za-z_][A-Za-z0-9_]*\s*:\s*(?:ImageResource|ColorResource)\s*\{`)
 var swiftReturnEnumMemberRe = regexp.MustCompile(`\breturn\s+\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// swiftSwitchArmEnumMemberRe matches switch expression arms whose whole
+// value is an implicit member, such as "case .beta: .betaIcon" or
+// "default: .placeholder", which yield the function's return value without
+// a return keyword.
+var swiftSwitchArmEnumMemberRe = regexp.MustCompile(`(?m)(?:\bcase\b[^:\n\r]*|\bdefault\s*):\s*\.([A-Za-z_][A-Za-z0-9_]*)[ \t]*(?:$|\}|//)`)
+var swiftPreviewMacroRe = regexp.MustCompile(`#Preview\b`)
+var swiftConditionalDirectiveRe = regexp.MustCompile(`^[ \t]*#(if|elseif|else|endif)\b([^\n]*)`)
 var swiftEnumMemberRefRe = regexp.MustCompile(`\.\s*([A-Za-z_][A-Za-z0-9_]*)`)
+var swiftStringLiteralRe = regexp.MustCompile(`"(?:[^"\\\n\r]|\\.)*"`)
+var plistIconNameRefRe = regexp.MustCompile(`<key>\s*CFBundleIconName\s*</key>\s*<string>\s*([^<\n\r]+?)\s*</string>`)
+var buildSettingAppIconNameRe = regexp.MustCompile(`\bASSETCATALOG_COMPILER_APPICON_NAME\s*=\s*"?([A-Za-z0-9._-]+)"?`)
+var buildSettingAlternateAppIconNamesRe = regexp.MustCompile(`\bASSETCATALOG_COMPILER_ALTERNATE_APPICON_NAMES\s*=\s*"?([A-Za-z0-9._ \t-]+)"?`)
+var swiftAlternateIconNameRefRe = regexp.MustCompile(`\bsetAlternateIconName\s*\(\s*"([^"\\\n\r]+)"`)
+var objcAlternateIconNameRefRe = regexp.MustCompile(`\bsetAlternateIconName:\s*@\"([^"\\\n\r]+)\"`)
+var swiftSystemSymbolNameRefRe = regexp.MustCompile(`\b(?:UI|NS)?Image\s*\(\s*(?:systemName|systemSymbolName)\s*:\s*"([^"\\\n\r]+)"`)
+var doccImageDirectiveRefRe = regexp.MustCompile(`@Image\s*\(\s*source\s*:\s*"([^"\\\n\r]+)"`)
+var htmlImageSrcRefRe = regexp.MustCompile(`<img\b[^>]*\bsrc\s*=\s*"([^"]+)"`)
+var imageScaleSuffixRe = regexp.MustCompile(`@[1-9]x$`)
+var markdownImageRefRe = regexp.MustCompile(`!\[[^\]\n\r]*\]\(\s*([A-Za-z0-9._-]+)\s*\)`)
+var swiftMarkdownImageRefRe = regexp.MustCompile(`!\[[^\]\n\r]*\]\(\s*([^)\s"]+)[^)\n\r]*\)`)
+var swiftStringLiteralConcatRe = regexp.MustCompile(`"([^"\\\n\r]*)"\s*\+\s*"([^"\\\n\r]*)"`)
+var swiftBundleResourceRefRe = regexp.MustCompile(`\bforResource\s*:\s*"([^"\\\n\r]+)"`)
+var swiftAppStorageDefaultRefRe = regexp.MustCompile(`@AppStorage\s*\([^)\n\r]*\)\s*(?:(?:private|fileprivate|internal|public)\s+)?var\s+[A-Za-z_][A-Za-z0-9_]*\s*(?::\s*String\s*)?=\s*"([^"\\\n\r]+)"`)
+var swiftRegisterDefaultsRe = regexp.MustCompile(`\bregister\s*\(\s*defaults\s*:\s*\[([^\]]*)\]`)
+var swiftDictionaryStringValueRe = regexp.MustCompile(`:\s*"([^"\\\n\r]+)"`)
+var swiftLocalizedKeyRefRe = regexp.MustCompile(`\bString\s*\(\s*localized\s*:\s*"([^"\\\n\r]+)"`)
+var swiftLocalizedImageNameRefRe = regexp.MustCompile(`\b(?:UI|NS)?Image\s*\(\s*(?:named|name)\s*:\s*NSLocalizedString\s*\(\s*"`)
+var swiftInterpolatedAssetNameRefRe = regexp.MustCompile(`\b(?:UI|NS)?(Image|Color)\s*\(\s*(?:(?:named|name)\s*:\s*)?"([^"\n\r]*\\\([^"\n\r]*)"`)
 
 type Options struct {
 	Root    string
 	Include []string
 	Exclude []string
 	Workers int
+	// DynamicNames marks assets used when they match the static prefix and/or
+	// suffix around an interpolated Swift asset name, e.g. "icon_\(state)".
+	DynamicNames bool
+	// MaxDepth bounds how many directory levels below Root are walked; nil
+	// means unlimited and 0 scans only files directly in Root. Directories
+	// inside an asset catalog count as the catalog's own depth.
+	MaxDepth *int
+	// BundleResources marks assets used when a Swift bundle lookup such as
+	// Bundle.main.path(forResource:ofType:) names them. This is lower
+	// confidence than catalog APIs and therefore opt-in.
+	BundleResources bool
+	// LocalizedKeys marks assets used when a Swift String(localized:) key
+	// matches their name. Some projects store asset names in localization
+	// tables; this heuristic is low confidence and therefore opt-in.
+	LocalizedKeys bool
+	// LocalizedNames marks image sets used when an image name lookup such as
+	// UIImage(named:) is passed NSLocalizedString("key", ...), taking the key
+	// as the asset name. Teams often name assets after their keys, but the
+	// runtime value may differ, so it is opt-in.
+	LocalizedNames bool
+	// Defaults marks image sets used when their name is the default string
+	// of an @AppStorage property or a UserDefaults register(defaults:) value.
+	// Such settings often hold asset names, but not always, so it is opt-in.
+	Defaults bool
+	// KeyPathAccessors names design-system accessors such as icons or image
+	// whose key path arguments, e.g. Theme.icons[\.home] or image(\.home),
+	// resolve to generated resource identifiers. Empty disables the lookup.
+	KeyPathAccessors []string
+	// IBAttributes maps extra Interface Builder attribute names, such as
+	// the image keys of custom view classes, to the asset type their value
+	// names, e.g. "iconName" to "imageset". Matching attributes in
+	// .storyboard and .xib files count as references.
+	IBAttributes map[string]string
+	// ObjCColorSelectors names Objective-C selectors, such as the
+	// colorNamed: of a theme class wrapping UIColor, whose @"..." argument
+	// names a color set on any receiver, e.g. [MyTheme colorNamed:@"brand"].
+	// Selectors are given without the trailing colon. Empty disables the
+	// lookup.
+	ObjCColorSelectors []string
+	// IBDesignable resolves storyboard and XIB user-defined runtime
+	// attributes: image-typed values, and string values whose key path is
+	// an @IBInspectable Swift String or Objective-C IBInspectable NSString
+	// property with Image or Icon in its name.
+	IBDesignable bool
+	// ODRTagsUsed marks every asset set with On-Demand Resources tags in its
+	// Contents.json used, since such assets are loaded by tag rather than by
+	// name. Without it, only sets whose tags a NSBundleResourceRequest names
+	// in source are marked used.
+	ODRTagsUsed bool
+	// ActiveConfig, when set, is the only custom Swift compilation condition
+	// considered defined: references in #if branches that cannot compile
+	// with it, such as #if DEBUG for "RELEASE", are ignored. Empty counts
+	// references in every branch.
+	ActiveConfig string
+	// ParallelCatalogs discovers catalogs by walking each immediate child
+	// directory of Root concurrently, bounded by Workers. Results are
+	// identical to the serial walk.
+	ParallelCatalogs bool
+	// IncludeHidden walks dot-directories such as .generated, which are
+	// skipped by default. .git is always skipped.
+	IncludeHidden bool
+	// Generated holds path globs of generated asset accessor files, such as
+	// SwiftGen or R.swift output. They are definition-only: names in them do
+	// not mark assets used, but `.accessor` members they define do when other
+	// Swift sources use them.
+	Generated []string
+	// Catalogs, when set, holds path globs that a .xcassets directory must
+	// match to be treated as an asset catalog. Other .xcassets directories,
+	// such as snapshot fixtures, are skipped entirely.
+	Catalogs []string
+	// IncludeBundles discovers asset catalogs inside .bundle directories.
+	// Bundles are packaged resources rather than source, so their raw
+	// catalogs are skipped by default to avoid double counting.
+	IncludeBundles bool
+	// RuleStats counts resolved references per detection rule in
+	// Result.RuleMatchCounts.
+	RuleStats bool
+	// TrackReferences records the source file, matching rule and matched
+	// text of every resolved reference in Result.References.
+	TrackReferences bool
+	// DocC scans .md and .tutorial documentation for @Image(source:) and
+	// markdown image references to image sets.
+	DocC bool
+	// HTML scans .html and .htm pages for <img src> references, matching
+	// the file's base name without extension or scale suffix to image sets.
+	HTML bool
+	// AttributedMarkdown scans Swift sources for markdown image syntax such
+	// as ![](hero), which AttributedString(markdown:) and SwiftUI Text render
+	// from bundled images, matching the target's base name to image sets.
+	// Multiline literal bodies stay blanked as for every other rule. Such
+	// strings are rare, so it is opt-in.
+	AttributedMarkdown bool
+	// CacheDir, when set, stores each result under a key derived from the
+	// rules version, these options and a CacheKey fingerprint of every path
+	// the scan could read, and returns the stored result while none of them
+	// change. Empty disables the cache.
+	CacheDir string
+	// CacheKey selects how CacheDir fingerprints files: CacheKeyMTime (the
+	// default when empty) or CacheKeyGit.
+	CacheKey string
+
+	// usageFileHook, when set, is called by usage workers before scanning
+	// each source file. Tests use it to cancel a scan while workers are
+	// running.
+	usageFileHook func(path string)
 }
 
 type Result struct {
-	AssetCatalogs int
-	AssetNames    []string
-	UsedAssets    []string
-	UnusedAssets  []string
-	UnusedByFile  map[string][]string
+	AssetCatalogs  int
+	AssetNames     []string
+	UsedAssets     []string
+	UnusedAssets   []string
+	UnusedByFile   map[string][]string
+	DuplicateNames []DuplicateName
+	// CaseCollisions lists same-typed asset names in one catalog that differ
+	// only by case, ordered by catalog, type and name.
+	CaseCollisions []CaseCollision
+	// EmptyAssetSets lists asset set paths whose only file is Contents.json.
+	// Color sets are never reported because their value lives in Contents.json.
+	EmptyAssetSets []string
+	// EmptyCatalogs lists .xcassets paths that contain no discovered asset
+	// sets.
+	EmptyCatalogs []string
+	// Catalogs lists every discovered catalog with its asset-set count.
+	Catalogs []Catalog
+	// AssetSetsByType counts the discovered asset sets per asset type, e.g.
+	// "imageset" or "colorset".
+	AssetSetsByType map[string]int
+	// DynamicNameMatches lists asset set paths matched by an interpolated
+	// Swift name family such as "flag_\(code)". It is populated even when
+	// Options.DynamicNames is off so destructive callers can protect them.
+	DynamicNameMatches []string
+	// UsedOnlyInTests lists used assets whose every reference comes from a
+	// test source; they are still included in UsedAssets.
+	UsedOnlyInTests []string
+	// UsedOnlyInPreviews lists used assets whose every reference sits inside
+	// a #Preview macro body of a non-test source; they are still included in
+	// UsedAssets.
+	UsedOnlyInPreviews []string
+	// TypeMismatches lists typed references that only match same-named
+	// assets of another type, ordered by source and name.
+	TypeMismatches []TypeMismatch
+	// RuleMatchCounts maps each detection rule to the number of references
+	// it resolved to at least one asset set. Rules without matches are
+	// absent. It is only populated when Options.RuleStats is set.
+	RuleMatchCounts map[string]int
+	// References maps asset names to the references that marked them used.
+	// It is only populated when Options.TrackReferences is set.
+	References map[string][]Reference
+	Profile    Profile
+	// CacheHit reports that the result was loaded from Options.CacheDir, or
+	// reused by Watch for an unchanged tree, instead of scanned; its Profile
+	// then describes the original scan or is zero.
+	CacheHit bool
+}
+
+// Profile breaks down where a scan spent its time.
+type Profile struct {
+	CatalogDiscovery time.Duration
+	LabelCollection  time.Duration
+	UsageDetection   time.Duration
+	// LabelFiles counts Swift files read while collecting resource labels and
+	// SourceFiles counts files scanned for asset references.
+	LabelFiles  int
+	SourceFiles int
+}
+
+// Reference is a single source match that marked an asset used.
+type Reference struct {
+	Source string
+	Rule   string
+	Text   string
+}
+
+// DuplicateName is an asset name of a single type that is defined in more
+// than one catalog.
+type DuplicateName struct {
+	Name      string
+	AssetType string
+	Catalogs  []string
+}
+
+// CaseCollision is a set of asset names of a single type in one catalog that
+// differ only by case, such as Hero and hero. They resolve to the same asset
+// on case-insensitive file systems, the macOS default.
+type CaseCollision struct {
+	Catalog   string
+	AssetType string
+	// Names holds the distinct spellings, sorted.
+	Names []string
+}
+
+// TypeMismatch is a typed reference, such as Color("hero"), that matches no
+// asset of the requested type but does match a same-named asset of another
+// type. It usually points at a bug in the referencing code.
+type TypeMismatch struct {
+	Name          string
+	RequestedType string
+	ExistingTypes []string
+	Source        string
+	Rule          string
 }
 
 type discoveredAsset struct {
@@ -62,24 +408,51 @@ type discoveredAsset struct {
 	CatalogPath string
 	AssetPath   string
 	AssetType   string
+	Empty       bool
 }
 
+type usageScope uint8
+
+const (
+	usageScopeProduction usageScope = 1 << iota
+	usageScopeTest
+	// usageScopePreview marks references inside #Preview macro bodies of
+	// non-test sources.
+	usageScopePreview
+)
+
 type sourceAssetReference struct {
 	Name      string
 	AssetType string
+	// Rule names the pattern that produced the reference and Text holds the
+	// matched source text; both are used for reference provenance.
+	Rule string
+	Text string
 }
 
 func Scan(opts Options) (Result, error) {
+	return ScanContext(context.Background(), opts)
+}
+
+// ScanContext is like Scan but stops walking and reading sources once ctx is
+// done, returning ctx.Err() after all workers have exited.
+func ScanContext(ctx context.Context, opts Options) (Result, error) {
+	if opts.CacheDir != "" {
+		return scanCached(ctx, opts)
+	}
 	workers := opts.Workers
 	if workers < 1 {
 		workers = runtime.NumCPU()
 	}
 
-	assetCatalogs, _, discoveredAssets, err := collectAssets(opts.Root, opts.Include, opts.Exclude)
+	var profile Profile
+	start := time.Now()
+	catalogPaths, _, discoveredAssets, err := collectAssets(ctx, opts)
 	if err != nil {
 		return Result{}, err
 	}
-	usedAssetPaths, err := collectUsedAssets(opts.Root, opts.Include, opts.Exclude, discoveredAssets, workers)
+	profile.CatalogDiscovery = time.Since(start)
+	usage, err := collectUsedAssets(ctx, opts, discoveredAssets, workers, &profile)
 	if err != nil {
 		return Result{}, err
 	}
@@ -89,12 +462,29 @@ func Scan(opts Options) (Result, error) {
 	usedNames := make(map[string]struct{}, len(discoveredAssets))
 	unusedNames := make(map[string]struct{}, len(discoveredAssets))
 	unusedByFile := make(map[string][]string)
+	emptyAssetSets := make([]string, 0)
+	productionNames := make(map[string]struct{}, len(discoveredAssets))
+	testNames := make(map[string]struct{})
+	previewNames := make(map[string]struct{})
 	for _, asset := range discoveredAssets {
 		summaryName := summaryNameForAsset(asset)
 		assetNamesSet[summaryName] = struct{}{}
-		if _, ok := usedAssetPaths[asset.AssetPath]; ok {
+		if asset.Empty {
+			emptyAssetSets = append(emptyAssetSets, asset.AssetPath)
+		}
+		if scope, ok := usage.scopes[asset.AssetPath]; ok {
 			usedNames[summaryName] = struct{}{}
 			delete(unusedNames, summaryName)
+			if scope&usageScopeProduction != 0 {
+				productionNames[summaryName] = struct{}{}
+				continue
+			}
+			if scope&usageScopeTest != 0 {
+				testNames[summaryName] = struct{}{}
+			}
+			if scope&usageScopePreview != 0 {
+				previewNames[summaryName] = struct{}{}
+			}
 			continue
 		}
 		if _, alreadyUsed := usedNames[summaryName]; !alreadyUsed {
@@ -121,16 +511,192 @@ func Scan(opts Options) (Result, error) {
 		slices.Sort(values)
 		unusedByFile[file] = values
 	}
+	// A name shared by several asset sets is only test- or preview-only when
+	// none of its sets has a reference from another scope.
+	usedOnlyInTests := make([]string, 0)
+	usedOnlyInPreviews := make([]string, 0)
+	for name := range usedNames {
+		if _, ok := productionNames[name]; ok {
+			continue
+		}
+		_, inTests := testNames[name]
+		_, inPreviews := previewNames[name]
+		switch {
+		case inTests && !inPreviews:
+			usedOnlyInTests = append(usedOnlyInTests, name)
+		case inPreviews && !inTests:
+			usedOnlyInPreviews = append(usedOnlyInPreviews, name)
+		}
+	}
+	slices.Sort(usedOnlyInTests)
+	slices.Sort(usedOnlyInPreviews)
 
 	return Result{
-		AssetCatalogs: assetCatalogs,
-		AssetNames:    assetNames,
-		UsedAssets:    used,
-		UnusedAssets:  unused,
-		UnusedByFile:  unusedByFile,
+		AssetCatalogs:      len(catalogPaths),
+		AssetNames:         assetNames,
+		UsedAssets:         used,
+		UnusedAssets:       unused,
+		UnusedByFile:       unusedByFile,
+		DuplicateNames:     collectDuplicateNames(discoveredAssets),
+		CaseCollisions:     collectCaseCollisions(discoveredAssets),
+		EmptyAssetSets:     emptyAssetSets,
+		EmptyCatalogs:      collectEmptyCatalogs(catalogPaths, discoveredAssets),
+		Catalogs:           buildCatalogs(catalogPaths, discoveredAssets),
+		AssetSetsByType:    countAssetSetsByType(discoveredAssets),
+		DynamicNameMatches: slices.Sorted(maps.Keys(usage.dynamicMatches)),
+		UsedOnlyInTests:    usedOnlyInTests,
+		UsedOnlyInPreviews: usedOnlyInPreviews,
+		RuleMatchCounts:    usage.ruleMatchCounts,
+		TypeMismatches:     usage.typeMismatches,
+		References:         collectReferencesByName(opts, discoveredAssets, summaryNameForAsset, usage.references),
+		Profile:            profile,
 	}, nil
 }
 
+func collectReferencesByName(opts Options, discoveredAssets []discoveredAsset, summaryNameForAsset func(discoveredAsset) string, referencesByPath map[string][]Reference) map[string][]Reference {
+	if !opts.TrackReferences {
+		return nil
+	}
+	out := make(map[string][]Reference, len(discoveredAssets))
+	for _, asset := range discoveredAssets {
+		name := summaryNameForAsset(asset)
+		out[name] = append(out[name], referencesByPath[asset.AssetPath]...)
+	}
+	for name, references := range out {
+		slices.SortFunc(references, compareReferences)
+		out[name] = slices.Compact(references)
+	}
+	return out
+}
+
+func compareReferences(a, b Reference) int {
+	if c := strings.Compare(a.Source, b.Source); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Rule, b.Rule); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Text, b.Text)
+}
+
+// Catalog is an asset catalog and the number of asset sets it contains.
+type Catalog struct {
+	Path      string
+	AssetSets int
+}
+
+// ScanCatalogs lists the asset catalogs under opts.Root with their asset-set
+// counts, skipping usage analysis entirely.
+func ScanCatalogs(ctx context.Context, opts Options) ([]Catalog, error) {
+	catalogPaths, _, discoveredAssets, err := collectAssets(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return buildCatalogs(catalogPaths, discoveredAssets), nil
+}
+
+// buildCatalogs pairs each catalog path with its discovered asset-set count.
+func buildCatalogs(catalogPaths []string, discoveredAssets []discoveredAsset) []Catalog {
+	counts := make(map[string]int, len(catalogPaths))
+	for _, asset := range discoveredAssets {
+		counts[asset.CatalogPath]++
+	}
+	catalogs := make([]Catalog, 0, len(catalogPaths))
+	for _, path := range catalogPaths {
+		catalogs = append(catalogs, Catalog{Path: path, AssetSets: counts[path]})
+	}
+	return catalogs
+}
+
+// countAssetSetsByType counts discovered asset sets per asset type.
+func countAssetSetsByType(discoveredAssets []discoveredAsset) map[string]int {
+	counts := make(map[string]int)
+	for _, asset := range discoveredAssets {
+		counts[asset.AssetType]++
+	}
+	return counts
+}
+
+// collectEmptyCatalogs returns the sorted catalog paths that no discovered
+// asset set belongs to.
+func collectEmptyCatalogs(catalogPaths []string, discoveredAssets []discoveredAsset) []string {
+	populated := make(map[string]struct{}, len(catalogPaths))
+	for _, asset := range discoveredAssets {
+		populated[asset.CatalogPath] = struct{}{}
+	}
+	out := make([]string, 0)
+	for _, path := range catalogPaths {
+		if _, ok := populated[path]; !ok {
+			out = append(out, path)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func collectDuplicateNames(discoveredAssets []discoveredAsset) []DuplicateName {
+	catalogsByKey := make(map[string]map[string]struct{}, len(discoveredAssets))
+	for _, asset := range discoveredAssets {
+		key := sourceAssetTypeKey(asset.Name, asset.AssetType)
+		if _, ok := catalogsByKey[key]; !ok {
+			catalogsByKey[key] = make(map[string]struct{}, 1)
+		}
+		catalogsByKey[key][asset.CatalogPath] = struct{}{}
+	}
+
+	out := make([]DuplicateName, 0)
+	for key, catalogSet := range catalogsByKey {
+		if len(catalogSet) < 2 {
+			continue
+		}
+		assetType, name, _ := strings.Cut(key, "\x00")
+		catalogs := make([]string, 0, len(catalogSet))
+		for catalog := range catalogSet {
+			catalogs = append(catalogs, catalog)
+		}
+		slices.Sort(catalogs)
+		out = append(out, DuplicateName{Name: name, AssetType: assetType, Catalogs: catalogs})
+	}
+	slices.SortFunc(out, func(a, b DuplicateName) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AssetType, b.AssetType)
+	})
+	return out
+}
+
+func collectCaseCollisions(discoveredAssets []discoveredAsset) []CaseCollision {
+	namesByKey := make(map[string]map[string]struct{}, len(discoveredAssets))
+	for _, asset := range discoveredAssets {
+		key := asset.CatalogPath + "\x00" + asset.AssetType + "\x00" + strings.ToLower(asset.Name)
+		if _, ok := namesByKey[key]; !ok {
+			namesByKey[key] = make(map[string]struct{}, 1)
+		}
+		namesByKey[key][asset.Name] = struct{}{}
+	}
+
+	out := make([]CaseCollision, 0)
+	for key, nameSet := range namesByKey {
+		if len(nameSet) < 2 {
+			continue
+		}
+		catalog, rest, _ := strings.Cut(key, "\x00")
+		assetType, _, _ := strings.Cut(rest, "\x00")
+		out = append(out, CaseCollision{Catalog: catalog, AssetType: assetType, Names: slices.Sorted(maps.Keys(nameSet))})
+	}
+	slices.SortFunc(out, func(a, b CaseCollision) int {
+		if c := strings.Compare(a.Catalog, b.Catalog); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.AssetType, b.AssetType); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Names[0], b.Names[0])
+	})
+	return out
+}
+
 func buildAssetSummaryNamer(discoveredAssets []discoveredAsset) func(discoveredAsset) string {
 	assetTypesByName := make(map[string]map[string]struct{}, len(discoveredAssets))
 	for _, asset := range discoveredAssets {
@@ -153,90 +719,246 @@ func buildAssetSummaryNamer(discoveredAssets []discoveredAsset) func(discoveredA
 	}
 }
 
-func collectAssets(root string, include []string, exclude []string) (int, []string, []discoveredAsset, error) {
-	assetNames := make([]string, 0, 256)
-	seen := make(map[string]struct{}, 256)
-	discoveredAssets := make([]discoveredAsset, 0, 256)
-	assetCatalogs := 0
+func collectAssets(ctx context.Context, opts Options) ([]string, []string, []discoveredAsset, error) {
+	var discovery assetDiscovery
+	var err error
+	if opts.ParallelCatalogs {
+		err = discovery.walkParallel(ctx, opts)
+	} else {
+		err = filepath.WalkDir(opts.Root, discovery.visit(ctx, opts))
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+	catalogPaths, discoveredAssets := discovery.catalogPaths, discovery.assets
+	slices.Sort(catalogPaths)
+	slices.SortFunc(discoveredAssets, func(a, b discoveredAsset) int {
+		return strings.Compare(a.AssetPath, b.AssetPath)
+	})
+	assetNames := make([]string, 0, len(discoveredAssets))
+	for _, asset := range discoveredAssets {
+		assetNames = append(assetNames, asset.Name)
+	}
+	slices.Sort(assetNames)
+	return catalogPaths, slices.Compact(assetNames), discoveredAssets, nil
+}
+
+// assetDiscovery accumulates the catalogs and asset sets found by one walk.
+type assetDiscovery struct {
+	catalogPaths []string
+	assets       []discoveredAsset
+}
+
+// visit returns the WalkDir callback that records catalogs and asset sets
+// below opts.Root into d, applying the scan's skip rules.
+func (d *assetDiscovery) visit(ctx context.Context, opts Options) fs.WalkDirFunc {
+	root, include, exclude := opts.Root, opts.Include, opts.Exclude
+	return func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 
 		rel, relErr := filepath.Rel(root, path)
 		if relErr != nil {
 			return relErr
 		}
+		if entry.IsDir() && (isSkippedHiddenDir(rel, entry.Name(), opts.IncludeHidden) || exceedsMaxDepth(rel, opts.MaxDepth)) {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() && !opts.IncludeBundles && rel != "." && strings.HasSuffix(entry.Name(), ".bundle") {
+			return filepath.SkipDir
+		}
 		if matchesAny(rel, exclude) {
-			if d.IsDir() {
+			if entry.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 		if len(include) > 0 && !matchesAny(rel, include) {
-			if d.IsDir() && rel != "." {
-				return nil
-			}
 			return nil
 		}
 
-		if d.IsDir() && strings.HasSuffix(d.Name(), ".xcassets") {
-			assetCatalogs++
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".xcassets") {
+			if len(opts.Catalogs) > 0 && !matchesAny(rel, opts.Catalogs) {
+				return filepath.SkipDir
+			}
+			d.catalogPaths = append(d.catalogPaths, path)
 			return nil
 		}
 
-		if d.IsDir() && isAssetSetDir(d.Name()) {
-			assetExt := strings.TrimPrefix(filepath.Ext(d.Name()), ".")
-			name := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		if entry.IsDir() && isAssetSetDir(entry.Name()) {
+			assetExt := strings.TrimPrefix(filepath.Ext(entry.Name()), ".")
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
 			if name != "" {
 				catalogPath := catalogPathForAsset(path)
 				if catalogPath == "" {
 					return filepath.SkipDir
 				}
-				discoveredAssets = append(discoveredAssets, discoveredAsset{
+				empty, emptyErr := isEmptyAssetSet(path, assetExt)
+				if emptyErr != nil {
+					return emptyErr
+				}
+				d.assets = append(d.assets, discoveredAsset{
 					Name:        name,
 					CatalogPath: catalogPath,
 					AssetPath:   path,
 					AssetType:   assetExt,
+					Empty:       empty,
 				})
-				if _, ok := seen[name]; !ok {
-					seen[name] = struct{}{}
-					assetNames = append(assetNames, name)
-				}
 			}
 			return filepath.SkipDir
 		}
 
 		return nil
-	})
+	}
+}
+
+// walkParallel visits opts.Root itself and then walks each immediate child
+// directory in its own goroutine, at most opts.Workers at a time, merging
+// the per-subtree results into d. Callers sort the merged results, so they
+// match a serial walk exactly.
+func (d *assetDiscovery) walkParallel(ctx context.Context, opts Options) error {
+	rootInfo, err := os.Lstat(opts.Root)
+	if err != nil {
+		return err
+	}
+	if !rootInfo.IsDir() || strings.HasSuffix(rootInfo.Name(), ".xcassets") {
+		// A catalog root holds asset sets directly; there is no subtree to
+		// fan out over.
+		return filepath.WalkDir(opts.Root, d.visit(ctx, opts))
+	}
+	if err := d.visit(ctx, opts)(opts.Root, fs.FileInfoToDirEntry(rootInfo), nil); err != nil {
+		if errors.Is(err, filepath.SkipDir) {
+			return nil
+		}
+		return err
+	}
+	entries, err := os.ReadDir(opts.Root)
 	if err != nil {
-		return 0, nil, nil, err
+		return err
 	}
 
-	slices.Sort(assetNames)
-	slices.SortFunc(discoveredAssets, func(a, b discoveredAsset) int {
-		return strings.Compare(a.AssetPath, b.AssetPath)
-	})
-	return assetCatalogs, assetNames, discoveredAssets, nil
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	subtrees := make([]assetDiscovery, len(entries))
+	errs := make([]error, len(entries))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = filepath.WalkDir(filepath.Join(opts.Root, entry.Name()), subtrees[i].visit(ctx, opts))
+		}()
+	}
+	wg.Wait()
+	for i := range subtrees {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		d.catalogPaths = append(d.catalogPaths, subtrees[i].catalogPaths...)
+		d.assets = append(d.assets, subtrees[i].assets...)
+	}
+	return nil
 }
 
+// isEmptyAssetSet reports whether a file-backed asset set holds nothing but
+// its Contents.json. Hidden files such as .DS_Store are ignored.
+func isEmptyAssetSet(path string, assetType string) (bool, error) {
+	if assetType == "colorset" {
+		return false, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == "Contents.json" || strings.HasPrefix(name, ".") {
+			continue
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// catalogPathForAsset returns the enclosing .xcassets path of assetPath, or
+// "" when it is not inside a catalog. Both / and \ are treated as separators
+// so mixed-separator paths resolve the same way on every platform.
 func catalogPathForAsset(assetPath string) string {
-	marker := ".xcassets" + string(filepath.Separator)
-	idx := strings.Index(assetPath, marker)
-	if idx < 0 {
-		if strings.HasSuffix(assetPath, ".xcassets") {
-			return assetPath
+	const catalogExt = ".xcassets"
+	for offset := 0; ; {
+		idx := strings.Index(assetPath[offset:], catalogExt)
+		if idx < 0 {
+			return ""
 		}
-		return ""
+		end := offset + idx + len(catalogExt)
+		if end == len(assetPath) || isPathSeparator(assetPath[end]) {
+			return assetPath[:end]
+		}
+		offset = end
 	}
-	return assetPath[:idx+len(".xcassets")]
 }
 
-func collectUsedAssets(root string, include []string, exclude []string, discoveredAssets []discoveredAsset, workers int) (map[string]struct{}, error) {
+// isInsideAssetCatalog reports whether path lies below an .xcassets directory.
+func isInsideAssetCatalog(path string) bool {
+	catalogPath := catalogPathForAsset(path)
+	return catalogPath != "" && len(catalogPath) < len(path)
+}
+
+func isPathSeparator(c byte) bool {
+	return c == '/' || c == '\\'
+}
+
+// splitPathSegments splits path on both / and \, dropping empty segments.
+func splitPathSegments(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '\\'
+	})
+}
+
+// collectedUsage is what the usage pass found across all sources.
+type collectedUsage struct {
+	// scopes maps used asset set paths to the scopes referencing them.
+	scopes     map[string]usageScope
+	references map[string][]Reference
+	// dynamicMatches holds asset paths matched by interpolated-name families
+	// whether or not Options.DynamicNames counts them as used.
+	dynamicMatches  map[string]struct{}
+	ruleMatchCounts map[string]int
+	typeMismatches  []TypeMismatch
+}
+
+func collectUsedAssets(ctx context.Context, opts Options, discoveredAssets []discoveredAsset, workers int, profile *Profile) (collectedUsage, error) {
+	root, include, exclude := opts.Root, opts.Include, opts.Exclude
 	fileCh := make(chan string, workers*2)
 	errCh := make(chan error, 1)
-	usedSet := make(map[string]struct{}, 128)
+	usedSet := make(map[string]usageScope, 128)
+	referencesByPath := make(map[string][]Reference)
+	dynamicMatches := make(map[string]struct{})
+	// ruleMatchCounts counts resolved references per rule when
+	// Options.RuleStats is set.
+	var ruleMatchCounts map[string]int
+	if opts.RuleStats {
+		ruleMatchCounts = make(map[string]int)
+	}
+	// odrRequests maps On-Demand Resources tags requested in source to the
+	// requesting references.
+	odrRequests := make(map[string][]odrRequest)
+	// typeMismatches holds typed references that only match a same-named
+	// asset of another type, keyed by source, name and requested type.
+	typeMismatches := make(map[string]TypeMismatch)
 	var usedMu sync.Mutex
 	assetPathsByName := make(map[string][]discoveredAsset, len(discoveredAssets))
 	assetPathsByTypeAndName := make(map[string][]discoveredAsset, len(discoveredAssets))
@@ -246,34 +968,202 @@ func collectUsedAssets(root string, include []string, exclude []string, discover
 		assetPathsByTypeAndName[typeKey] = append(assetPathsByTypeAndName[typeKey], asset)
 	}
 	swiftResourceCandidates := buildSwiftResourceCandidateIndex(discoveredAssets)
-	swiftResourceLabelAssetTypes, swiftResourceLabelPatterns, swiftSourceContents, err := collectSwiftResourceArgumentLabelAssetTypes(root, include, exclude)
+	keyPathAccessorRe := compileKeyPathAccessorRe(opts.KeyPathAccessors)
+	ibAttributeRe := compileIBAttributeRe(opts.IBAttributes)
+	objcColorSelectorRe := compileObjCColorSelectorRe(opts.ObjCColorSelectors)
+	labelStart := time.Now()
+	swiftResourceParams, swiftSourceContents, err := collectSwiftResourceArgumentLabelAssetTypes(ctx, opts)
 	if err != nil {
-		return nil, err
+		return collectedUsage{}, err
 	}
+	profile.LabelCollection = time.Since(labelStart)
+	profile.LabelFiles = len(swiftSourceContents)
+	usageStart := time.Now()
 
-	markUsed := func(sourcePath string, name string, assetType string) {
-		var (
-			candidates []discoveredAsset
-			ok         bool
-		)
-		if assetType != "" {
-			candidates, ok = assetPathsByTypeAndName[sourceAssetTypeKey(name, assetType)]
-		} else {
-			candidates, ok = assetPathsByName[name]
-		}
-		if !ok || len(candidates) == 0 {
-			return
-		}
-		selected := selectClosestAssets(sourcePath, candidates)
+	recordUsed := func(sourcePath string, scope usageScope, ref sourceAssetReference, selected []discoveredAsset) {
 		if len(selected) == 0 {
 			return
 		}
 		usedMu.Lock()
+		if ruleMatchCounts != nil {
+			ruleMatchCounts[ref.Rule]++
+		}
 		for _, asset := range selected {
-			usedSet[asset.AssetPath] = struct{}{}
+			usedSet[asset.AssetPath] |= scope
+			if opts.TrackReferences {
+				referencesByPath[asset.AssetPath] = append(referencesByPath[asset.AssetPath], Reference{
+					Source: sourcePath,
+					Rule:   ref.Rule,
+					Text:   strings.Join(strings.Fields(ref.Text), " "),
+				})
+			}
 		}
 		usedMu.Unlock()
 	}
+	candidatesFor := func(ref sourceAssetReference) []discoveredAsset {
+		var candidates []discoveredAsset
+		switch ref.AssetType {
+		case "":
+			candidates = assetPathsByName[ref.Name]
+		case "imageset":
+			// Named image lookups also resolve custom symbol images.
+			candidates = slices.Concat(
+				assetPathsByTypeAndName[sourceAssetTypeKey(ref.Name, "imageset")],
+				assetPathsByTypeAndName[sourceAssetTypeKey(ref.Name, "symbolset")],
+			)
+		default:
+			candidates = assetPathsByTypeAndName[sourceAssetTypeKey(ref.Name, ref.AssetType)]
+		}
+		return candidates
+	}
+	markUsed := func(sourcePath string, scope usageScope, ref sourceAssetReference) {
+		candidates := candidatesFor(ref)
+		if len(candidates) == 0 {
+			if ref.AssetType != "" && len(assetPathsByName[ref.Name]) > 0 {
+				mismatch := TypeMismatch{Name: ref.Name, RequestedType: ref.AssetType, ExistingTypes: assetTypesOf(assetPathsByName[ref.Name]), Source: sourcePath, Rule: ref.Rule}
+				usedMu.Lock()
+				typeMismatches[sourcePath+"\x00"+sourceAssetTypeKey(ref.Name, ref.AssetType)] = mismatch
+				usedMu.Unlock()
+			}
+			return
+		}
+		recordUsed(sourcePath, scope, ref, selectClosestAssets(sourcePath, candidates))
+	}
+
+	scanContent := func(path string, ext string, scope usageScope, content string) {
+		switch ext {
+		case ".storyboard", ".xib":
+			for _, ref := range extractIBAssetReferences(content) {
+				markUsed(path, scope, ref)
+			}
+			for _, ref := range extractIBCustomAttributeReferences(content, ibAttributeRe, opts.IBAttributes) {
+				markUsed(path, scope, ref)
+			}
+			if opts.IBDesignable {
+				for _, ref := range extractIBRuntimeAttributeReferences(content, swiftResourceParams.inspectableImages) {
+					markUsed(path, scope, ref)
+				}
+			}
+		case ".plist", ".xcconfig", ".pbxproj":
+			for _, ref := range extractAppIconNameReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		case ".md", ".tutorial":
+			for _, ref := range extractDocCImageReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		case ".html", ".htm":
+			for _, ref := range extractHTMLImageReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		default:
+			for _, ref := range extractExplicitSourceAssetReferences(content, swiftResourceParams) {
+				markUsed(path, scope, ref)
+			}
+			if _, isObjC := objcExtensions[ext]; isObjC {
+				for _, ref := range extractObjCColorSelectorReferences(content, objcColorSelectorRe) {
+					markUsed(path, scope, ref)
+				}
+			}
+			if requests := extractODRRequests(path, scope, content); len(requests) > 0 {
+				usedMu.Lock()
+				for _, request := range requests {
+					odrRequests[request.Tag] = append(odrRequests[request.Tag], request)
+				}
+				usedMu.Unlock()
+			}
+		}
+
+		if ext == ".swift" && opts.BundleResources {
+			for _, ref := range extractSwiftBundleResourceReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		}
+
+		if ext == ".swift" && opts.Defaults {
+			for _, ref := range extractSwiftDefaultsReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		}
+
+		if ext == ".swift" && opts.LocalizedKeys {
+			for _, ref := range extractSwiftLocalizedKeyReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		}
+
+		if ext == ".swift" && opts.AttributedMarkdown {
+			for _, ref := range extractSwiftMarkdownImageReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		}
+
+		if ext == ".swift" && opts.LocalizedNames {
+			for _, ref := range extractSwiftLocalizedImageNameReferences(content) {
+				markUsed(path, scope, ref)
+			}
+		}
+
+		if ext == ".swift" {
+			for _, family := range extractSwiftInterpolatedAssetNameFamilies(content) {
+				for _, name := range family.matchingNames(discoveredAssets) {
+					ref := sourceAssetReference{
+						Name:      name,
+						AssetType: family.AssetType,
+						Rule:      "swift-interpolated-name",
+						Text:      family.Text,
+					}
+					usedMu.Lock()
+					for _, asset := range candidatesFor(ref) {
+						dynamicMatches[asset.AssetPath] = struct{}{}
+					}
+					usedMu.Unlock()
+					if opts.DynamicNames {
+						markUsed(path, scope, ref)
+					}
+				}
+			}
+		}
+
+		if ext == ".swift" {
+			for _, ref := range extractSwiftTypedResourceIdentifiers(content) {
+				matchedAssets, ok := swiftResourceCandidates[ref.Name]
+				if !ok {
+					continue
+				}
+				recordUsed(path, scope, ref, selectSwiftResourceAssets(path, ref.Name, matchedAssets))
+			}
+			for _, ref := range extractSwiftResourceIdentifiers(content) {
+				matchedAssets, ok := swiftResourceCandidates[ref.Name]
+				if !ok {
+					continue
+				}
+				recordUsed(path, scope, ref, selectSwiftResourceAssets(path, ref.Name, matchedAssets))
+			}
+			for _, ref := range extractSwiftGeneratedAccessorReferences(content, swiftResourceParams.accessors) {
+				markUsed(path, scope, ref)
+			}
+			for _, ref := range extractSwiftImageNameConstantReferences(content, swiftResourceParams.imageNames) {
+				markUsed(path, scope, ref)
+			}
+			for _, ref := range extractSwiftSDKImageArgumentReferences(content) {
+				matchedAssets := slices.DeleteFunc(slices.Clone(swiftResourceCandidates[ref.Name]), func(asset discoveredAsset) bool {
+					return asset.AssetType != "imageset" && asset.AssetType != "symbolset"
+				})
+				if len(matchedAssets) == 0 {
+					continue
+				}
+				recordUsed(path, scope, ref, selectSwiftResourceAssets(path, ref.Name, matchedAssets))
+			}
+			for _, ref := range extractSwiftKeyPathAccessorReferences(content, keyPathAccessorRe) {
+				matchedAssets, ok := swiftResourceCandidates[ref.Name]
+				if !ok {
+					continue
+				}
+				recordUsed(path, scope, ref, selectSwiftResourceAssets(path, ref.Name, matchedAssets))
+			}
+		}
+	}
 
 	var wg sync.WaitGroup
 	for i := 0; i < workers; i++ {
@@ -281,12 +1171,23 @@ func collectUsedAssets(root string, include []string, exclude []string, discover
 		go func() {
 			defer wg.Done()
 			for path := range fileCh {
+				// Keep draining after cancellation so the walk never blocks.
+				if ctx.Err() != nil {
+					continue
+				}
+				if opts.usageFileHook != nil {
+					opts.usageFileHook(path)
+				}
 				ext := strings.ToLower(filepath.Ext(path))
+				scope := sourceUsageScope(root, path)
 				content, ok := swiftSourceContents[path]
 				if !ok {
 					var err error
 					content, err = osReadFile(path)
 					if err != nil {
+						if _, isBuildConfig := buildConfigExtensions[ext]; isBuildConfig && errors.Is(err, errInvalidUTF8) {
+							continue
+						}
 						select {
 						case errCh <- err:
 						default:
@@ -294,41 +1195,27 @@ func collectUsedAssets(root string, include []string, exclude []string, discover
 						continue
 					}
 				}
-				switch ext {
-				case ".storyboard", ".xib":
-					for _, ref := range extractIBAssetReferences(content) {
-						markUsed(path, ref.Name, ref.AssetType)
-					}
-				default:
-					for _, ref := range extractExplicitSourceAssetReferences(content, swiftResourceLabelAssetTypes, swiftResourceLabelPatterns) {
-						markUsed(path, ref.Name, ref.AssetType)
-					}
+				if ext == ".swift" && isXcodeGeneratedAssetSymbols(content) {
+					// Generated symbol definitions name every asset; only
+					// their uses elsewhere count.
+					continue
 				}
-
 				if ext == ".swift" {
-					for _, identifier := range extractSwiftTypedResourceIdentifiers(content) {
-						matchedAssets, ok := swiftResourceCandidates[identifier]
-						if !ok {
-							continue
-						}
-						usedMu.Lock()
-						for _, asset := range selectClosestAssets(path, matchedAssets) {
-							usedSet[asset.AssetPath] = struct{}{}
-						}
-						usedMu.Unlock()
+					content = foldSwiftStringLiteralConcatenations(blankSwiftMultilineStrings(content))
+					if opts.ActiveConfig != "" {
+						content = blankInactiveSwiftConditionalBlocks(content, opts.ActiveConfig)
 					}
-					for _, identifier := range extractSwiftResourceIdentifiers(content) {
-						matchedAssets, ok := swiftResourceCandidates[identifier]
-						if !ok {
-							continue
-						}
-						usedMu.Lock()
-						for _, asset := range selectClosestAssets(path, matchedAssets) {
-							usedSet[asset.AssetPath] = struct{}{}
+					var previews string
+					content, previews = splitSwiftPreviewBlocks(content)
+					if previews != "" {
+						previewScope := usageScopePreview
+						if scope == usageScopeTest {
+							previewScope = usageScopeTest
 						}
-						usedMu.Unlock()
+						scanContent(path, ext, previewScope, previews)
 					}
 				}
+				scanContent(path, ext, scope, content)
 			}
 		}()
 	}
@@ -337,15 +1224,15 @@ func collectUsedAssets(root string, include []string, exclude []string, discover
 		if err != nil {
 			return err
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if d.IsDir() {
-			if d.Name() == ".git" {
-				return filepath.SkipDir
-			}
 			rel, relErr := filepath.Rel(root, path)
 			if relErr != nil {
 				return relErr
 			}
-			if matchesAny(rel, exclude) {
+			if isSkippedHiddenDir(rel, d.Name(), opts.IncludeHidden) || exceedsMaxDepth(rel, opts.MaxDepth) || matchesAny(rel, exclude) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -361,97 +1248,1062 @@ func collectUsedAssets(root string, include []string, exclude []string, discover
 		if len(include) > 0 && !matchesAny(rel, include) {
 			return nil
 		}
-		if strings.Contains(path, ".xcassets"+string(filepath.Separator)) {
-			return nil
+		if isInsideAssetCatalog(path) || matchesAny(rel, opts.Generated) {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if _, ok := sourceExtensions[ext]; !ok {
+			_, isDocC := doccExtensions[ext]
+			_, isHTML := htmlExtensions[ext]
+			if !(isDocC && opts.DocC) && !(isHTML && opts.HTML) {
+				return nil
+			}
+		}
+
+		profile.SourceFiles++
+		fileCh <- path
+		return nil
+	})
+	close(fileCh)
+	wg.Wait()
+	profile.UsageDetection = time.Since(usageStart)
+
+	if err := ctx.Err(); err != nil {
+		return collectedUsage{}, err
+	}
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return collectedUsage{}, err
+		}
+	default:
+	}
+
+	if walkErr != nil {
+		return collectedUsage{}, walkErr
+	}
+	if opts.ODRTagsUsed || len(odrRequests) > 0 {
+		for _, asset := range discoveredAssets {
+			tags := readODRTags(asset.AssetPath)
+			if len(tags) == 0 {
+				continue
+			}
+			selected := []discoveredAsset{asset}
+			if opts.ODRTagsUsed {
+				ref := sourceAssetReference{Name: asset.Name, AssetType: asset.AssetType, Rule: "odr-tagged-asset", Text: "on-demand-resource-tags: " + strings.Join(tags, ", ")}
+				recordUsed(filepath.Join(asset.AssetPath, "Contents.json"), usageScopeProduction, ref, selected)
+			}
+			for _, tag := range tags {
+				for _, request := range odrRequests[tag] {
+					ref := sourceAssetReference{Name: asset.Name, AssetType: asset.AssetType, Rule: "odr-resource-request", Text: request.Text}
+					recordUsed(request.Source, request.Scope, ref, selected)
+				}
+			}
+		}
+	}
+	return collectedUsage{
+		scopes:          usedSet,
+		references:      referencesByPath,
+		dynamicMatches:  dynamicMatches,
+		ruleMatchCounts: ruleMatchCounts,
+		typeMismatches:  sortTypeMismatches(typeMismatches),
+	}, nil
+}
+
+// assetTypesOf returns the sorted distinct asset types of assets.
+func assetTypesOf(assets []discoveredAsset) []string {
+	types := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		types = append(types, asset.AssetType)
+	}
+	slices.Sort(types)
+	return slices.Compact(types)
+}
+
+func sortTypeMismatches(byKey map[string]TypeMismatch) []TypeMismatch {
+	mismatches := slices.Collect(maps.Values(byKey))
+	slices.SortFunc(mismatches, func(a, b TypeMismatch) int {
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RequestedType, b.RequestedType)
+	})
+	return mismatches
+}
+
+// odrRequest is an On-Demand Resources tag requested through
+// NSBundleResourceRequest.
+type odrRequest struct {
+	Tag    string
+	Source string
+	Scope  usageScope
+	Text   string
+}
+
+// extractODRRequests returns the tags named by Swift
+// NSBundleResourceRequest(tags:) and Objective-C initWithTags: calls.
+func extractODRRequests(path string, scope usageScope, content string) []odrRequest {
+	var out []odrRequest
+	for _, re := range []*regexp.Regexp{swiftODRRequestRe, objcODRRequestRe} {
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			for _, tag := range odrTagLiteralRe.FindAllStringSubmatch(m[1], -1) {
+				out = append(out, odrRequest{Tag: tag[1], Source: path, Scope: scope, Text: m[0]})
+			}
+		}
+	}
+	return out
+}
+
+// readODRTags returns the On-Demand Resources tags in the Contents.json of
+// the asset set at assetPath. A missing or malformed file has no tags.
+func readODRTags(assetPath string) []string {
+	data, err := os.ReadFile(filepath.Join(assetPath, "Contents.json"))
+	if err != nil {
+		return nil
+	}
+	var contents struct {
+		Properties struct {
+			Tags []string `json:"on-demand-resource-tags"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil
+	}
+	return contents.Properties.Tags
+}
+
+func sourceUsageScope(root string, path string) usageScope {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return usageScopeProduction
+	}
+	if matchesAny(rel, testSourcePatterns) {
+		return usageScopeTest
+	}
+	return usageScopeProduction
+}
+
+func extractIBAssetReferences(content string) []sourceAssetReference {
+	imageStateMatches := ibImageStateRefRe.FindAllStringSubmatch(content, -1)
+	namedTagMatches := ibNamedAssetTagRefRe.FindAllStringSubmatch(content, -1)
+	if len(imageStateMatches) == 0 && len(namedTagMatches) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{})
+	out := make([]sourceAssetReference, 0, len(imageStateMatches)+len(namedTagMatches))
+	appendMatches := func(matches [][]string, typeIndex int, defaultAssetType string, rule string) {
+		for _, m := range matches {
+			if len(m) < 2 || len(m) <= typeIndex {
+				continue
+			}
+			assetType := defaultAssetType
+			if typeIndex >= 0 {
+				assetType = ibTagToAssetType(strings.TrimSpace(m[typeIndex]))
+			}
+			name := strings.TrimSpace(m[len(m)-1])
+			if name == "" {
+				continue
+			}
+			key := sourceAssetTypeKey(name, assetType)
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, sourceAssetReference{Name: name, AssetType: assetType, Rule: rule, Text: m[0]})
+		}
+	}
+	appendMatches(imageStateMatches, -1, "imageset", "ib-image-attribute")
+	appendMatches(namedTagMatches, 1, "", "ib-named-asset")
+	return out
+}
+
+// compileIBAttributeRe matches the configured Interface Builder attributes
+// and captures the attribute name and its value; it returns nil when none
+// are configured.
+func compileIBAttributeRe(attributes map[string]string) *regexp.Regexp {
+	if len(attributes) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(attributes))
+	for _, name := range slices.Sorted(maps.Keys(attributes)) {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return regexp.MustCompile(`(?:^|\s)(` + strings.Join(quoted, "|") + `)\s*=\s*"([^"\\\n\r]+)"`)
+}
+
+// extractIBCustomAttributeReferences returns references for the configured
+// attributes matched by re, typed by attributes; a nil re matches nothing.
+func extractIBCustomAttributeReferences(content string, re *regexp.Regexp, attributes map[string]string) []sourceAssetReference {
+	if re == nil {
+		return nil
+	}
+	var out []sourceAssetReference
+	for _, m := range re.FindAllStringSubmatch(content, -1) {
+		name := strings.TrimSpace(m[2])
+		if name == "" {
+			continue
+		}
+		out = append(out, sourceAssetReference{Name: name, AssetType: attributes[m[1]], Rule: "ib-custom-attribute", Text: strings.TrimSpace(m[0])})
+	}
+	return out
+}
+
+// extractIBRuntimeAttributeReferences returns image set references from
+// user-defined runtime attributes: image-typed values, and string values set
+// on a key path listed in inspectableImages.
+func extractIBRuntimeAttributeReferences(content string, inspectableImages map[string]struct{}) []sourceAssetReference {
+	var out []sourceAssetReference
+	for _, tag := range ibRuntimeAttributeRe.FindAllString(content, -1) {
+		value := ibRuntimeAttributeValueRe.FindStringSubmatch(tag)
+		attrType := ibRuntimeAttributeTypeRe.FindStringSubmatch(tag)
+		if value == nil || attrType == nil {
+			continue
+		}
+		name := strings.TrimSpace(value[1])
+		if name == "" {
+			continue
+		}
+		switch attrType[1] {
+		case "image":
+			out = append(out, sourceAssetReference{Name: name, AssetType: "imageset", Rule: "ib-runtime-attribute-image", Text: tag})
+		case "string":
+			keyPath := ibRuntimeAttributeKeyPathRe.FindStringSubmatch(tag)
+			if keyPath == nil {
+				continue
+			}
+			if _, ok := inspectableImages[keyPath[1]]; ok {
+				out = append(out, sourceAssetReference{Name: name, AssetType: "imageset", Rule: "ib-inspectable-string", Text: tag})
+			}
+		}
+	}
+	return out
+}
+
+// extractAppIconNameReferences returns app icon set names configured through
+// Info.plist CFBundleIconName entries or asset catalog compiler build settings.
+func extractAppIconNameReferences(content string) []sourceAssetReference {
+	seen := make(map[string]struct{})
+	out := make([]sourceAssetReference, 0, 4)
+	appendName := func(name string, rule string, text string) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		if _, exists := seen[name]; exists {
+			return
+		}
+		seen[name] = struct{}{}
+		out = append(out, sourceAssetReference{Name: name, AssetType: "appiconset", Rule: rule, Text: text})
+	}
+
+	for _, m := range plistIconNameRefRe.FindAllStringSubmatch(content, -1) {
+		appendName(m[1], "plist-icon-name", m[0])
+	}
+	for _, m := range buildSettingAppIconNameRe.FindAllStringSubmatch(content, -1) {
+		appendName(m[1], "build-setting-appicon-name", m[0])
+	}
+	for _, m := range buildSettingAlternateAppIconNamesRe.FindAllStringSubmatch(content, -1) {
+		for _, name := range strings.Fields(m[1]) {
+			appendName(name, "build-setting-alternate-appicon-names", m[0])
+		}
+	}
+	return out
+}
+
+func ibTagToAssetType(tag string) string {
+	switch strings.ToLower(strings.TrimSpace(tag)) {
+	case "image":
+		return "imageset"
+	case "color":
+		return "colorset"
+	default:
+		return ""
+	}
+}
+
+// blankSwiftMultilineStrings replaces the bodies of """ multiline string
+// literals with spaces, keeping line breaks and offsets, so code-like text
+// they hold as data, such as HTML templates, is never matched. Interpolated
+// segments are blanked as well. Delimiters are found by tokenizing the
+// source, so a """ inside a comment or single-line string never opens a
+// literal, an escaped \""" never closes one, and a raw #""" literal only
+// closes at """#. An unterminated literal is left untouched.
+func blankSwiftMultilineStrings(content string) string {
+	if !strings.Contains(content, `"""`) {
+		return content
+	}
+	var out []byte
+	for i := 0; i < len(content); {
+		switch {
+		case strings.HasPrefix(content[i:], "//"):
+			i = skipSwiftLineComment(content, i)
+		case strings.HasPrefix(content[i:], "/*"):
+			i = skipSwiftBlockComment(content, i)
+		case content[i] == '"' || content[i] == '#':
+			hashes := 0
+			for i+hashes < len(content) && content[i+hashes] == '#' {
+				hashes++
+			}
+			quote := i + hashes
+			if quote >= len(content) || content[quote] != '"' {
+				i = quote
+				continue
+			}
+			if !strings.HasPrefix(content[quote:], `"""`) {
+				i = skipSwiftStringBody(content, quote+1, `"`+strings.Repeat("#", hashes), false)
+				continue
+			}
+			bodyStart := quote + len(`"""`)
+			closing := `"""` + strings.Repeat("#", hashes)
+			end := skipSwiftStringBody(content, bodyStart, closing, true)
+			if end < 0 {
+				// Unterminated: leave the rest untouched.
+				i = len(content)
+				continue
+			}
+			if out == nil {
+				out = []byte(content)
+			}
+			for k := bodyStart; k < end-len(closing); k++ {
+				if out[k] != '\n' && out[k] != '\r' {
+					out[k] = ' '
+				}
+			}
+			i = end
+		default:
+			i++
+		}
+	}
+	if out == nil {
+		return content
+	}
+	return string(out)
+}
+
+// skipSwiftLineComment returns the index of the line break ending the //
+// comment that starts at start, or len(content).
+func skipSwiftLineComment(content string, start int) int {
+	if end := strings.IndexAny(content[start:], "\r\n"); end >= 0 {
+		return start + end
+	}
+	return len(content)
+}
+
+// skipSwiftBlockComment returns the index just past the /* comment that starts
+// at start, honoring Swift's nested block comments, or len(content) when it
+// never closes.
+func skipSwiftBlockComment(content string, start int) int {
+	depth := 0
+	for i := start; i < len(content)-1; {
+		switch {
+		case strings.HasPrefix(content[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(content[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(content)
+}
+
+// skipSwiftStringBody scans the string literal body starting at start for
+// closing, the quote(s) plus raw-string hashes that end it, and returns the
+// index just past it. Backslash escapes apply only when followed by the raw
+// hashes, and \( interpolations are skipped with balanced parentheses.
+// Single-line bodies stop at a line break; the index of the break is then
+// returned, as for any unterminated single-line literal. Multiline bodies
+// that never close return -1.
+func skipSwiftStringBody(content string, start int, closing string, multiline bool) int {
+	hashes := strings.Repeat("#", strings.Count(closing, "#"))
+	escape := `\` + hashes
+	for i := start; i < len(content); {
+		switch {
+		case strings.HasPrefix(content[i:], closing):
+			return i + len(closing)
+		case !multiline && (content[i] == '\n' || content[i] == '\r'):
+			return i
+		case strings.HasPrefix(content[i:], escape+"("):
+			i = skipSwiftInterpolation(content, i+len(escape)+1)
+		case strings.HasPrefix(content[i:], escape) && i+len(escape) < len(content):
+			i += len(escape) + 1
+		default:
+			i++
+		}
+	}
+	if multiline {
+		return -1
+	}
+	return len(content)
+}
+
+// skipSwiftInterpolation returns the index just past the parenthesis closing
+// the \( interpolation whose expression starts at start, skipping nested
+// single-line string literals, or len(content) when it never closes.
+func skipSwiftInterpolation(content string, start int) int {
+	depth := 1
+	for i := start; i < len(content); {
+		switch content[i] {
+		case '"':
+			i = skipSwiftStringBody(content, i+1, `"`, false)
+		case '(':
+			depth++
+			i++
+		case ')':
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		case '\n', '\r':
+			return i
+		default:
+			i++
+		}
+	}
+	return len(content)
+}
+
+// swiftConditionState is a Swift #if condition evaluated against the active
+// configuration. Conditions the scan cannot decide, such as os(iOS) or
+// canImport(UIKit), are unknown.
+type swiftConditionState int
+
+const (
+	swiftConditionUnknown swiftConditionState = iota
+	swiftConditionTrue
+	swiftConditionFalse
+)
+
+// blankInactiveSwiftConditionalBlocks replaces the lines of #if, #elseif and
+// #else branches that are not compiled when activeConfig is the only custom
+// compilation condition with spaces, keeping line breaks and offsets. Every
+// branch after an unknown condition is kept, and unbalanced directives are
+// ignored.
+func blankInactiveSwiftConditionalBlocks(content string, activeConfig string) string {
+	if !strings.Contains(content, "#if") {
+		return content
+	}
+	type branch struct {
+		parentActive bool
+		// taken is set once a branch whose condition is true was seen, so
+		// the following branches cannot be compiled.
+		taken bool
+	}
+	var stack []branch
+	active := true
+	out := []byte(content)
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		m := swiftConditionalDirectiveRe.FindStringSubmatch(line)
+		switch {
+		case m != nil && m[1] == "if":
+			state := evaluateSwiftCondition(m[2], activeConfig)
+			stack = append(stack, branch{parentActive: active, taken: state == swiftConditionTrue})
+			active = active && state != swiftConditionFalse
+		case m != nil && len(stack) > 0 && m[1] == "elseif":
+			top := &stack[len(stack)-1]
+			state := evaluateSwiftCondition(m[2], activeConfig)
+			active = top.parentActive && !top.taken && state != swiftConditionFalse
+			top.taken = top.taken || state == swiftConditionTrue
+		case m != nil && len(stack) > 0 && m[1] == "else":
+			top := &stack[len(stack)-1]
+			active = top.parentActive && !top.taken
+			top.taken = true
+		case m != nil && len(stack) > 0 && m[1] == "endif":
+			active = stack[len(stack)-1].parentActive
+			stack = stack[:len(stack)-1]
+		case !active:
+			for i := offset; i < offset+len(line); i++ {
+				if out[i] != '\n' && out[i] != '\r' {
+					out[i] = ' '
+				}
+			}
+		}
+		offset += len(line)
+	}
+	return string(out)
+}
+
+// evaluateSwiftCondition evaluates a #if condition built from compilation
+// condition names, true/false, !, &&, || and parentheses. Only activeConfig
+// is defined; platform checks such as os(iOS) are unknown, as is anything
+// that does not parse.
+func evaluateSwiftCondition(condition string, activeConfig string) swiftConditionState {
+	if comment := strings.Index(condition, "//"); comment >= 0 {
+		condition = condition[:comment]
+	}
+	parser := swiftConditionParser{input: condition, activeConfig: activeConfig}
+	state, ok := parser.parseOr()
+	parser.skipSpace()
+	if !ok || parser.pos != len(parser.input) {
+		return swiftConditionUnknown
+	}
+	return state
+}
+
+type swiftConditionParser struct {
+	input        string
+	pos          int
+	activeConfig string
+}
+
+func (p *swiftConditionParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t' || p.input[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+func (p *swiftConditionParser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *swiftConditionParser) parseOr() (swiftConditionState, bool) {
+	state, ok := p.parseAnd()
+	for ok && p.consume("||") {
+		var next swiftConditionState
+		next, ok = p.parseAnd()
+		switch {
+		case state == swiftConditionTrue || next == swiftConditionTrue:
+			state = swiftConditionTrue
+		case state == swiftConditionFalse && next == swiftConditionFalse:
+			state = swiftConditionFalse
+		default:
+			state = swiftConditionUnknown
+		}
+	}
+	return state, ok
+}
+
+func (p *swiftConditionParser) parseAnd() (swiftConditionState, bool) {
+	state, ok := p.parseUnary()
+	for ok && p.consume("&&") {
+		var next swiftConditionState
+		next, ok = p.parseUnary()
+		switch {
+		case state == swiftConditionFalse || next == swiftConditionFalse:
+			state = swiftConditionFalse
+		case state == swiftConditionTrue && next == swiftConditionTrue:
+			state = swiftConditionTrue
+		default:
+			state = swiftConditionUnknown
+		}
+	}
+	return state, ok
+}
+
+func (p *swiftConditionParser) parseUnary() (swiftConditionState, bool) {
+	if p.consume("!") {
+		state, ok := p.parseUnary()
+		switch state {
+		case swiftConditionTrue:
+			return swiftConditionFalse, ok
+		case swiftConditionFalse:
+			return swiftConditionTrue, ok
+		}
+		return state, ok
+	}
+	if p.consume("(") {
+		state, ok := p.parseOr()
+		return state, ok && p.consume(")")
+	}
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" {
+		return swiftConditionUnknown, false
+	}
+	if p.consume("(") {
+		// Platform conditions such as os(iOS) or swift(>=5.9) cannot be
+		// decided by a source scan.
+		closing := strings.IndexByte(p.input[p.pos:], ')')
+		if closing < 0 {
+			return swiftConditionUnknown, false
+		}
+		p.pos += closing + 1
+		return swiftConditionUnknown, true
+	}
+	switch name {
+	case "true", p.activeConfig:
+		return swiftConditionTrue, true
+	default:
+		return swiftConditionFalse, true
+	}
+}
+
+// isXcodeGeneratedAssetSymbols reports whether content is an asset symbol
+// file generated by Xcode, which defines ImageResource/ColorResource members
+// for every asset and is therefore definition-only.
+func isXcodeGeneratedAssetSymbols(content string) bool {
+	return strings.Contains(content, "import DeveloperToolsSupport") && xcodeAssetSymbolDefinitionRe.MatchString(content)
+}
+
+// foldSwiftStringLiteralConcatenations joins adjacent plain string literals
+// concatenated with +, so "hero" + "_dark" reads as "hero_dark". Literals
+// with escapes or interpolation are left untouched.
+func foldSwiftStringLiteralConcatenations(content string) string {
+	if !strings.Contains(content, "+") {
+		return content
+	}
+	for {
+		folded := swiftStringLiteralConcatRe.ReplaceAllString(content, `"$1$2"`)
+		if folded == content {
+			return content
+		}
+		content = folded
+	}
+}
+
+// extractDocCImageReferences returns image set references from DocC
+// @Image(source:) directives and markdown image links that name a resource.
+func extractDocCImageReferences(content string) []sourceAssetReference {
+	seen := make(map[string]struct{})
+	out := make([]sourceAssetReference, 0, 4)
+	appendMatches := func(re *regexp.Regexp, rule string) {
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			name := strings.TrimSpace(m[1])
+			if name == "" {
+				continue
+			}
+			if _, exists := seen[name]; exists {
+				continue
+			}
+			seen[name] = struct{}{}
+			out = append(out, sourceAssetReference{Name: name, AssetType: "imageset", Rule: rule, Text: m[0]})
+		}
+	}
+	appendMatches(doccImageDirectiveRefRe, "docc-image-directive")
+	appendMatches(markdownImageRefRe, "markdown-image")
+	return out
+}
+
+// extractHTMLImageReferences returns image set references for local <img src>
+// paths, reduced to their base name without extension or @Nx scale suffix.
+func extractHTMLImageReferences(content string) []sourceAssetReference {
+	seen := make(map[string]struct{})
+	out := make([]sourceAssetReference, 0, 4)
+	for _, m := range htmlImageSrcRefRe.FindAllStringSubmatch(content, -1) {
+		name, ok := localImageName(m[1])
+		if !ok {
+			continue
+		}
+		if _, exists := seen[name]; exists {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, sourceAssetReference{Name: name, AssetType: "imageset", Rule: "html-image-src", Text: m[0]})
+	}
+	return out
+}
+
+// extractSwiftMarkdownImageReferences returns image set references for
+// markdown image targets in Swift sources, e.g. the hero of
+// AttributedString(markdown: "![Hero](hero)"), reduced like <img src> paths.
+func extractSwiftMarkdownImageReferences(content string) []sourceAssetReference {
+	seen := make(map[string]struct{})
+	var out []sourceAssetReference
+	for _, m := range swiftMarkdownImageRefRe.FindAllStringSubmatch(content, -1) {
+		name, ok := localImageName(m[1])
+		if !ok {
+			continue
+		}
+		if _, exists := seen[name]; exists {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, sourceAssetReference{Name: name, AssetType: "imageset", Rule: "swift-markdown-image", Text: m[0]})
+	}
+	return out
+}
+
+// localImageName reduces a local image path to its base name without
+// extension or @Nx scale suffix. ok is false for URLs, data URIs and paths
+// that reduce to nothing.
+func localImageName(src string) (name string, ok bool) {
+	src = strings.TrimSpace(src)
+	if strings.Contains(src, "://") || strings.HasPrefix(src, "data:") {
+		return "", false
+	}
+	base := src[strings.LastIndexAny(src, `/\`)+1:]
+	name = imageScaleSuffixRe.ReplaceAllString(strings.TrimSuffix(base, filepath.Ext(base)), "")
+	return name, name != ""
+}
+
+// extractSwiftBundleResourceReferences returns untyped references for names
+// passed to Bundle forResource: lookups.
+func extractSwiftBundleResourceReferences(content string) []sourceAssetReference {
+	return extractUntypedReferences(content, swiftBundleResourceRefRe, "swift-bundle-resource")
+}
+
+// extractSwiftLocalizedKeyReferences returns untyped references for keys
+// passed to String(localized:).
+func extractSwiftLocalizedKeyReferences(content string) []sourceAssetReference {
+	return extractUntypedReferences(content, swiftLocalizedKeyRefRe, "swift-localized-key")
+}
+
+// extractSwiftLocalizedImageNameReferences returns image set references for
+// image name lookups whose name is an NSLocalizedString call, using the
+// localization key as the asset name.
+func extractSwiftLocalizedImageNameReferences(content string) []sourceAssetReference {
+	seen := make(map[string]struct{})
+	var refs []sourceAssetReference
+	for _, loc := range swiftLocalizedImageNameRefRe.FindAllStringIndex(content, -1) {
+		key, end, ok := readSwiftStringLiteral(content, loc[1])
+		if !ok {
+			continue
+		}
+		name := strings.TrimSpace(key)
+		if name == "" {
+			continue
+		}
+		if _, exists := seen[name]; exists {
+			continue
+		}
+		seen[name] = struct{}{}
+		refs = append(refs, sourceAssetReference{Name: name, AssetType: "imageset", Rule: "swift-localized-image-name", Text: content[loc[0]:end]})
+	}
+	return refs
+}
+
+// extractSwiftDefaultsReferences returns image set references for string
+// defaults of @AppStorage properties and UserDefaults register(defaults:)
+// dictionary values.
+func extractSwiftDefaultsReferences(content string) []sourceAssetReference {
+	seen := make(map[string]struct{})
+	var refs []sourceAssetReference
+	appendRef := func(name, rule, text string) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		if _, exists := seen[name]; exists {
+			return
+		}
+		seen[name] = struct{}{}
+		refs = append(refs, sourceAssetReference{Name: name, AssetType: "imageset", Rule: rule, Text: text})
+	}
+	for _, m := range swiftAppStorageDefaultRefRe.FindAllStringSubmatch(content, -1) {
+		appendRef(m[1], "swift-appstorage-default", m[0])
+	}
+	for _, m := range swiftRegisterDefaultsRe.FindAllStringSubmatch(content, -1) {
+		for _, value := range swiftDictionaryStringValueRe.FindAllStringSubmatch(m[1], -1) {
+			appendRef(value[1], "swift-registered-default", value[0])
+		}
+	}
+	return refs
+}
+
+// extractUntypedReferences returns one untyped reference per distinct name
+// captured by re, attributed to rule.
+func extractUntypedReferences(content string, re *regexp.Regexp, rule string) []sourceAssetReference {
+	matches := re.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(matches))
+	refs := make([]sourceAssetReference, 0, len(matches))
+	for _, m := range matches {
+		name := strings.TrimSpace(m[1])
+		if name == "" {
+			continue
+		}
+		if _, exists := seen[name]; exists {
+			continue
+		}
+		seen[name] = struct{}{}
+		refs = append(refs, sourceAssetReference{Name: name, Rule: rule, Text: m[0]})
+	}
+	return refs
+}
+
+// assetNameFamily describes the static parts of an interpolated asset name.
+// At least one of Prefix or Suffix is non-empty.
+type assetNameFamily struct {
+	Prefix    string
+	Suffix    string
+	AssetType string
+	// Text is the first source match that produced the family.
+	Text string
+}
+
+func (f assetNameFamily) matches(name string) bool {
+	// The interpolated part must contribute at least one character.
+	if len(name) <= len(f.Prefix)+len(f.Suffix) {
+		return false
+	}
+	return strings.HasPrefix(name, f.Prefix) && strings.HasSuffix(name, f.Suffix)
+}
+
+func (f assetNameFamily) matchingNames(discoveredAssets []discoveredAsset) []string {
+	seen := make(map[string]struct{})
+	out := make([]string, 0, 4)
+	for _, asset := range discoveredAssets {
+		if asset.AssetType != f.AssetType || !f.matches(asset.Name) {
+			continue
+		}
+		if _, ok := seen[asset.Name]; ok {
+			continue
+		}
+		seen[asset.Name] = struct{}{}
+		out = append(out, asset.Name)
+	}
+	return out
+}
+
+func extractSwiftInterpolatedAssetNameFamilies(content string) []assetNameFamily {
+	matches := swiftInterpolatedAssetNameRefRe.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	out := make([]assetNameFamily, 0, len(matches))
+	for _, m := range matches {
+		if len(m) < 3 {
+			continue
+		}
+		prefix, suffix, ok := splitSwiftInterpolatedLiteral(m[2])
+		if !ok || (prefix == "" && suffix == "") {
+			continue
+		}
+		assetType := "imageset"
+		if m[1] == "Color" {
+			assetType = "colorset"
+		}
+		key := assetType + "\x00" + prefix + "\x00" + suffix
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, assetNameFamily{Prefix: prefix, Suffix: suffix, AssetType: assetType, Text: m[0]})
+	}
+	return out
+}
+
+// splitSwiftInterpolatedLiteral returns the static text before the first
+// interpolation and after the last one. It reports false when an
+// interpolation is not closed within the literal.
+func splitSwiftInterpolatedLiteral(literal string) (string, string, bool) {
+	first := strings.Index(literal, `\(`)
+	if first < 0 {
+		return "", "", false
+	}
+
+	end := -1
+	for i := first; i < len(literal); i++ {
+		if !strings.HasPrefix(literal[i:], `\(`) {
+			continue
+		}
+		depth := 0
+		closed := false
+		for j := i + 1; j < len(literal); j++ {
+			switch literal[j] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				end = j + 1
+				i = j
+				closed = true
+				break
+			}
 		}
-
-		ext := strings.ToLower(filepath.Ext(path))
-		if _, ok := sourceExtensions[ext]; !ok {
-			return nil
+		if !closed {
+			return "", "", false
 		}
+	}
+	return literal[:first], literal[end:], true
+}
 
-		fileCh <- path
+// extractSwiftResourceIdentifiers returns references whose Name is the
+// generated resource identifier rather than the asset name.
+func extractSwiftResourceIdentifiers(content string) []sourceAssetReference {
+	matches := swiftResourceRefRe.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
 		return nil
-	})
-	close(fileCh)
-	wg.Wait()
+	}
 
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return nil, err
+	refs := make([]sourceAssetReference, 0, len(matches))
+	for _, m := range matches {
+		if len(m) < 2 || m[1] == "" {
+			continue
 		}
-	default:
+		refs = append(refs, sourceAssetReference{Name: m[1], Rule: "swift-resource-argument", Text: m[0]})
 	}
+	return refs
+}
 
-	if walkErr != nil {
-		return nil, walkErr
+// extractSwiftGeneratedAccessorReferences returns the asset names loaded by
+// generated accessors that content uses as `.member`, e.g. Asset.Icons.hero.
+func extractSwiftGeneratedAccessorReferences(content string, accessors map[string][]string) []sourceAssetReference {
+	if len(accessors) == 0 {
+		return nil
 	}
-	return usedSet, nil
+	seen := make(map[string]struct{})
+	var refs []sourceAssetReference
+	for _, m := range swiftEnumMemberRefRe.FindAllStringSubmatch(content, -1) {
+		for _, name := range accessors[m[1]] {
+			if _, exists := seen[name]; exists {
+				continue
+			}
+			seen[name] = struct{}{}
+			refs = append(refs, sourceAssetReference{Name: name, Rule: "swift-generated-accessor", Text: "." + m[1]})
+		}
+	}
+	return refs
 }
 
-func extractIBAssetReferences(content string) []sourceAssetReference {
-	imageStateMatches := ibImageStateRefRe.FindAllStringSubmatch(content, -1)
-	namedTagMatches := ibNamedAssetTagRefRe.FindAllStringSubmatch(content, -1)
-	if len(imageStateMatches) == 0 && len(namedTagMatches) == 0 {
+// extractSwiftImageNameConstantReferences returns the image names held by
+// NSImage.Name constants that content passes to NSImage(named:).
+func extractSwiftImageNameConstantReferences(content string, imageNames map[string][]string) []sourceAssetReference {
+	if len(imageNames) == 0 {
 		return nil
 	}
 	seen := make(map[string]struct{})
-	out := make([]sourceAssetReference, 0, len(imageStateMatches)+len(namedTagMatches))
-	appendMatches := func(matches [][]string, typeIndex int, defaultAssetType string) {
-		for _, m := range matches {
-			if len(m) < 2 || len(m) <= typeIndex {
+	var refs []sourceAssetReference
+	for _, m := range swiftImageNamedMemberRe.FindAllStringSubmatch(content, -1) {
+		for _, name := range imageNames[m[1]] {
+			if _, exists := seen[name]; exists {
 				continue
 			}
-			assetType := defaultAssetType
-			if typeIndex >= 0 {
-				assetType = ibTagToAssetType(strings.TrimSpace(m[typeIndex]))
-			}
-			name := strings.TrimSpace(m[len(m)-1])
-			if name == "" {
+			seen[name] = struct{}{}
+			refs = append(refs, sourceAssetReference{Name: name, AssetType: "imageset", Rule: "swift-image-name-constant", Text: m[0]})
+		}
+	}
+	return refs
+}
+
+// extractSwiftSDKImageArgumentReferences returns generated image identifiers
+// passed as bare `.member` values to the swiftSDKImageArguments labels. Only
+// top-level arguments of each call are considered, so labels inside nested
+// calls or closures do not match.
+func extractSwiftSDKImageArgumentReferences(content string) []sourceAssetReference {
+	var refs []sourceAssetReference
+	for _, m := range swiftSDKImageCallRe.FindAllStringSubmatchIndex(content, -1) {
+		callee := content[m[2]:m[3]]
+		args, ok := swiftTopLevelArguments(content[m[1]:])
+		if !ok {
+			continue
+		}
+		for _, arg := range swiftLabeledMemberArgumentRe.FindAllStringSubmatch(args, -1) {
+			if !slices.Contains(swiftSDKImageArguments[callee], arg[1]) {
 				continue
 			}
-			key := sourceAssetTypeKey(name, assetType)
-			if _, exists := seen[key]; exists {
-				continue
+			refs = append(refs, sourceAssetReference{
+				Name:      arg[2],
+				AssetType: "imageset",
+				Rule:      "swift-sdk-image-label",
+				Text:      strings.TrimSpace(strings.TrimPrefix(arg[0], ",")),
+			})
+		}
+	}
+	return refs
+}
+
+// swiftTopLevelArguments returns the argument list that starts right after
+// an opening parenthesis, with the contents of nested (), [] and {} groups
+// blanked out. It reports false when the list is not closed.
+func swiftTopLevelArguments(rest string) (string, bool) {
+	var b strings.Builder
+	depth := 0
+	for _, r := range rest {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return b.String(), true
 			}
-			seen[key] = struct{}{}
-			out = append(out, sourceAssetReference{Name: name, AssetType: assetType})
+			depth--
+			continue
+		}
+		if depth > 0 {
+			r = ' '
 		}
+		b.WriteRune(r)
 	}
-	appendMatches(imageStateMatches, -1, "imageset")
-	appendMatches(namedTagMatches, 1, "")
-	return out
+	return "", false
 }
 
-func ibTagToAssetType(tag string) string {
-	switch strings.ToLower(strings.TrimSpace(tag)) {
-	case "image":
-		return "imageset"
-	case "color":
-		return "colorset"
-	default:
-		return ""
+// compileKeyPathAccessorRe matches a key path member passed by subscript or
+// call to one of accessors, e.g. icons[\.home] or image(\.home). It returns
+// nil when accessors is empty.
+func compileKeyPathAccessorRe(accessors []string) *regexp.Regexp {
+	if len(accessors) == 0 {
+		return nil
 	}
+	quoted := make([]string, 0, len(accessors))
+	for _, accessor := range accessors {
+		quoted = append(quoted, regexp.QuoteMeta(accessor))
+	}
+	return regexp.MustCompile(`(?:^|[^A-Za-z0-9_])(?:` + strings.Join(quoted, "|") + `)\s*[\[(]\s*\\\.([A-Za-z_][A-Za-z0-9_]*)`)
 }
 
-func extractSwiftResourceIdentifiers(content string) []string {
-	matches := swiftResourceRefRe.FindAllStringSubmatch(content, -1)
-	if len(matches) == 0 {
+// compileObjCColorSelectorRe builds the pattern matching a message send of
+// one of selectors with a literal string argument, on any receiver and
+// across line breaks. It returns nil when selectors is empty.
+func compileObjCColorSelectorRe(selectors []string) *regexp.Regexp {
+	if len(selectors) == 0 {
 		return nil
 	}
+	quoted := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		quoted = append(quoted, regexp.QuoteMeta(selector))
+	}
+	return regexp.MustCompile(`(?:^|[^A-Za-z0-9_:])(?:` + strings.Join(quoted, "|") + `)\s*:\s*@"([^"\\\n\r]+)"`)
+}
 
-	identifiers := make([]string, 0, len(matches))
+// extractObjCColorSelectorReferences returns color set references for the
+// selector arguments matched by re; a nil re matches nothing.
+func extractObjCColorSelectorReferences(content string, re *regexp.Regexp) []sourceAssetReference {
+	if re == nil {
+		return nil
+	}
+	matches := re.FindAllStringSubmatch(content, -1)
+	refs := make([]sourceAssetReference, 0, len(matches))
 	for _, m := range matches {
-		if len(m) < 2 || m[1] == "" {
-			continue
-		}
-		identifiers = append(identifiers, m[1])
+		refs = append(refs, sourceAssetReference{Name: m[1], AssetType: "colorset", Rule: "objc-color-selector", Text: strings.TrimSpace(m[0])})
+	}
+	return refs
+}
+
+// extractSwiftKeyPathAccessorReferences returns resource identifier
+// references for key path members matched by re; a nil re matches nothing.
+func extractSwiftKeyPathAccessorReferences(content string, re *regexp.Regexp) []sourceAssetReference {
+	if re == nil {
+		return nil
 	}
-	return identifiers
+	matches := re.FindAllStringSubmatch(content, -1)
+	refs := make([]sourceAssetReference, 0, len(matches))
+	for _, m := range matches {
+		refs = append(refs, sourceAssetReference{Name: m[1], Rule: "swift-keypath-accessor", Text: strings.TrimSpace(m[0])})
+	}
+	return refs
 }
 
-func extractSwiftTypedResourceIdentifiers(content string) []string {
+// extractSwiftTypedResourceIdentifiers returns references whose Name is a
+// generated resource identifier assigned to ImageResource/ColorResource values.
+func extractSwiftTypedResourceIdentifiers(content string) []sourceAssetReference {
 	varMatches := swiftTypedResourceVarRe.FindAllStringSubmatch(content, -1)
 	initMatches := swiftTypedResourceVarInitRe.FindAllStringSubmatch(content, -1)
 	scalarVarMatches := swiftTypedResourceScalarVarRe.FindAllStringSubmatch(content, -1)
@@ -465,7 +2317,14 @@ func extractSwiftTypedResourceIdentifiers(content string) []string {
 	mergedMatches = append(mergedMatches, scalarVarMatches...)
 
 	seenIdentifiers := make(map[string]struct{})
-	identifiers := make([]string, 0, len(mergedMatches))
+	refs := make([]sourceAssetReference, 0, len(mergedMatches))
+	appendIdentifier := func(identifier string) {
+		if _, exists := seenIdentifiers[identifier]; exists {
+			return
+		}
+		seenIdentifiers[identifier] = struct{}{}
+		refs = append(refs, sourceAssetReference{Name: identifier, Rule: "swift-typed-resource", Text: "." + identifier})
+	}
 	patternsByVar := make(map[string]swiftEnumIdentifierPatterns)
 	for _, m := range mergedMatches {
 		if len(m) < 2 || strings.TrimSpace(m[1]) == "" {
@@ -478,16 +2337,14 @@ func extractSwiftTypedResourceIdentifiers(content string) []string {
 			patternsByVar[varName] = patterns
 		}
 		for _, identifier := range extractEnumIdentifiersForSwiftVar(content, patterns) {
-			if _, exists := seenIdentifiers[identifier]; exists {
-				continue
-			}
-			seenIdentifiers[identifier] = struct{}{}
-			identifiers = append(identifiers, identifier)
+			appendIdentifier(identifier)
 		}
 	}
 
 	for _, body := range resourceReturnBodies {
-		for _, m := range swiftReturnEnumMemberRe.FindAllStringSubmatch(body, -1) {
+		returns := swiftReturnEnumMemberRe.FindAllStringSubmatch(body, -1)
+		returns = append(returns, swiftSwitchArmEnumMemberRe.FindAllStringSubmatch(body, -1)...)
+		for _, m := range returns {
 			if len(m) < 2 {
 				continue
 			}
@@ -495,30 +2352,28 @@ func extractSwiftTypedResourceIdentifiers(content string) []string {
 			if identifier == "" {
 				continue
 			}
-			if _, exists := seenIdentifiers[identifier]; exists {
-				continue
-			}
-			seenIdentifiers[identifier] = struct{}{}
-			identifiers = append(identifiers, identifier)
+			appendIdentifier(identifier)
 		}
 	}
-	return identifiers
+	return refs
 }
 
 type swiftEnumIdentifierPatterns struct {
-	assign       *regexp.Regexp
-	append       *regexp.Regexp
-	insert       *regexp.Regexp
-	scalarAssign *regexp.Regexp
+	assign          *regexp.Regexp
+	append          *regexp.Regexp
+	insert          *regexp.Regexp
+	scalarAssign    *regexp.Regexp
+	subscriptAssign *regexp.Regexp
 }
 
 func buildSwiftEnumIdentifierPatterns(varName string) swiftEnumIdentifierPatterns {
 	quotedVarName := regexp.QuoteMeta(varName)
 	return swiftEnumIdentifierPatterns{
-		assign:       regexp.MustCompile(`\b` + quotedVarName + `(?:\s*:\s*[^=\n\r]+)?\s*=\s*\[([^\]]*)\]`),
-		append:       regexp.MustCompile(`\b` + quotedVarName + `\s*\.append\s*\(\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
-		insert:       regexp.MustCompile(`\b` + quotedVarName + `\s*\.insert\s*\(\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
-		scalarAssign: regexp.MustCompile(`\b` + quotedVarName + `(?:\s*:\s*[^=\n\r]+)?\s*=\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
+		assign:          regexp.MustCompile(`\b` + quotedVarName + `(?:\s*:\s*[^=\n\r]+)?\s*=\s*\[([^\]]*)\]`),
+		append:          regexp.MustCompile(`\b` + quotedVarName + `\s*\.append\s*\(\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
+		insert:          regexp.MustCompile(`\b` + quotedVarName + `\s*\.insert\s*\(\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
+		scalarAssign:    regexp.MustCompile(`\b` + quotedVarName + `(?:\s*:\s*[^=\n\r]+)?\s*=\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
+		subscriptAssign: regexp.MustCompile(`\b` + quotedVarName + `\s*\[[^\]\n\r]*\]\s*=\s*\.([A-Za-z_][A-Za-z0-9_]*)`),
 	}
 }
 
@@ -548,6 +2403,41 @@ func extractSwiftResourceReturnBodies(content string) []string {
 	return bodies
 }
 
+// splitSwiftPreviewBlocks blanks out the bodies of #Preview macros in
+// content, keeping offsets and line breaks, and returns them separately
+// joined by newlines so their references can be scoped to previews.
+func splitSwiftPreviewBlocks(content string) (string, string) {
+	matches := swiftPreviewMacroRe.FindAllStringIndex(content, -1)
+	if len(matches) == 0 {
+		return content, ""
+	}
+	rest := []byte(content)
+	var previews []string
+	next := 0
+	for _, match := range matches {
+		if match[0] < next {
+			continue
+		}
+		openBrace := strings.IndexByte(content[match[1]:], '{')
+		if openBrace < 0 {
+			break
+		}
+		openIdx := match[1] + openBrace
+		closeIdx := findMatchingBrace(content, openIdx)
+		if closeIdx < 0 {
+			break
+		}
+		previews = append(previews, content[openIdx+1:closeIdx])
+		for i := openIdx + 1; i < closeIdx; i++ {
+			if rest[i] != '\n' {
+				rest[i] = ' '
+			}
+		}
+		next = closeIdx + 1
+	}
+	return string(rest), strings.Join(previews, "\n")
+}
+
 func findMatchingBrace(content string, openIdx int) int {
 	if openIdx < 0 || openIdx >= len(content) || content[openIdx] != '{' {
 		return -1
@@ -588,7 +2478,9 @@ func extractEnumIdentifiersForSwiftVar(content string, patterns swiftEnumIdentif
 		if len(m) < 2 {
 			continue
 		}
-		for _, enumMatch := range swiftEnumMemberRefRe.FindAllStringSubmatch(m[1], -1) {
+		// Dictionary literal keys are strings that may contain dots, so they
+		// are dropped before harvesting ".member" values.
+		for _, enumMatch := range swiftEnumMemberRefRe.FindAllStringSubmatch(swiftStringLiteralRe.ReplaceAllString(m[1], `""`), -1) {
 			if len(enumMatch) < 2 {
 				continue
 			}
@@ -617,14 +2509,21 @@ func extractEnumIdentifiersForSwiftVar(content string, patterns swiftEnumIdentif
 		appendIdentifier(strings.TrimSpace(m[1]))
 	}
 
+	for _, m := range patterns.subscriptAssign.FindAllStringSubmatch(content, -1) {
+		if len(m) < 2 {
+			continue
+		}
+		appendIdentifier(strings.TrimSpace(m[1]))
+	}
+
 	return out
 }
 
-func extractExplicitSourceAssetReferences(content string, labelAssetTypes map[string]map[string]struct{}, labelPatterns map[string]*regexp.Regexp) []sourceAssetReference {
+func extractExplicitSourceAssetReferences(content string, params swiftResourceParameters) []sourceAssetReference {
 	results := make([]sourceAssetReference, 0, 16)
 	seen := make(map[string]struct{})
 
-	appendTypedMatches := func(re *regexp.Regexp, assetType string) {
+	appendTypedMatches := func(re *regexp.Regexp, assetType string, rule string) {
 		matches := re.FindAllStringSubmatch(content, -1)
 		for _, m := range matches {
 			if len(m) < 2 {
@@ -639,16 +2538,60 @@ func extractExplicitSourceAssetReferences(content string, labelAssetTypes map[st
 				continue
 			}
 			seen[key] = struct{}{}
-			results = append(results, sourceAssetReference{Name: name, AssetType: assetType})
+			results = append(results, sourceAssetReference{Name: name, AssetType: assetType, Rule: rule, Text: m[0]})
 		}
 	}
 
-	appendTypedMatches(swiftNamedImageAssetRefRe, "imageset")
-	appendTypedMatches(swiftNamedColorAssetRefRe, "colorset")
-	appendTypedMatches(swiftNamedDataAssetRefRe, "dataset")
-	appendTypedMatches(swiftUIImageAssetRefRe, "imageset")
-	appendTypedMatches(swiftUIColorAssetRefRe, "colorset")
-	for _, ref := range extractSwiftLabeledResourceArgumentReferences(content, labelAssetTypes, labelPatterns) {
+	// Named lookups match only the call up to the opening quote; the literal
+	// itself is decoded so escaped quotes and backslashes in names resolve.
+	// With wholeCall set, the literal must be the first argument of a call
+	// that closes, so `Image("hero" + suffix)` is not read as "hero".
+	appendLiteralMatches := func(re *regexp.Regexp, assetType string, rule string, wholeCall bool) {
+		for _, loc := range re.FindAllStringIndex(content, -1) {
+			value, end, ok := readSwiftStringLiteral(content, loc[1])
+			if !ok {
+				continue
+			}
+			if wholeCall {
+				if end, ok = skipSwiftCallArguments(content, end); !ok {
+					continue
+				}
+			}
+			name := strings.TrimSpace(value)
+			if name == "" {
+				continue
+			}
+			key := sourceAssetTypeKey(name, assetType)
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+			results = append(results, sourceAssetReference{Name: name, AssetType: assetType, Rule: rule, Text: content[loc[0]:end]})
+		}
+	}
+
+	appendLiteralMatches(swiftNamedImageAssetRefRe, "imageset", "swift-image-named", false)
+	appendLiteralMatches(swiftNamedColorAssetRefRe, "colorset", "swift-color-named", false)
+	appendLiteralMatches(swiftNamedDataAssetRefRe, "dataset", "swift-data-asset-named", false)
+	appendLiteralMatches(swiftWatchImageNamedRefRe, "imageset", "swift-watch-image-named", false)
+	appendLiteralMatches(swiftTextureNameRefRe, "textureset", "swift-texture-named", false)
+	appendLiteralMatches(swiftUIImageAssetRefRe, "imageset", "swiftui-image", true)
+	appendLiteralMatches(swiftUIColorAssetRefRe, "colorset", "swiftui-color", true)
+	// System symbol names only resolve to custom symbol sets by exact name;
+	// unresolved names are SF Symbols and are ignored.
+	appendTypedMatches(swiftSystemSymbolNameRefRe, "symbolset", "swift-system-symbol")
+	for _, ref := range slices.Concat(
+		extractSwiftResourceArgumentReferences(content, swiftLabelMemberPairRe, params.labels, "swift-resource-label"),
+		extractSwiftResourceArgumentReferences(content, swiftCalleeMemberPairRe, params.positional, "swift-resource-positional"),
+	) {
+		key := sourceAssetTypeKey(ref.Name, ref.AssetType)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		results = append(results, ref)
+	}
+	for _, ref := range extractSwiftRawValueEnumReferences(content) {
 		key := sourceAssetTypeKey(ref.Name, ref.AssetType)
 		if _, exists := seen[key]; exists {
 			continue
@@ -656,43 +2599,71 @@ func extractExplicitSourceAssetReferences(content string, labelAssetTypes map[st
 		seen[key] = struct{}{}
 		results = append(results, ref)
 	}
-	appendTypedMatches(objcImageNamedAssetRefRe, "imageset")
-	appendTypedMatches(objcColorNamedAssetRefRe, "colorset")
-	appendTypedMatches(objcDataAssetNameRefRe, "dataset")
-	for _, name := range extractObjCImageNamedVariableReferences(content) {
-		key := sourceAssetTypeKey(name, "imageset")
+	appendTypedMatches(objcImageNamedAssetRefRe, "imageset", "objc-image-named")
+	appendTypedMatches(objcColorNamedAssetRefRe, "colorset", "objc-color-named")
+	appendTypedMatches(objcDataAssetNameRefRe, "dataset", "objc-data-asset-name")
+	appendTypedMatches(objcWatchImageNamedRefRe, "imageset", "objc-watch-image-named")
+	appendTypedMatches(objcTextureNameRefRe, "textureset", "objc-texture-named")
+	appendTypedMatches(swiftAlternateIconNameRefRe, "appiconset", "swift-alternate-icon-name")
+	appendTypedMatches(objcAlternateIconNameRefRe, "appiconset", "objc-alternate-icon-name")
+	for _, ref := range extractObjCImageNamedVariableReferences(content) {
+		key := sourceAssetTypeKey(ref.Name, ref.AssetType)
 		if _, exists := seen[key]; exists {
 			continue
 		}
 		seen[key] = struct{}{}
-		results = append(results, sourceAssetReference{Name: name, AssetType: "imageset"})
+		results = append(results, ref)
 	}
 
 	return results
 }
 
-func collectSwiftResourceArgumentLabelAssetTypes(root string, include []string, exclude []string) (map[string]map[string]struct{}, map[string]*regexp.Regexp, map[string]string, error) {
+// swiftResourceParameters records which call sites take an ImageResource or
+// ColorResource, so `.member` arguments there can be resolved to asset sets.
+type swiftResourceParameters struct {
+	// labels maps argument labels declared with a resource type to asset types.
+	labels map[string]map[string]struct{}
+	// positional maps function and type names whose first parameter is an
+	// unlabeled resource type to asset types.
+	positional map[string]map[string]struct{}
+	// accessors maps members defined in Options.Generated files to the
+	// asset names they load, e.g. `static let hero = ImageAsset(name: "hero")`.
+	accessors map[string][]string
+	// inspectableImages holds @IBInspectable / IBInspectable string property
+	// names implying an image; only collected when Options.IBDesignable is set.
+	inspectableImages map[string]struct{}
+	// imageNames maps NSImage.Name constants to the names they hold, e.g.
+	// `static let hero = NSImage.Name("hero")`.
+	imageNames map[string][]string
+}
+
+func collectSwiftResourceArgumentLabelAssetTypes(ctx context.Context, opts Options) (swiftResourceParameters, map[string]string, error) {
+	root, include, exclude := opts.Root, opts.Include, opts.Exclude
 	labels := make(map[string]map[string]struct{})
+	positional := make(map[string]map[string]struct{})
+	accessors := make(map[string][]string)
+	inspectables := make(map[string]struct{})
+	imageNames := make(map[string][]string)
 	swiftSources := make(map[string]string)
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if d.IsDir() {
-			if d.Name() == ".git" {
-				return filepath.SkipDir
-			}
 			rel, relErr := filepath.Rel(root, path)
 			if relErr != nil {
 				return relErr
 			}
-			if matchesAny(rel, exclude) {
+			if isSkippedHiddenDir(rel, d.Name(), opts.IncludeHidden) || exceedsMaxDepth(rel, opts.MaxDepth) || matchesAny(rel, exclude) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		if strings.Contains(path, ".xcassets"+string(filepath.Separator)) {
+		if isInsideAssetCatalog(path) {
 			return nil
 		}
 
@@ -706,7 +2677,8 @@ func collectSwiftResourceArgumentLabelAssetTypes(root string, include []string,
 		if len(include) > 0 && !matchesAny(rel, include) {
 			return nil
 		}
-		if strings.ToLower(filepath.Ext(path)) != ".swift" {
+		ext := strings.ToLower(filepath.Ext(path))
+		if _, isObjC := objcExtensions[ext]; ext != ".swift" && (!opts.IBDesignable || !isObjC) {
 			return nil
 		}
 
@@ -714,6 +2686,26 @@ func collectSwiftResourceArgumentLabelAssetTypes(root string, include []string,
 		if readErr != nil {
 			return readErr
 		}
+		if ext != ".swift" {
+			collectInspectableImageKeys(content, objcInspectableStringRe, inspectables)
+			return nil
+		}
+		if opts.IBDesignable {
+			collectInspectableImageKeys(content, swiftInspectableStringRe, inspectables)
+		}
+		for _, m := range swiftImageNameConstantRe.FindAllStringSubmatch(content, -1) {
+			if !slices.Contains(imageNames[m[1]], m[2]) {
+				imageNames[m[1]] = append(imageNames[m[1]], m[2])
+			}
+		}
+		if matchesAny(rel, opts.Generated) {
+			for _, m := range swiftGeneratedAccessorDefinitionRe.FindAllStringSubmatch(content, -1) {
+				if !slices.Contains(accessors[m[1]], m[2]) {
+					accessors[m[1]] = append(accessors[m[1]], m[2])
+				}
+			}
+			return nil
+		}
 		swiftSources[path] = content
 		for _, m := range swiftResourceParameterRe.FindAllStringSubmatch(content, -1) {
 			if len(m) < 3 {
@@ -733,17 +2725,56 @@ func collectSwiftResourceArgumentLabelAssetTypes(root string, include []string,
 			}
 			labels[label][assetType] = struct{}{}
 		}
+		collectSwiftPositionalResourceCallees(content, positional)
 
 		return nil
 	})
 	if err != nil {
-		return nil, nil, nil, err
+		return swiftResourceParameters{}, nil, err
+	}
+	return swiftResourceParameters{labels: labels, positional: positional, accessors: accessors, inspectableImages: inspectables, imageNames: imageNames}, swiftSources, nil
+}
+
+// collectInspectableImageKeys adds the property names declared by re whose
+// name implies an image, e.g. imageName or iconKey, to keys.
+func collectInspectableImageKeys(content string, re *regexp.Regexp, keys map[string]struct{}) {
+	for _, m := range re.FindAllStringSubmatch(content, -1) {
+		if inspectableImageKeyRe.MatchString(m[1]) {
+			keys[m[1]] = struct{}{}
+		}
 	}
-	labelPatterns := make(map[string]*regexp.Regexp, len(labels))
-	for label := range labels {
-		labelPatterns[label] = regexp.MustCompile(`\b` + regexp.QuoteMeta(label) + `\s*:\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
+}
+
+// collectSwiftPositionalResourceCallees adds the callee names of functions and
+// initializers declared with an unlabeled first ImageResource/ColorResource
+// parameter, such as `func setIcon(_ icon: ImageResource)` or
+// `init(_ icon: ImageResource)`, to positional. Initializers are attributed to
+// the nearest preceding type declaration.
+func collectSwiftPositionalResourceCallees(content string, positional map[string]map[string]struct{}) {
+	for _, m := range swiftPositionalResourceParameterRe.FindAllStringSubmatchIndex(content, -1) {
+		assetType := resourceTypeToAssetType(content[m[4]:m[5]])
+		if assetType == "" {
+			continue
+		}
+		callee := ""
+		if m[2] >= 0 {
+			callee = content[m[2]:m[3]]
+		} else {
+			decls := swiftTypeDeclarationRe.FindAllStringSubmatch(content[:m[0]], -1)
+			if len(decls) == 0 {
+				continue
+			}
+			typeName := decls[len(decls)-1][1]
+			callee = typeName[strings.LastIndex(typeName, ".")+1:]
+		}
+		if callee == "" {
+			continue
+		}
+		if _, exists := positional[callee]; !exists {
+			positional[callee] = make(map[string]struct{}, 1)
+		}
+		positional[callee][assetType] = struct{}{}
 	}
-	return labels, labelPatterns, swiftSources, nil
 }
 
 func resourceTypeToAssetType(resourceType string) string {
@@ -757,39 +2788,46 @@ func resourceTypeToAssetType(resourceType string) string {
 	}
 }
 
-func extractSwiftLabeledResourceArgumentReferences(content string, labelAssetTypes map[string]map[string]struct{}, labelPatterns map[string]*regexp.Regexp) []sourceAssetReference {
-	if len(labelAssetTypes) == 0 {
+// extractSwiftResourceArgumentReferences scans content once with re, whose
+// first group is a label or callee and second group a `.member`, and keeps
+// the pairs whose first group is in assetTypesByKey. References are ordered
+// by key, then by position, and deduplicated by name and asset type.
+func extractSwiftResourceArgumentReferences(content string, re *regexp.Regexp, assetTypesByKey map[string]map[string]struct{}, rule string) []sourceAssetReference {
+	if len(assetTypesByKey) == 0 {
 		return nil
 	}
 
-	labels := make([]string, 0, len(labelAssetTypes))
-	for label := range labelAssetTypes {
-		labels = append(labels, label)
+	type pairMatch struct {
+		name string
+		text string
+	}
+	matchesByKey := make(map[string][]pairMatch)
+	for pos := 0; pos < len(content); {
+		m := re.FindStringSubmatchIndex(content[pos:])
+		if m == nil {
+			break
+		}
+		key := content[pos+m[2] : pos+m[3]]
+		if _, ok := assetTypesByKey[key]; ok {
+			matchesByKey[key] = append(matchesByKey[key], pairMatch{name: content[pos+m[4] : pos+m[5]], text: content[pos+m[0] : pos+m[1]]})
+		}
+		// Resume at the member so it can itself start the next pair, as in
+		// `a: .b: .c`.
+		pos += m[4]
 	}
-	slices.Sort(labels)
 
 	seen := make(map[string]struct{})
 	out := make([]sourceAssetReference, 0, 8)
-	for _, label := range labels {
-		re, ok := labelPatterns[label]
-		if !ok || re == nil {
-			continue
-		}
-		for _, m := range re.FindAllStringSubmatch(content, -1) {
-			if len(m) < 2 {
-				continue
-			}
-			name := strings.TrimSpace(m[1])
-			if name == "" {
-				continue
-			}
-			for assetType := range labelAssetTypes[label] {
-				key := sourceAssetTypeKey(name, assetType)
-				if _, exists := seen[key]; exists {
+	for _, key := range slices.Sorted(maps.Keys(matchesByKey)) {
+		assetTypes := slices.Sorted(maps.Keys(assetTypesByKey[key]))
+		for _, match := range matchesByKey[key] {
+			for _, assetType := range assetTypes {
+				refKey := sourceAssetTypeKey(match.name, assetType)
+				if _, exists := seen[refKey]; exists {
 					continue
 				}
-				seen[key] = struct{}{}
-				out = append(out, sourceAssetReference{Name: name, AssetType: assetType})
+				seen[refKey] = struct{}{}
+				out = append(out, sourceAssetReference{Name: match.name, AssetType: assetType, Rule: rule, Text: match.text})
 			}
 		}
 	}
@@ -800,14 +2838,103 @@ func sourceAssetTypeKey(name string, assetType string) string {
 	return assetType + "\x00" + name
 }
 
-func extractObjCImageNamedVariableReferences(content string) []string {
+// extractSwiftRawValueEnumReferences resolves image and color loads named
+// by a String-backed enum declared in the same file. Icon.home.rawValue names
+// that case's raw value; icon.rawValue on a value declared as Icon, or bound
+// by a loop over Icon.allCases, names the raw value of every case.
+func extractSwiftRawValueEnumReferences(content string) []sourceAssetReference {
+	if !strings.Contains(content, ".rawValue") {
+		return nil
+	}
+	loads := swiftRawValueLoadRe.FindAllStringSubmatch(content, -1)
+	if len(loads) == 0 {
+		return nil
+	}
+	enums := collectSwiftStringEnumRawValues(content)
+	if len(enums) == 0 {
+		return nil
+	}
+
+	var valueTypes map[string]string
+	refs := make([]sourceAssetReference, 0, len(loads))
+	for _, m := range loads {
+		assetType := "imageset"
+		if strings.HasSuffix(m[1], "Color") {
+			assetType = "colorset"
+		}
+		if m[3] != "" {
+			if rawValue, ok := enums[m[2]][m[3]]; ok {
+				refs = append(refs, sourceAssetReference{Name: rawValue, AssetType: assetType, Rule: "swift-enum-raw-value", Text: m[0]})
+			}
+			continue
+		}
+		if valueTypes == nil {
+			valueTypes = collectSwiftEnumValueTypes(content, enums)
+		}
+		enumName, ok := valueTypes[m[2]]
+		if !ok {
+			continue
+		}
+		for _, caseName := range slices.Sorted(maps.Keys(enums[enumName])) {
+			refs = append(refs, sourceAssetReference{Name: enums[enumName][caseName], AssetType: assetType, Rule: "swift-enum-raw-value-variable", Text: m[0]})
+		}
+	}
+	return refs
+}
+
+// collectSwiftStringEnumRawValues maps each `enum Name: String` declared in
+// content to its case names and raw values; a case without an explicit
+// raw value uses its name.
+func collectSwiftStringEnumRawValues(content string) map[string]map[string]string {
+	enums := make(map[string]map[string]string)
+	for _, m := range swiftStringEnumRe.FindAllStringSubmatchIndex(content, -1) {
+		openIdx := m[1] - 1
+		closeIdx := findMatchingBrace(content, openIdx)
+		if closeIdx < 0 {
+			continue
+		}
+		name := content[m[2]:m[3]]
+		rawValues := enums[name]
+		if rawValues == nil {
+			rawValues = make(map[string]string)
+			enums[name] = rawValues
+		}
+		for _, decl := range swiftEnumCaseDeclRe.FindAllStringSubmatch(content[openIdx+1:closeIdx], -1) {
+			for _, item := range swiftEnumCaseItemRe.FindAllStringSubmatch(decl[1], -1) {
+				rawValue := item[2]
+				if !strings.Contains(item[0], "=") {
+					rawValue = item[1]
+				}
+				rawValues[item[1]] = rawValue
+			}
+		}
+	}
+	return enums
+}
+
+// collectSwiftEnumValueTypes maps value names declared with one of enums'
+// types (`let icon: Icon`, `icon: Icon` parameters, `for icon in
+// Icon.allCases`) to that enum.
+func collectSwiftEnumValueTypes(content string, enums map[string]map[string]string) map[string]string {
+	valueTypes := make(map[string]string)
+	for _, re := range []*regexp.Regexp{swiftTypedValueDeclRe, swiftAllCasesLoopRe} {
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			if _, ok := enums[m[2]]; ok {
+				valueTypes[m[1]] = m[2]
+			}
+		}
+	}
+	return valueTypes
+}
+
+func extractObjCImageNamedVariableReferences(content string) []sourceAssetReference {
 	varMatches := objcImageNamedVariableRefRe.FindAllStringSubmatch(content, -1)
 	if len(varMatches) == 0 {
 		return nil
 	}
 
 	seenNames := make(map[string]struct{})
-	names := make([]string, 0, len(varMatches))
+	refs := make([]sourceAssetReference, 0, len(varMatches))
 	assignPatterns := make(map[string]*regexp.Regexp, len(varMatches))
 	for _, m := range varMatches {
 		if len(m) < 2 || strings.TrimSpace(m[1]) == "" {
@@ -829,12 +2956,12 @@ func extractObjCImageNamedVariableReferences(content string) []string {
 					continue
 				}
 				seenNames[literal] = struct{}{}
-				names = append(names, literal)
+				refs = append(refs, sourceAssetReference{Name: literal, AssetType: "imageset", Rule: "objc-image-named-variable", Text: assignMatch[0]})
 			}
 		}
 	}
 
-	return names
+	return refs
 }
 
 func extractObjCStringLiterals(content string) []string {
@@ -860,6 +2987,10 @@ func extractObjCStringLiterals(content string) []string {
 func buildSwiftResourceCandidateIndex(discoveredAssets []discoveredAsset) map[string][]discoveredAsset {
 	index := make(map[string][]discoveredAsset, len(discoveredAssets))
 	for _, asset := range discoveredAssets {
+		// Xcode does not generate ImageResource symbols for app icon sets.
+		if asset.AssetType == "appiconset" {
+			continue
+		}
 		for _, candidate := range swiftResourceCandidatesForAsset(asset.Name, asset.AssetType) {
 			existing := index[candidate]
 			if !containsAssetPath(existing, asset.AssetPath) {
@@ -870,6 +3001,20 @@ func buildSwiftResourceCandidateIndex(discoveredAssets []discoveredAsset) map[st
 	return index
 }
 
+// SearchedCandidates returns the identifiers a scan matches against the
+// asset set at assetPath: its name, used by string lookups, followed by the
+// generated Swift resource symbol forms such as the camelCase variant. App
+// icon sets have no generated symbols.
+func SearchedCandidates(assetPath string) []string {
+	base := filepath.Base(assetPath)
+	assetType := strings.TrimPrefix(filepath.Ext(base), ".")
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	if assetType == "appiconset" {
+		return []string{name}
+	}
+	return swiftResourceCandidatesForAsset(name, assetType)
+}
+
 func swiftResourceCandidatesForAsset(assetName string, assetType string) []string {
 	candidates := []string{assetName}
 	parts := strings.FieldsFunc(assetName, func(r rune) bool {
@@ -963,9 +3108,27 @@ func selectClosestAssets(sourcePath string, candidates []discoveredAsset) []disc
 	return best
 }
 
+// selectSwiftResourceAssets resolves a generated resource identifier to the
+// closest candidate assets. When different asset names still collide on the
+// identifier (foo-bar and foo_bar both become fooBar), an asset named exactly
+// like the identifier wins over the camelCase variants.
+func selectSwiftResourceAssets(sourcePath string, identifier string, candidates []discoveredAsset) []discoveredAsset {
+	selected := selectClosestAssets(sourcePath, candidates)
+	exact := make([]discoveredAsset, 0, len(selected))
+	for _, asset := range selected {
+		if asset.Name == identifier {
+			exact = append(exact, asset)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return selected
+}
+
 func commonPathPrefixSegments(a string, b string) int {
-	aParts := strings.Split(filepath.Clean(a), string(filepath.Separator))
-	bParts := strings.Split(filepath.Clean(b), string(filepath.Separator))
+	aParts := splitPathSegments(filepath.Clean(a))
+	bParts := splitPathSegments(filepath.Clean(b))
 	max := len(aParts)
 	if len(bParts) < max {
 		max = len(bParts)
@@ -1009,15 +3172,63 @@ func swiftIdentifierVariants(name string) []string {
 	return variants
 }
 
+// isSkippedHiddenDir reports whether the directory name at rel is a
+// dot-directory the walks skip. The scan root itself is never skipped, and
+// .git is skipped even when includeHidden is set.
+func isSkippedHiddenDir(rel string, name string, includeHidden bool) bool {
+	if rel == "." || !strings.HasPrefix(name, ".") {
+		return false
+	}
+	return name == ".git" || !includeHidden
+}
+
 func isAssetSetDir(name string) bool {
 	switch filepath.Ext(name) {
-	case ".imageset", ".colorset", ".dataset":
+	case ".imageset", ".colorset", ".dataset", ".appiconset", ".symbolset", ".textureset":
 		return true
 	default:
 		return false
 	}
 }
 
+// exceedsMaxDepth reports whether the directory at rel lies deeper than
+// maxDepth. An asset catalog and everything inside it share the catalog's
+// depth so catalogs within the limit are always discovered completely.
+func exceedsMaxDepth(rel string, maxDepth *int) bool {
+	if maxDepth == nil || rel == "." {
+		return false
+	}
+	parts := splitPathSegments(rel)
+	depth := len(parts)
+	for i, part := range parts {
+		if strings.HasSuffix(part, ".xcassets") {
+			depth = i + 1
+			break
+		}
+	}
+	return depth > *maxDepth
+}
+
+// EffectivePatterns returns patterns the way path matching applies them:
+// root-relative with forward slashes, without blanks or duplicates, sorted.
+// A trailing slash still marks a directory prefix.
+func EffectivePatterns(patterns []string) []string {
+	effective := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if p := normalizePathPattern(pattern); p != "" {
+			effective = append(effective, p)
+		}
+	}
+	slices.Sort(effective)
+	return slices.Compact(effective)
+}
+
+func normalizePathPattern(pattern string) string {
+	p := filepath.ToSlash(strings.TrimSpace(pattern))
+	p = strings.TrimPrefix(p, "./")
+	return strings.TrimPrefix(p, "/")
+}
+
 func matchesAny(candidatePath string, patterns []string) bool {
 	if len(patterns) == 0 {
 		return false
@@ -1027,12 +3238,10 @@ func matchesAny(candidatePath string, patterns []string) bool {
 	normalized = strings.TrimPrefix(normalized, "./")
 	normalized = strings.TrimPrefix(normalized, "/")
 	for _, pattern := range patterns {
-		p := filepath.ToSlash(strings.TrimSpace(pattern))
+		p := normalizePathPattern(pattern)
 		if p == "" {
 			continue
 		}
-		p = strings.TrimPrefix(p, "./")
-		p = strings.TrimPrefix(p, "/")
 		if strings.HasSuffix(p, "/") {
 			base := strings.TrimSuffix(p, "/")
 			if normalized == base || strings.HasPrefix(normalized, base+"/") {
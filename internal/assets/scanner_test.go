@@ -1,12 +1,19 @@
 package assets
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"runtime"
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -245,6 +252,90 @@ func TestScan_FindsSwiftDataAssetResourceReference_WithDataSuffixTrim(t *testing
 	}
 }
 
+func TestScan_FindsSwiftNSDataAssetNameReference(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	dataAssetPath := filepath.Join(catalog, "config.dataset")
+	imageAssetPath := filepath.Join(catalog, "config.imageset")
+	if err := os.MkdirAll(dataAssetPath, 0o755); err != nil {
+		t.Fatalf("mkdir data asset set: %v", err)
+	}
+	if err := os.MkdirAll(imageAssetPath, 0o755); err != nil {
+		t.Fatalf("mkdir image asset set: %v", err)
+	}
+
+	swiftPath := filepath.Join(root, "App", "Config.swift")
+	if err := os.WriteFile(swiftPath, []byte(`let asset = NSDataAsset(name: "config", bundle: .main)`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 1 || res.UsedAssets[0] != "config.dataset" {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if len(res.UnusedAssets) != 1 || res.UnusedAssets[0] != "config.imageset" {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsSwiftNSDataAssetNamedReference(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "config.dataset"), 0o755); err != nil {
+		t.Fatalf("mkdir data asset set: %v", err)
+	}
+
+	swiftPath := filepath.Join(root, "App", "Config.swift")
+	if err := os.WriteFile(swiftPath, []byte(`let asset = NSDataAsset(named: "config")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 1 || res.UsedAssets[0] != "config" {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if len(res.UnusedAssets) != 0 {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsForceUnwrappedNSDataAssetDataDecodeChain(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"config.dataset", "fixtures.dataset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir data asset set: %v", err)
+		}
+	}
+
+	source := `let config = try JSONDecoder().decode(Config.self, from: NSDataAsset(name: "config")!.data)
+let font = UIFont(name: "fixtures", size: 12)
+let cache = PayloadCache(name: "fixtures")`
+	if err := os.WriteFile(filepath.Join(root, "App", "Config.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"config"}) {
+		t.Fatalf("expected force-unwrapped data asset to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"fixtures"}) {
+		t.Fatalf("expected unrelated initializers to leave fixtures unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
 func TestScan_FindsStoryboardImageReferences(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -288,6 +379,153 @@ func TestScan_FindsStoryboardImageReferences(t *testing.T) {
 	}
 }
 
+func TestScan_FindsStoryboardBarAppearanceImageReferences(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"navShadow.imageset", "navBackground.imageset", "tabIndicator.imageset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	storyboard := `<?xml version="1.0" encoding="UTF-8"?>
+<document type="com.apple.InterfaceBuilder3.CocoaTouch.Storyboard.XIB">
+    <scenes>
+        <scene>
+            <objects>
+                <navigationController id="n">
+                    <navigationBar key="navigationBar" backgroundImage="navBackground" shadowImage="navShadow" id="nb"/>
+                </navigationController>
+                <tabBarController id="t">
+                    <tabBar key="tabBar" selectionIndicatorImage="tabIndicator" id="tb"/>
+                </tabBarController>
+            </objects>
+        </scene>
+    </scenes>
+</document>`
+	if err := os.WriteFile(filepath.Join(root, "Main.storyboard"), []byte(storyboard), 0o644); err != nil {
+		t.Fatalf("write storyboard: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"navBackground", "navShadow", "tabIndicator"}) {
+		t.Fatalf("expected bar appearance images to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsConfiguredIBAttributeReferences(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"badgeIcon.imageset", "badgeTint.colorset", "badgeTint.imageset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	xib := `<?xml version="1.0" encoding="UTF-8"?>
+<document type="com.apple.InterfaceBuilder3.CocoaTouch.XIB">
+    <objects>
+        <view customClass="BadgeView" customModule="App" iconName="badgeIcon" tintName="badgeTint" id="v"/>
+    </objects>
+</document>`
+	if err := os.WriteFile(filepath.Join(root, "Badge.xib"), []byte(xib), 0o644); err != nil {
+		t.Fatalf("write xib: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected unconfigured attributes to be ignored, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, IBAttributes: map[string]string{"iconName": "imageset", "tintName": "colorset"}, TrackReferences: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badgeIcon", "badgeTint.colorset"}) {
+		t.Fatalf("expected configured attributes to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"badgeTint.imageset", "unused"}) {
+		t.Fatalf("expected the badgeTint image set to stay unused, got %#v", res.UnusedAssets)
+	}
+	refs := res.References["badgeIcon"]
+	if len(refs) != 1 || refs[0].Rule != "ib-custom-attribute" || refs[0].Text != `iconName="badgeIcon"` {
+		t.Fatalf("unexpected badgeIcon references: %#v", refs)
+	}
+}
+
+func TestScan_IBDesignableResolvesObjCInspectableRuntimeAttributes(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"badge.imageset", "swiftBadge.imageset", "runtimeImage.imageset", "caption.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	files := map[string]string{
+		"BadgeView.h": `IB_DESIGNABLE
+@interface BadgeView : UIView
+@property (nonatomic, copy) IBInspectable NSString *imageName;
+@property (nonatomic, copy) IBInspectable NSString *caption;
+@end`,
+		"SwiftBadgeView.swift": `@IBDesignable final class SwiftBadgeView: UIView {
+    @IBInspectable var iconName: String = ""
+}`,
+		"Main.storyboard": `<document>
+    <objects>
+        <view customClass="BadgeView" id="a">
+            <userDefinedRuntimeAttributes>
+                <userDefinedRuntimeAttribute type="string" keyPath="imageName" value="badge"/>
+                <userDefinedRuntimeAttribute type="string" keyPath="caption" value="caption"/>
+                <userDefinedRuntimeAttribute type="image" keyPath="placeholder" value="runtimeImage"/>
+            </userDefinedRuntimeAttributes>
+        </view>
+        <view customClass="SwiftBadgeView" id="b">
+            <userDefinedRuntimeAttributes>
+                <userDefinedRuntimeAttribute keyPath="iconName" type="string" value="swiftBadge"/>
+            </userDefinedRuntimeAttributes>
+        </view>
+    </objects>
+</document>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected runtime attributes to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, IBDesignable: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "runtimeImage", "swiftBadge"}) {
+		t.Fatalf("expected inspectable image keys to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"caption"}) {
+		t.Fatalf("expected non-image inspectable keys to stay unused, got %#v", res.UnusedAssets)
+	}
+}
+
 func TestScan_IgnoresGenericStoryboardNameAttributes(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -555,6 +793,90 @@ let color = Color("brand", bundle: .main)
 	}
 }
 
+func TestScan_FindsSwiftUIImageStringReference_WithParenthesizedTrailingArguments(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"wifi.imageset", "hero.imageset", "badge.imageset", "brand.colorset", "stale.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	content := `import SwiftUI
+let signal = Image("wifi", variableValue: min(strength, 1.0))
+let hero = Image("hero", label: Text("Hero (large)"))
+let badge = Image(
+    "badge",
+    bundle: Bundle(for: BadgeView.self),
+    label: Text(verbatim: "\(count) new")
+)
+let color = Color("brand", bundle: Bundle(identifier: "com.example.ui"))
+let stale = Image("stale" + suffix)
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2, TrackReferences: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "brand", "hero", "wifi"}) {
+		t.Fatalf("expected trailing-argument forms to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"stale"}) {
+		t.Fatalf("expected concatenated name to stay unused, got unused %#v", res.UnusedAssets)
+	}
+	if refs := res.References["hero"]; len(refs) != 1 || refs[0].Rule != "swiftui-image" || refs[0].Text != `Image("hero", label: Text("Hero (large)"))` {
+		t.Fatalf("expected the whole call as hero reference text, got %#v", refs)
+	}
+	if refs := res.References["wifi"]; len(refs) != 1 || refs[0].Text != `Image("wifi", variableValue: min(strength, 1.0))` {
+		t.Fatalf("expected the whole call as wifi reference text, got %#v", refs)
+	}
+}
+
+func TestScan_FindsSwiftUIModifierImageAndColorArguments(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"bg.imageset", "card_bg.imageset", "overlay.imageset", "ink.colorset", "accent.colorset", "tint.colorset", "stale.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	content := `import SwiftUI
+struct CardView: View {
+    var body: some View {
+        Text("Title").padding().background(Image("bg")).foregroundStyle(Color("ink"))
+        VStack {}
+            .background(Image("card_bg").resizable(), alignment: .top)
+            .overlay { Image("overlay") }
+            .foregroundStyle(Color("accent"), .secondary)
+        Text("Footer")
+            .background {
+                Color("tint")
+            }
+    }
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "CardView.swift"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"accent", "bg", "card_bg", "ink", "overlay", "tint"}) {
+		t.Fatalf("expected modifier arguments and closures to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"stale"}) {
+		t.Fatalf("expected only stale to be unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
 func TestScan_FindsSwiftTypedImageResourceIdentifiers(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -589,6 +911,68 @@ let image = UIImage(resource: icons[0])
 	}
 }
 
+func TestScan_FindsSwiftTypedImageResourceIdentifiers_FromCollectionAccessors(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"onboardingStep1", "onboardingStep2", "tip"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir image asset set: %v", err)
+		}
+	}
+
+	swiftPath := filepath.Join(root, "App", "Onboarding.swift")
+	content := `let steps: [ImageResource] = [.onboardingStep1, .onboardingStep2]
+let tips = [ImageResource]()
+let hero = UIImage(resource: steps.first!)
+let random = UIImage(resource: tips.randomElement()!)
+`
+	if err := os.WriteFile(swiftPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"onboardingStep1", "onboardingStep2"}) {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+}
+
+func TestScan_FindsSwiftTypedImageResourceIdentifiers_FromDictionarySubscript(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"home", "settings", "profile", "fill"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir image asset set: %v", err)
+		}
+	}
+
+	swiftPath := filepath.Join(root, "App", "TabBar.swift")
+	// "house.fill" is a dictionary key, not a member, so the fill asset stays
+	// unused.
+	content := `var icons: [String: ImageResource] = ["house.fill": .home, "gear": .settings]
+icons["person"] = .profile
+let image = UIImage(resource: icons["house.fill"]!)
+`
+	if err := os.WriteFile(swiftPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"home", "profile", "settings"}) {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"fill"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
 func TestScan_FindsSwiftTypedImageResourceIdentifiers_WithInferredArrayType(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -667,6 +1051,51 @@ func TestScan_FindsSwiftTypedImageResourceIdentifiers_FromTypedReturn(t *testing
 	}
 }
 
+func TestScan_FindsSwiftTypedImageResourceIdentifiers_FromSwitchReturns(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"betaIcon", "proIcon", "teamIcon", "freeIcon", "darkBadge", "lightBadge", "beta"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir image asset set: %v", err)
+		}
+	}
+
+	swiftPath := filepath.Join(root, "App", "Edition.swift")
+	content := `func editionIcon(for edition: Edition) -> ImageResource {
+    switch edition {
+    case .beta: return .betaIcon
+    case .pro, .team(_):
+        if edition.isTeam { return .teamIcon }
+        return .proIcon
+    default: return .freeIcon
+    }
+}
+
+var badge: ImageResource {
+    switch scheme {
+    case .dark: .darkBadge
+    default: .lightBadge
+    }
+}
+`
+	if err := os.WriteFile(swiftPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	wantUsed := []string{"betaIcon", "darkBadge", "freeIcon", "lightBadge", "proIcon", "teamIcon"}
+	if !slices.Equal(res.UsedAssets, wantUsed) {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"beta"}) {
+		t.Fatalf("expected switch case patterns not to count as usage, got %#v", res.UnusedAssets)
+	}
+}
+
 func TestScan_ScopesSwiftTypedReturnEnumMembersToResourceContexts(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
@@ -879,27 +1308,321 @@ func TestScan_FindsLabeledMembersWhenLabelIsTypedAsImageResource(t *testing.T) {
 	}
 }
 
-func TestScan_FindsObjCImageNamedVariableReferences(t *testing.T) {
+func TestScan_FindsPositionalMembersWhenFirstParameterIsImageResource(t *testing.T) {
 	t.Parallel()
 	root := t.TempDir()
 	catalog := filepath.Join(root, "App", "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "ipad_key_remove.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir image asset set: %v", err)
-	}
-	if err := os.MkdirAll(filepath.Join(catalog, "iphone_key_remove.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir image asset set: %v", err)
+	for _, name := range []string{"home.imageset", "settings.imageset", "accent.colorset", "warning.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
 
-	objcPath := filepath.Join(root, "App", "VTPasscodeUtilConstants.m")
-	content := `+ (UIImage *)removeKeyImage {
-    NSString *removeKey = isCurrentDeviceTypeiPad() ? @"ipad_key_remove" : @"iphone_key_remove";
-    return [UIImage imageNamed:removeKey];
-}`
-	if err := os.WriteFile(objcPath, []byte(content), 0o644); err != nil {
-		t.Fatalf("write objc source: %v", err)
-	}
+	componentPath := filepath.Join(root, "App", "Components.swift")
+	componentContent := `struct IconView: View {
+    init(_ icon: ImageResource, size: CGFloat = 24) {}
+}
 
-	res, err := Scan(Options{Root: root, Workers: 2})
+final class Toolbar {
+    func setIcon(_ icon: ImageResource) {}
+}
+
+extension Theme.Swatch {
+    init?(_ color: ColorResource) {}
+}
+
+enum Tone {
+    case warning
+}
+
+func badge(_ tone: Tone) {}
+`
+	if err := os.WriteFile(componentPath, []byte(componentContent), 0o644); err != nil {
+		t.Fatalf("write component: %v", err)
+	}
+
+	usagePath := filepath.Join(root, "App", "Home.swift")
+	usageContent := `func render(toolbar: Toolbar) {
+    let icon = IconView(.home, size: 32)
+    toolbar.setIcon(.settings)
+    let swatch = Swatch(.accent)
+    badge(.warning)
+}`
+	if err := os.WriteFile(usagePath, []byte(usageContent), 0o644); err != nil {
+		t.Fatalf("write usage: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2, TrackReferences: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"accent", "home", "settings"}) {
+		t.Fatalf("expected positional resource arguments to be used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"warning"}) {
+		t.Fatalf("expected positional enum argument to stay unused, got %#v", res.UnusedAssets)
+	}
+	for _, name := range res.UsedAssets {
+		refs := res.References[name]
+		if len(refs) != 1 || refs[0].Rule != "swift-resource-positional" {
+			t.Fatalf("expected a swift-resource-positional reference for %s, got %#v", name, refs)
+		}
+	}
+}
+
+func TestScan_ObjCColorSelectorsMatchWrapperMessagesOnAnyReceiver(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"brand.colorset", "accent.colorset", "surface.colorset", "muted.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `@implementation HeaderView
+- (void)applyTheme {
+    self.backgroundColor = [MyTheme colorNamed:@"brand"];
+    self.tintColor = [[MyTheme shared]
+        colorNamed:
+            @"accent"];
+    self.layer.borderColor = [MyTheme themeColor:@"surface"].CGColor;
+    self.textColor = [MyTheme colorNamed:@"muted" fallback:nil];
+}
+@end
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "HeaderView.m"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected wrapper selectors to be ignored without ObjCColorSelectors, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, ObjCColorSelectors: []string{"colorNamed", "themeColor"}})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"accent", "brand", "muted", "surface"}) {
+		t.Fatalf("expected registered selectors to mark colors used, got used %#v", res.UsedAssets)
+	}
+}
+
+func TestScan_FindsObjCImageNamedNestedInImageViewMessages(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero", "banner", "badge", "unused"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir image asset set: %v", err)
+		}
+	}
+
+	objcPath := filepath.Join(root, "App", "HeaderView.m")
+	content := `- (void)setup {
+    UIImageView *hero = [[UIImageView alloc] initWithImage:[UIImage imageNamed:@"hero"]];
+    [self.bannerView setImage:[UIImage imageNamed:@"banner"]];
+    [self.badgeView setImage:[[UIImage imageNamed:@"badge"] imageWithRenderingMode:UIImageRenderingModeAlwaysTemplate]];
+}`
+	if err := os.WriteFile(objcPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "banner", "hero"}) {
+		t.Fatalf("expected nested imageNamed: messages to be used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsObjCImageNamedInObjectiveCPlusPlusSources(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"renderer_icon.imageset", "brand.colorset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	content := `#include <vector>
+
+@implementation RendererView
+- (void)setup {
+    std::vector<int> samples;
+    self.iconView.image = [UIImage imageNamed:@"renderer_icon"];
+    self.backgroundColor = [MyTheme colorNamed:@"brand"];
+}
+@end
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "RendererView.mm"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write objc++ source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2, ObjCColorSelectors: []string{"colorNamed"}})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"brand", "renderer_icon"}) {
+		t.Fatalf("expected .mm references to be used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsNonASCIIAssetNamesInStringLiterals(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"café_icon.imageset", "⭐️star.imageset", "日本.colorset", "naïve.imageset", "ünused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	swiftPath := filepath.Join(root, "App", "Menu.swift")
+	swiftContent := `let icon = UIImage(named: "café_icon")
+let star = Image("⭐️star")
+let tint = Color("日本")
+`
+	if err := os.WriteFile(swiftPath, []byte(swiftContent), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+	objcPath := filepath.Join(root, "App", "Legacy.m")
+	if err := os.WriteFile(objcPath, []byte(`UIImage *image = [UIImage imageNamed:@"naïve"];`), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"café_icon", "naïve", "⭐️star", "日本"}) {
+		t.Fatalf("expected non-ASCII names to be used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"ünused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsBareImageMembersInSDKImageArguments(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"settings.imageset", "share-icon.imageset", "tab_home.imageset", "tab_home_selected.imageset", "nested.imageset", "status.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	swiftPath := filepath.Join(root, "App", "Menu.swift")
+	content := `let settings = UIAction(title: NSLocalizedString("Settings", comment: ""), image: .settings) { _ in }
+let share = UIBarButtonItem(image: .shareIcon, style: .plain, target: nil, action: nil)
+let item = UITabBarItem(title: "Home", image: .tabHome, selectedImage: .tabHomeSelected)
+let menu = UIMenu(title: "x", children: [UIAction(title: "y", handler: { _ in render(image: .nested) })])
+let badge = UIAction(title: "Status", image: .status) { _ in }
+`
+	if err := os.WriteFile(swiftPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2, TrackReferences: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"settings", "share-icon", "tab_home", "tab_home_selected"}) {
+		t.Fatalf("expected SDK image arguments to be used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"nested", "status"}) {
+		t.Fatalf("expected nested labels and color sets to stay unused, got %#v", res.UnusedAssets)
+	}
+	if refs := res.References["settings"]; len(refs) != 1 || refs[0].Rule != "swift-sdk-image-label" || refs[0].Text != "image: .settings" {
+		t.Fatalf("unexpected settings references: %#v", refs)
+	}
+}
+
+func TestScan_GeneratedAccessorFilesAreDefinitionOnly(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "legacy.imageset", "brand.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	generatedDir := filepath.Join(root, "App", "Generated")
+	if err := os.MkdirAll(generatedDir, 0o755); err != nil {
+		t.Fatalf("mkdir generated dir: %v", err)
+	}
+	generated := `internal enum Asset {
+  internal enum Icons {
+    internal static let hero = UIImage(named: "hero")!
+    internal static let legacy = UIImage(named: "legacy")!
+  }
+  internal enum Colors {
+    internal static let brand = UIColor(named: "brand")!
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(generatedDir, "Assets+Generated.swift"), []byte(generated), 0o644); err != nil {
+		t.Fatalf("write generated source: %v", err)
+	}
+	usage := `let image = Asset.Icons.hero
+let color = Asset.Colors.brand
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(usage), 0o644); err != nil {
+		t.Fatalf("write usage: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UnusedAssets) != 0 {
+		t.Fatalf("expected generated names to mask every asset without Generated, got %#v", res.UnusedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, Generated: []string{"**/Generated/**"}})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"brand", "hero"}) {
+		t.Fatalf("expected accessors used at call sites to count, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"legacy"}) {
+		t.Fatalf("expected the unreferenced accessor's asset to be unused, got %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsObjCImageNamedVariableReferences(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "ipad_key_remove.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir image asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(catalog, "iphone_key_remove.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir image asset set: %v", err)
+	}
+
+	objcPath := filepath.Join(root, "App", "VTPasscodeUtilConstants.m")
+	content := `+ (UIImage *)removeKeyImage {
+    NSString *removeKey = isCurrentDeviceTypeiPad() ? @"ipad_key_remove" : @"iphone_key_remove";
+    return [UIImage imageNamed:removeKey];
+}`
+	if err := os.WriteFile(objcPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
 	if err != nil {
 		t.Fatalf("scan error: %v", err)
 	}
@@ -1004,6 +1727,53 @@ func TestScan_DuplicateAssetNamesAcrossCatalogs_DoesNotEmitSameSummaryAsUsedAndU
 	}
 }
 
+func TestScan_DuplicateAssetNamesAcrossCatalogs_ReportsCollision(t *testing.T) {
+	t.Parallel()
+	res, moduleACatalog, moduleBCatalog := scanDuplicateCatalogIconFixture(t)
+
+	if len(res.DuplicateNames) != 1 {
+		t.Fatalf("expected one duplicate name, got %#v", res.DuplicateNames)
+	}
+	duplicate := res.DuplicateNames[0]
+	if duplicate.Name != "icon" || duplicate.AssetType != "imageset" {
+		t.Fatalf("unexpected duplicate entry: %#v", duplicate)
+	}
+	if !slices.Equal(duplicate.Catalogs, []string{moduleACatalog, moduleBCatalog}) {
+		t.Fatalf("unexpected duplicate catalogs: %#v", duplicate.Catalogs)
+	}
+}
+
+func TestScan_ReportsAssetNameCaseCollisionsWithinCatalog(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	appCatalog := filepath.Join(root, "App", "Assets.xcassets")
+	widgetCatalog := filepath.Join(root, "Widget", "Assets.xcassets")
+	// Hero sits in a group folder so the fixture also builds on
+	// case-insensitive file systems.
+	for _, dir := range []string{
+		filepath.Join(appCatalog, "Heroes", "Hero.imageset"),
+		filepath.Join(appCatalog, "hero.imageset"),
+		filepath.Join(appCatalog, "HERO.colorset"),
+		filepath.Join(widgetCatalog, "Hero.imageset"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.CaseCollisions) != 1 {
+		t.Fatalf("expected one case collision, got %#v", res.CaseCollisions)
+	}
+	collision := res.CaseCollisions[0]
+	if collision.Catalog != appCatalog || collision.AssetType != "imageset" || !slices.Equal(collision.Names, []string{"Hero", "hero"}) {
+		t.Fatalf("unexpected case collision: %#v", collision)
+	}
+}
+
 func scanDuplicateCatalogIconFixture(t *testing.T) (Result, string, string) {
 	t.Helper()
 	root := t.TempDir()
@@ -1068,39 +1838,1973 @@ func TestScan_IgnoresAssetSetsOutsideCatalogs(t *testing.T) {
 	}
 }
 
-func TestMatchesAny_GlobPatternMatchesExpectedPath(t *testing.T) {
+func TestScan_ClassifiesAssetsReferencedOnlyFromTests(t *testing.T) {
 	t.Parallel()
-	if !matchesAny("App/Main.swift", []string{"App/*.swift"}) {
-		t.Fatalf("expected glob to match path")
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"fixture.imageset", "hero.imageset", "snapshot.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
-}
 
-func TestMatchesAny_GlobstarPatternMatchesNestedPath(t *testing.T) {
-	t.Parallel()
-	if !matchesAny("Sources/App/Features/Home/View.swift", []string{"Sources/**/*.swift"}) {
-		t.Fatalf("expected globstar pattern to match nested path")
+	files := map[string]string{
+		filepath.Join(root, "App", "Home.swift"):                         `let _ = UIImage(named: "hero")`,
+		filepath.Join(root, "AppTests", "HomeTests.swift"):               `let _ = UIImage(named: "fixture")` + "\n" + `let _ = UIImage(named: "hero")`,
+		filepath.Join(root, "App", "Snapshots", "SnapshotTesting.swift"): `let _ = UIImage(named: "snapshot")`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir source dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write source: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 3 {
+		t.Fatalf("expected test-only references to still count as used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UsedOnlyInTests, []string{"fixture", "snapshot"}) {
+		t.Fatalf("unexpected used-only-in-tests assets: %#v", res.UsedOnlyInTests)
 	}
 }
 
-func TestMatchesAny_DirectoryPatternMatchesSubtree(t *testing.T) {
+func TestScan_ClassifiesAssetsReferencedOnlyFromPreviews(t *testing.T) {
 	t.Parallel()
-	if !matchesAny("ExternalLib/Assets.xcassets/icon.imageset", []string{"ExternalLib/"}) {
-		t.Fatalf("expected directory pattern to match subtree")
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"banner.imageset", "footer.imageset", "hero.imageset", "sample.imageset", "fixture.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
+
+	files := map[string]string{
+		filepath.Join(root, "App", "HomeView.swift"): `struct HomeView: View {
+    var body: some View { Image(resource: .banner) }
 }
 
-func TestMatchesAny_DoesNotUseSubstringFallback(t *testing.T) {
-	t.Parallel()
-	if matchesAny("MyExternalLib/Assets.xcassets/icon.imageset", []string{"ExternalLib/"}) {
-		t.Fatalf("did not expect substring overlap to match")
+#Preview {
+    Image(resource: .hero)
+    Image(resource: .banner)
+}
+
+#Preview("Dark", traits: .sizeThatFitsLayout) {
+    @Previewable @State var on = true
+    VStack {
+        if on { Image("sample") }
+    }
+}
+
+let footer = UIImage(named: "footer")`,
+		filepath.Join(root, "AppTests", "PreviewTests.swift"): `#Preview { Image("fixture") }`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir source dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write source: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 5 {
+		t.Fatalf("expected preview-only references to still count as used, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UsedOnlyInPreviews, []string{"hero", "sample"}) {
+		t.Fatalf("unexpected used-only-in-previews assets: %#v", res.UsedOnlyInPreviews)
+	}
+	if !slices.Equal(res.UsedOnlyInTests, []string{"fixture"}) {
+		t.Fatalf("expected previews in test sources to stay test-only, got %#v", res.UsedOnlyInTests)
 	}
 }
 
-func TestSwiftResourceCandidatesForAsset_HandlesMultibyteCamelCaseParts(t *testing.T) {
+func TestScan_RuleStatsCountsResolvedReferencesPerRule(t *testing.T) {
 	t.Parallel()
-	candidates := swiftResourceCandidatesForAsset("primary_äpfel", "imageset")
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "logo.imageset", "brand.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(root, "App", "Home.swift"):    `let a = UIImage(named: "hero")` + "\n" + `let b = UIImage(named: "missing")`,
+		filepath.Join(root, "App", "Profile.swift"): `let a = UIImage(named: "logo")` + "\n" + `let c = UIColor(named: "brand")`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write source: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.RuleMatchCounts != nil {
+		t.Fatalf("expected no rule stats without RuleStats, got %#v", res.RuleMatchCounts)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, RuleStats: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	want := map[string]int{"swift-image-named": 2, "swift-color-named": 1}
+	if !maps.Equal(res.RuleMatchCounts, want) {
+		t.Fatalf("expected rule stats %#v, got %#v", want, res.RuleMatchCounts)
+	}
+}
+
+func TestScan_DynamicNamesMatchesInterpolatedNameFamilies(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name       string
+		source     string
+		used       []string
+		unused     []string
+		assetNames []string
+	}{
+		{
+			name:       "suffix only",
+			source:     `let image = UIImage(named: "\(prefix)_selected")`,
+			assetNames: []string{"home_selected", "home", "selected"},
+			used:       []string{"home_selected"},
+			unused:     []string{"home", "selected"},
+		},
+		{
+			name:       "prefix only",
+			source:     `let image = Image("flag_\(country.code)")`,
+			assetNames: []string{"flag_de", "flag_", "banner_de"},
+			used:       []string{"flag_de"},
+			unused:     []string{"banner_de", "flag_"},
+		},
+		{
+			name:       "prefix and suffix",
+			source:     `let image = UIImage(named: "tab_\(item.rawValue)_selected")`,
+			assetNames: []string{"tab_home_selected", "tab_home", "home_selected"},
+			used:       []string{"tab_home_selected"},
+			unused:     []string{"home_selected", "tab_home"},
+		},
+		{
+			name:       "multiple interpolations",
+			source:     `let image = UIImage(named: "tab_\(a)_\(b)_on")`,
+			assetNames: []string{"tab_x_y_on", "tab_x_y_off"},
+			used:       []string{"tab_x_y_on"},
+			unused:     []string{"tab_x_y_off"},
+		},
+		{
+			name:       "interpolation only",
+			source:     `let image = UIImage(named: "\(name)")`,
+			assetNames: []string{"anything"},
+			unused:     []string{"anything"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			catalog := filepath.Join(root, "App", "Assets.xcassets")
+			for _, name := range tc.assetNames {
+				if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+					t.Fatalf("mkdir asset set: %v", err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(tc.source), 0o644); err != nil {
+				t.Fatalf("write swift source: %v", err)
+			}
+
+			res, err := Scan(Options{Root: root, Workers: 2, DynamicNames: true})
+			if err != nil {
+				t.Fatalf("scan error: %v", err)
+			}
+			if len(tc.used) == 0 {
+				tc.used = []string{}
+			}
+			if !slices.Equal(res.UsedAssets, tc.used) {
+				t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+			}
+			if !slices.Equal(res.UnusedAssets, tc.unused) {
+				t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+			}
+		})
+	}
+}
+
+func TestScan_DynamicNamesDisabledByDefault(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "home_selected.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(`let image = UIImage(named: "\(prefix)_selected")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UnusedAssets) != 1 || res.UnusedAssets[0] != "home_selected" {
+		t.Fatalf("expected interpolated family to be ignored by default, got %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_AppIconSetsUseInfoPlistAndAlternateIconReferences(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"AppIcon.appiconset", "AppIcon-Dark.appiconset", "AppIcon-Legacy.appiconset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir app icon set: %v", err)
+		}
+	}
+
+	plist := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>CFBundleIcons</key>
+	<dict>
+		<key>CFBundlePrimaryIcon</key>
+		<dict>
+			<key>CFBundleIconName</key>
+			<string>AppIcon</string>
+		</dict>
+	</dict>
+</dict>
+</plist>`
+	if err := os.WriteFile(filepath.Join(root, "App", "Info.plist"), []byte(plist), 0o644); err != nil {
+		t.Fatalf("write plist: %v", err)
+	}
+	swift := `UIApplication.shared.setAlternateIconName("AppIcon-Dark") { _ in }`
+	if err := os.WriteFile(filepath.Join(root, "App", "IconPicker.swift"), []byte(swift), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "Binary.plist"), []byte("bplist00\xff\xfe"), 0o644); err != nil {
+		t.Fatalf("write binary plist: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"AppIcon", "AppIcon-Dark"}) {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"AppIcon-Legacy"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+	unusedInCatalog := res.UnusedByFile[catalog]
+	if len(unusedInCatalog) != 1 || unusedInCatalog[0] != filepath.Join(catalog, "AppIcon-Legacy.appiconset") {
+		t.Fatalf("unexpected unused app icon paths: %#v", res.UnusedByFile)
+	}
+}
+
+func TestScan_AppIconSetsUseBuildSettingReferences(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"AppIcon.appiconset", "AppIcon-Holiday.appiconset", "AppIcon-Pride.appiconset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir app icon set: %v", err)
+		}
+	}
+
+	pbxproj := `buildSettings = {
+				ASSETCATALOG_COMPILER_APPICON_NAME = AppIcon;
+				ASSETCATALOG_COMPILER_ALTERNATE_APPICON_NAMES = "AppIcon-Holiday";
+			};`
+	projectDir := filepath.Join(root, "App.xcodeproj")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatalf("mkdir project dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "project.pbxproj"), []byte(pbxproj), 0o644); err != nil {
+		t.Fatalf("write pbxproj: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"AppIcon", "AppIcon-Holiday"}) {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"AppIcon-Pride"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_MaxDepthSkipsCatalogsAndSourcesBeyondLimit(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	shallowCatalog := filepath.Join(root, "a", "b", "c", "Assets.xcassets")
+	deepCatalog := filepath.Join(root, "a", "b", "c", "d", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(shallowCatalog, "Icons", "shallow.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir shallow asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(deepCatalog, "deep.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir deep asset set: %v", err)
+	}
+	deepSource := filepath.Join(root, "a", "b", "c", "d", "e", "Feature.swift")
+	if err := os.MkdirAll(filepath.Dir(deepSource), 0o755); err != nil {
+		t.Fatalf("mkdir source dir: %v", err)
+	}
+	if err := os.WriteFile(deepSource, []byte(`let _ = UIImage(named: "shallow")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	maxDepth := 4
+	res, err := Scan(Options{Root: root, Workers: 2, MaxDepth: &maxDepth})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 1 {
+		t.Fatalf("expected 1 catalog within depth, got %d", res.AssetCatalogs)
+	}
+	if !slices.Equal(res.AssetNames, []string{"shallow"}) {
+		t.Fatalf("unexpected asset names: %#v", res.AssetNames)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"shallow"}) {
+		t.Fatalf("expected source beyond depth to be ignored, got unused %#v", res.UnusedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 2 || !slices.Equal(res.UsedAssets, []string{"shallow"}) {
+		t.Fatalf("expected unlimited scan to find both catalogs and the deep source, got %#v", res)
+	}
+}
+
+func TestExceedsMaxDepth_CountsCatalogContentsAsCatalogDepth(t *testing.T) {
+	t.Parallel()
+	zero := 0
+	one := 1
+	if exceedsMaxDepth(".", &zero) {
+		t.Fatalf("expected root to be within depth 0")
+	}
+	if !exceedsMaxDepth("Sources", &zero) {
+		t.Fatalf("expected subdirectory to exceed depth 0")
+	}
+	if exceedsMaxDepth(filepath.Join("Assets.xcassets", "Icons", "icon.imageset"), &one) {
+		t.Fatalf("expected catalog contents to share the catalog depth")
+	}
+	if exceedsMaxDepth(filepath.Join("a", "b"), nil) {
+		t.Fatalf("expected nil max depth to be unlimited")
+	}
+}
+
+func TestScan_BundleResourceLookupsAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	source := `let path = Bundle.main.path(forResource: "hero", ofType: "png")`
+	if err := os.WriteFile(filepath.Join(root, "App", "Loader.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"hero"}) {
+		t.Fatalf("expected bundle lookup to be ignored by default, got unused %#v", res.UnusedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, BundleResources: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"hero"}) {
+		t.Fatalf("expected bundle lookup to mark hero used, got used %#v", res.UsedAssets)
+	}
+}
+
+func TestScan_LocalizedKeyLookupsAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"promo_banner.imageset", "unrelated.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let key = String(localized: "promo_banner")
+let image = UIImage(named: key)`
+	if err := os.WriteFile(filepath.Join(root, "App", "Promo.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"promo_banner", "unrelated"}) {
+		t.Fatalf("expected localized key to be ignored by default, got unused %#v", res.UnusedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, LocalizedKeys: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"promo_banner"}) {
+		t.Fatalf("expected localized key to mark promo_banner used, got used %#v", res.UsedAssets)
+	}
+}
+
+func TestScan_LocalizedImageNamesAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"home_icon.imageset", "home_icon.colorset", "unrelated.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let icon = UIImage(named: NSLocalizedString("home_icon", comment: ""))
+let title = NSLocalizedString("unrelated", comment: "")`
+	if err := os.WriteFile(filepath.Join(root, "App", "Home.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected localized image names to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, LocalizedNames: true, TrackReferences: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UnusedByFile[catalog], []string{
+		filepath.Join(catalog, "home_icon.colorset"),
+		filepath.Join(catalog, "unrelated.imageset"),
+	}) {
+		t.Fatalf("expected only the home_icon image set to be used, got unused %#v", res.UnusedByFile)
+	}
+	refs := res.References["home_icon.imageset"]
+	if len(refs) != 1 || refs[0].Rule != "swift-localized-image-name" || refs[0].Text != `UIImage(named: NSLocalizedString("home_icon"` {
+		t.Fatalf("unexpected references: %#v", res.References)
+	}
+}
+
+func TestScan_SwiftMarkdownImagesAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"hero.imageset", "badge.imageset", "remote.imageset", "unrelated.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let intro = try AttributedString(markdown: "Welcome ![Hero](hero) aboard")
+let reward = Text(LocalizedStringKey("Earn a ![badge](Images/badge@2x.png) today"))
+let banner = try AttributedString(markdown: "![](https://example.com/remote.png)")
+let body = """
+![](unrelated)
+"""`
+	if err := os.WriteFile(filepath.Join(root, "App", "Intro.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected markdown images to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, AttributedMarkdown: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "hero"}) {
+		t.Fatalf("expected markdown images to mark badge and hero used, got used %#v", res.UsedAssets)
+	}
+}
+
+func TestScan_DefaultsStringValuesAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"classic_icon.imageset", "dark_banner.imageset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `struct SettingsView: View {
+    @AppStorage("iconStyle") var style = "classic_icon"
+}
+
+UserDefaults.standard.register(defaults: [
+    "bannerName": "dark_banner",
+    "launchCount": 0,
+])`
+	if err := os.WriteFile(filepath.Join(root, "App", "Settings.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected defaults to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, Defaults: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"classic_icon", "dark_banner"}) {
+		t.Fatalf("expected default strings to mark assets used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_KeyPathAccessorLookupsAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"home.imageset", "tab-settings.imageset", "brand.colorset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let home = Theme.icons[\.home]
+let settings = AssetLibrary.image(\.tabSettings)
+let brand = palette(\.brand)
+let sorted = items.sorted(by: \.unused)`
+	if err := os.WriteFile(filepath.Join(root, "App", "Theme.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected key paths to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, KeyPathAccessors: []string{"icons", "image", "palette"}})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"brand", "home", "tab-settings"}) {
+		t.Fatalf("expected accessor key paths to resolve, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("expected key paths outside accessors to be ignored, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_SystemNameResolvesOnlyExactCustomSymbolSets(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"custom.star.symbolset", "star.symbolset", "badge.symbolset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let custom = UIImage(systemName: "custom.star")
+let system = UIImage(systemName: "star.fill", withConfiguration: config)
+let swiftUI = Image(systemName: "heart")
+let named = Image("badge")`
+	if err := os.WriteFile(filepath.Join(root, "App", "Icons.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.AssetNames, []string{"badge", "custom.star", "star"}) {
+		t.Fatalf("unexpected asset names: %#v", res.AssetNames)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "custom.star"}) {
+		t.Fatalf("expected custom symbol hits to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"star"}) {
+		t.Fatalf("expected system symbol name to leave star unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_DottedSymbolNameWithSymbolConfigurationMarksSymbolSetUsed(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"brand.symbol.symbolset", "brand.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let config = UIImage.SymbolConfiguration(pointSize: 20, weight: .bold)
+let icon = UIImage(named: "brand.symbol")?.applyingSymbolConfiguration(config)`
+	if err := os.WriteFile(filepath.Join(root, "App", "Brand.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"brand.symbol"}) {
+		t.Fatalf("expected dotted symbol name to resolve to the symbolset, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"brand"}) {
+		t.Fatalf("expected brand imageset to stay unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_TextureLoaderNamesMarkTextureSetsUsed(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"albedo.textureset", "normal.textureset", "roughness.textureset", "unused.textureset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	swiftSource := `let loader = MTKTextureLoader(device: device)
+let albedo = try loader.newTexture(name: "albedo", scaleFactor: 1, bundle: nil, options: nil)
+let normal = MDLTexture(named: "normal")`
+	if err := os.WriteFile(filepath.Join(root, "App", "Renderer.swift"), []byte(swiftSource), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+	objcSource := `id<MTLTexture> texture = [loader newTextureWithName:@"roughness" scaleFactor:1 bundle:nil options:nil error:&error];`
+	if err := os.WriteFile(filepath.Join(root, "App", "Renderer.m"), []byte(objcSource), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"albedo", "normal", "roughness"}) {
+		t.Fatalf("expected texture loader names to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_WatchKitImageSettersMarkImageSetsUsed(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Watch", "Assets.xcassets")
+	for _, dir := range []string{"hero.imageset", "card.imageset", "badge.imageset", "panel.imageset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	swiftSource := `interfaceImage.setImageNamed("hero")
+group.setBackgroundImageNamed( "card" )`
+	if err := os.WriteFile(filepath.Join(root, "Watch", "InterfaceController.swift"), []byte(swiftSource), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+	objcSource := `[self.interfaceImage setImageNamed:@"badge"];
+[self.group setBackgroundImageNamed:@"panel"];`
+	if err := os.WriteFile(filepath.Join(root, "Watch", "InterfaceController.m"), []byte(objcSource), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "card", "hero", "panel"}) {
+		t.Fatalf("expected watch image setters to mark assets used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_NestedUIImageNamedInsideUIImageViewInitializer(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"hero.imageset", "banner.imageset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let heroView = UIImageView(image: UIImage(named: "hero")) // hero artwork
+let bannerView = UIImageView(image: UIImage(
+    named: "banner" /* marketing */
+))`
+	if err := os.WriteFile(filepath.Join(root, "App", "Header.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"banner", "hero"}) {
+		t.Fatalf("expected nested named images to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"unused"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_NamedLookupsDecodeEscapedStringLiterals(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{`say "cheese".imageset`, `back\slash.imageset`, "café.colorset", "flag_.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let quoted = UIImage(named: "say \"cheese\"")
+let slashed = UIImage(named: "back\\slash")
+let accent = UIColor(named: "caf\u{E9}")
+let flag = UIImage(named: "flag_\(code)")`
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{`back\slash`, "café", `say "cheese"`}) {
+		t.Fatalf("expected escaped names to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"flag_"}) {
+		t.Fatalf("expected interpolated literal to stay unresolved, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsNSImageNamedReferenceThroughNameConstants(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "MacApp", "Assets.xcassets")
+	for _, dir := range []string{"hero-banner.imageset", "toolbar_icon.imageset", "sidebar.imageset", "stale.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	names := `import AppKit
+
+extension NSImage.Name {
+    static let heroBanner = NSImage.Name("hero-banner")
+    static let toolbarIcon = NSImage.Name(rawValue: "toolbar_icon")
+    static let sidebar: NSImage.Name = "sidebar"
+    static let stale = NSImage.Name("stale")
+}
+`
+	view := `import AppKit
+
+final class WindowController: NSWindowController {
+    let hero = NSImage(named: .heroBanner)
+    let toolbar = NSImage(named: NSImage.Name.toolbarIcon)
+    let sidebar = NSImage(named: .sidebar)
+}
+`
+	for name, content := range map[string]string{"ImageNames.swift": names, "WindowController.swift": view} {
+		if err := os.WriteFile(filepath.Join(root, "MacApp", name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"hero-banner", "sidebar", "toolbar_icon"}) {
+		t.Fatalf("expected NSImage.Name constants to resolve, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"stale"}) {
+		t.Fatalf("expected unreferenced constant to stay unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FindsUIImageNamedInsideConvenienceExtension(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"hero.imageset", "badge.imageset", "stale.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	// badge is only loaded by its extension property, which nothing calls.
+	extension := `import UIKit
+
+extension UIImage {
+    static var hero: UIImage { UIImage(named: "hero")! }
+    static var badge: UIImage {
+        UIImage(named: "badge")!
+    }
+}
+`
+	if err := os.MkdirAll(filepath.Join(root, "App", "Extensions"), 0o755); err != nil {
+		t.Fatalf("mkdir extensions: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "Extensions", "UIImage+Assets.swift"), []byte(extension), 0o644); err != nil {
+		t.Fatalf("write extension source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "HeaderView.swift"), []byte(`let header = UIImageView(image: UIImage.hero)`), 0o644); err != nil {
+		t.Fatalf("write view source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "hero"}) {
+		t.Fatalf("expected extension-loaded images to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"stale"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_NamedInBundleMatchesTwoAndThreeArgumentForms(t *testing.T) {
+	t.Parallel()
+	classes := []struct {
+		class     string
+		assetType string
+	}{
+		{class: "UIImage", assetType: "imageset"},
+		{class: "NSImage", assetType: "imageset"},
+		{class: "UIColor", assetType: "colorset"},
+		{class: "NSColor", assetType: "colorset"},
+	}
+	arities := []struct {
+		name string
+		args string
+	}{
+		{name: "named in", args: `named: "hero", in: .module`},
+		{name: "named in compatibleWith", args: `named: "hero", in: .module, compatibleWith: nil`},
+	}
+
+	for _, class := range classes {
+		for _, arity := range arities {
+			t.Run(class.class+" "+arity.name, func(t *testing.T) {
+				t.Parallel()
+				root := t.TempDir()
+				catalog := filepath.Join(root, "App", "Assets.xcassets")
+				for _, name := range []string{"hero", "other"} {
+					if err := os.MkdirAll(filepath.Join(catalog, name+"."+class.assetType), 0o755); err != nil {
+						t.Fatalf("mkdir asset set: %v", err)
+					}
+				}
+				source := "let value = " + class.class + "(" + arity.args + ")"
+				if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(source), 0o644); err != nil {
+					t.Fatalf("write swift source: %v", err)
+				}
+
+				res, err := Scan(Options{Root: root, Workers: 2})
+				if err != nil {
+					t.Fatalf("scan error: %v", err)
+				}
+				if !slices.Equal(res.UsedAssets, []string{"hero"}) {
+					t.Fatalf("expected hero to be used for %q, got used %#v", source, res.UsedAssets)
+				}
+				if !slices.Equal(res.UnusedAssets, []string{"other"}) {
+					t.Fatalf("expected other to be unused for %q, got unused %#v", source, res.UnusedAssets)
+				}
+			})
+		}
+	}
+}
+
+func TestCatalogPathForAsset_HandlesWindowsAndMixedSeparators(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		path string
+		want string
+	}{
+		{path: `C:\repo\App\Assets.xcassets\icon.imageset`, want: `C:\repo\App\Assets.xcassets`},
+		{path: `C:\repo\App\Assets.xcassets\Icons\icon.imageset`, want: `C:\repo\App\Assets.xcassets`},
+		{path: `C:\repo/App\Assets.xcassets/icon.imageset`, want: `C:\repo/App\Assets.xcassets`},
+		{path: `C:\repo\App\Assets.xcassets`, want: `C:\repo\App\Assets.xcassets`},
+		{path: `C:\repo\App\Old.xcassets.bak\New.xcassets\icon.imageset`, want: `C:\repo\App\Old.xcassets.bak\New.xcassets`},
+		{path: `C:\repo\App\icon.imageset`, want: ""},
+		{path: "/repo/App/Assets.xcassets/icon.imageset", want: "/repo/App/Assets.xcassets"},
+	}
+
+	for _, tc := range cases {
+		if got := catalogPathForAsset(tc.path); got != tc.want {
+			t.Fatalf("catalogPathForAsset(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+	if !isInsideAssetCatalog(`C:\repo\Assets.xcassets\icon.imageset\Contents.json`) {
+		t.Fatalf("expected backslash path to be inside a catalog")
+	}
+	if isInsideAssetCatalog(`C:\repo\Assets.xcassets`) {
+		t.Fatalf("expected catalog root itself not to be inside a catalog")
+	}
+}
+
+func TestScan_ReportsContentsOnlyAssetSetsAsEmpty(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	files := map[string]string{
+		filepath.Join("placeholder.imageset", "Contents.json"): "{}",
+		filepath.Join("placeholder.imageset", ".DS_Store"):     "",
+		filepath.Join("hero.imageset", "Contents.json"):        "{}",
+		filepath.Join("hero.imageset", "hero.png"):             "png",
+		filepath.Join("brand.colorset", "Contents.json"):       "{}",
+	}
+	for rel, content := range files {
+		path := filepath.Join(catalog, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write asset file: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	expected := []string{filepath.Join(catalog, "placeholder.imageset")}
+	if !slices.Equal(res.EmptyAssetSets, expected) {
+		t.Fatalf("expected only the placeholder set to be empty, got %#v", res.EmptyAssetSets)
+	}
+}
+
+func TestScan_ButtonConfigurationPropertyChainAssignments(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"bg", "hero", "spare"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `var config = UIButton.Configuration.filled()
+config.background.image =
+    UIImage(
+        named: "bg"
+    )
+config.image = UIImage(
+    resource: .hero
+)
+button.configuration = config`
+	if err := os.WriteFile(filepath.Join(root, "App", "Button.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"bg", "hero"}) {
+		t.Fatalf("expected wrapped property-chain assignments to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"spare"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+// Not parallel: the goroutine count must not include other tests' workers.
+func TestScanContext_CanceledReturnsPromptlyWithoutLeakingWorkers(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	const sourceFiles = 2000
+	for i := 0; i < sourceFiles; i++ {
+		path := filepath.Join(root, "App", "View"+strconv.Itoa(i)+".swift")
+		if err := os.WriteFile(path, []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+			t.Fatalf("write swift source: %v", err)
+		}
+	}
+
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel once workers have picked up several files, while the walk is
+	// still feeding them.
+	var scanned atomic.Int64
+	opts := Options{Root: root, Workers: 4, usageFileHook: func(string) {
+		if scanned.Add(1) == 8 {
+			cancel()
+		}
+	}}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := ScanContext(ctx, opts)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ScanContext did not return after cancellation")
+	}
+	if got := scanned.Load(); got < 8 || got >= sourceFiles {
+		t.Fatalf("expected cancellation mid-scan, workers scanned %d of %d files", got, sourceFiles)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected scan goroutines to exit, got %d running (was %d)", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestScan_DocCImageReferencesAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero", "diagram", "spare"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	docs := filepath.Join(root, "App", "Docs.docc")
+	if err := os.MkdirAll(docs, 0o755); err != nil {
+		t.Fatalf("mkdir docc catalog: %v", err)
+	}
+	article := "# Getting Started\n\n![The hero image](hero)\n\n![Remote](https://example.com/spare.png)\n"
+	if err := os.WriteFile(filepath.Join(docs, "GettingStarted.md"), []byte(article), 0o644); err != nil {
+		t.Fatalf("write article: %v", err)
+	}
+	tutorial := `@Tutorial(time: 5) {
+    @Section(title: "Overview") {
+        @Image(source: "diagram", alt: "Architecture")
+    }
+}`
+	if err := os.WriteFile(filepath.Join(docs, "Intro.tutorial"), []byte(tutorial), 0o644); err != nil {
+		t.Fatalf("write tutorial: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected docs to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, DocC: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"diagram", "hero"}) {
+		t.Fatalf("expected doc image references to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"spare"}) {
+		t.Fatalf("expected remote image URL to be ignored, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_HTMLImageReferencesAreOptIn(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"welcome", "step_one", "spare"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	help := filepath.Join(root, "App", "Help.help", "Contents", "Resources")
+	if err := os.MkdirAll(help, 0o755); err != nil {
+		t.Fatalf("mkdir help bundle: %v", err)
+	}
+	page := `<html><body>
+<img class="hero" src="images/welcome.png" alt="Welcome">
+<img src="step_one@2x.png">
+<img src="https://example.com/spare.png">
+</body></html>`
+	if err := os.WriteFile(filepath.Join(help, "index.html"), []byte(page), 0o644); err != nil {
+		t.Fatalf("write help page: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UsedAssets) != 0 {
+		t.Fatalf("expected html to be ignored by default, got used %#v", res.UsedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, HTML: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"step_one", "welcome"}) {
+		t.Fatalf("expected html image sources to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"spare"}) {
+		t.Fatalf("expected remote image URL to be ignored, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_FoldsLiteralStringConcatenationInNames(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero_dark", "tab_home_selected", "banner_dark"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let hero = UIImage(named: "hero" + "_dark")
+let tab = Image("tab_" +
+    "home" +
+    "_selected")
+let banner = UIImage(named: "banner" + suffix)`
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"hero_dark", "tab_home_selected"}) {
+		t.Fatalf("expected literal concatenations to resolve, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"banner_dark"}) {
+		t.Fatalf("expected non-literal operand to stay unresolved, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_IgnoresLookalikeReferencesInsideMultilineStrings(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero", "footer", "templateIcon", "rawIcon"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let hero = UIImage(named: "hero")
+let template = """
+    <p>Call UIImage(named: "templateIcon") to load it.</p>
+    """
+let raw = #"""
+    Image("rawIcon")
+    """#
+let footer = UIImage(named: "footer")`
+	if err := os.WriteFile(filepath.Join(root, "App", "Templates.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"footer", "hero"}) {
+		t.Fatalf("expected references around multiline strings to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"rawIcon", "templateIcon"}) {
+		t.Fatalf("expected lookalikes inside multiline strings to stay unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_MultilineStringDelimitersInCommentsEscapesAndRawStringsDoNotFlipPairing(t *testing.T) {
+	t.Parallel()
+	const template = "let template = \"\"\"\n    UIImage(named: \"lookalike\")\n    \"\"\"\n"
+	cases := map[string]string{
+		"line comment":           "// Templates start with \"\"\" and end the same way.\nlet hero = UIImage(named: \"hero\")\n" + template,
+		"block comment":          "/* a \"\"\" delimiter /* nested */ still comment */\nlet hero = UIImage(named: \"hero\")\n" + template,
+		"raw single-line string": "let marker = #\" \"\"\" starts a block \"#\nlet hero = UIImage(named: \"hero\")\n" + template,
+		"escaped delimiter":      "let quote = \"\"\"\n    Quote: \\\"\"\"\n    UIImage(named: \"lookalike\")\n    \"\"\"\nlet hero = UIImage(named: \"hero\")\n",
+		"raw string":             "let raw = #\"\"\"\n    A plain \"\"\" does not close a raw literal.\n    UIImage(named: \"lookalike\")\n    \"\"\"#\nlet hero = UIImage(named: \"hero\")\n",
+	}
+	for name, source := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			catalog := filepath.Join(root, "App", "Assets.xcassets")
+			for _, asset := range []string{"hero", "footer", "lookalike"} {
+				if err := os.MkdirAll(filepath.Join(catalog, asset+".imageset"), 0o755); err != nil {
+					t.Fatalf("mkdir asset set: %v", err)
+				}
+			}
+			source += "let footer = UIImage(named: \"footer\")\n"
+			if err := os.WriteFile(filepath.Join(root, "App", "Templates.swift"), []byte(source), 0o644); err != nil {
+				t.Fatalf("write swift source: %v", err)
+			}
+
+			res, err := Scan(Options{Root: root, Workers: 2})
+			if err != nil {
+				t.Fatalf("scan error: %v", err)
+			}
+			if !slices.Equal(res.UsedAssets, []string{"footer", "hero"}) {
+				t.Fatalf("expected references around the multiline string to be used, got used %#v", res.UsedAssets)
+			}
+			if !slices.Equal(res.UnusedAssets, []string{"lookalike"}) {
+				t.Fatalf("expected the lookalike inside the multiline string to stay unused, got unused %#v", res.UnusedAssets)
+			}
+		})
+	}
+}
+
+func TestScan_ActiveConfigIgnoresReferencesInInactiveConditionalBranches(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero", "debugBadge", "releaseBadge", "betaBadge", "phoneIcon", "macIcon", "nestedDebug"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let hero = UIImage(named: "hero")
+#if DEBUG
+let badge = UIImage(named: "debugBadge")
+#elseif BETA || !RELEASE
+let badge = UIImage(named: "betaBadge")
+#else // RELEASE
+let badge = UIImage(named: "releaseBadge")
+#endif
+#if os(iOS)
+let icon = UIImage(named: "phoneIcon")
+#else
+let icon = UIImage(named: "macIcon")
+  #if DEBUG
+  let nested = UIImage(named: "nestedDebug")
+  #endif
+#endif`
+	if err := os.WriteFile(filepath.Join(root, "App", "Badge.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if len(res.UnusedAssets) != 0 {
+		t.Fatalf("expected every branch to count without ActiveConfig, got unused %#v", res.UnusedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, ActiveConfig: "RELEASE"})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"hero", "macIcon", "phoneIcon", "releaseBadge"}) {
+		t.Fatalf("unexpected used assets for RELEASE: %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"betaBadge", "debugBadge", "nestedDebug"}) {
+		t.Fatalf("unexpected unused assets for RELEASE: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_XcodeGeneratedAssetSymbolsAreNotUsage(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "spare.imageset", "AccentColor.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	generated := `import Foundation
+#if canImport(DeveloperToolsSupport)
+import DeveloperToolsSupport
+#endif
+
+#if SWIFT_PACKAGE
+private let resourceBundle = Foundation.Bundle.module
+#else
+private class ResourceBundleClass {}
+private let resourceBundle = Foundation.Bundle(for: ResourceBundleClass.self)
+#endif
+
+// MARK: - Color Symbols -
+
+@available(iOS 17.0, macOS 14.0, tvOS 17.0, watchOS 10.0, *)
+extension DeveloperToolsSupport.ColorResource {
+
+    /// The "AccentColor" asset catalog color resource.
+    static let accent = DeveloperToolsSupport.ColorResource(name: "AccentColor", bundle: resourceBundle)
+
+}
+
+// MARK: - Image Symbols -
+
+@available(iOS 17.0, macOS 14.0, tvOS 17.0, watchOS 10.0, *)
+extension DeveloperToolsSupport.ImageResource {
+
+    /// The "hero" asset catalog image resource.
+    static let hero = DeveloperToolsSupport.ImageResource(name: "hero", bundle: resourceBundle)
+
+    /// The "spare" asset catalog image resource.
+    static let spare = DeveloperToolsSupport.ImageResource(name: "spare", bundle: resourceBundle)
+
+}
+
+// MARK: - Backwards Deployment Support -
+
+#if canImport(UIKit)
+@available(iOS 11.0, tvOS 11.0, *)
+@available(watchOS, unavailable)
+extension UIKit.UIImage {
+
+    /// The "spare" asset catalog image.
+    static var spare: UIKit.UIImage {
+        .init(resource: .spare)
+    }
+
+}
+#endif
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "GeneratedAssetSymbols.swift"), []byte(generated), 0o644); err != nil {
+		t.Fatalf("write generated symbols: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte("let image = UIImage(resource: .hero)\nfunc setIcon(resource: ImageResource) {}\n"), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	// The resource: label declared above makes .init(resource: .spare) in the
+	// generated file look like a typed reference unless the file is skipped.
+	res, err := Scan(Options{Root: root, Workers: 2, DynamicNames: true, BundleResources: true, LocalizedKeys: true, LocalizedNames: true, AttributedMarkdown: true, Defaults: true, KeyPathAccessors: []string{"image"}, IBDesignable: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"hero"}) {
+		t.Fatalf("expected only the symbol used outside the generated file, got %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"AccentColor", "spare"}) {
+		t.Fatalf("expected generated symbol definitions not to count as usage, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_ResolvesStringEnumRawValuesInImageLoads(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"home", "home_icon", "settings_icon", "profile", "brandTint.colorset", "stale"} {
+		if filepath.Ext(name) == "" {
+			name += ".imageset"
+		}
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `enum Icon: String {
+    case home
+    case settings = "settings_icon"
+}
+
+enum TabIcon: String, CaseIterable {
+    case profile, search = "home_icon"
+
+    var title: String {
+        switch self {
+        case .profile: return "Profile"
+        case .search: return "Search"
+        }
+    }
+}
+
+enum Palette: String {
+    case brandTint
+    case stale
+}
+
+func configure(imageView: UIImageView, tab: TabIcon) {
+    imageView.image = UIImage(named: Icon.home.rawValue)
+    imageView.highlightedImage = UIImage(named: Icon.settings.rawValue)
+    imageView.tintColor = UIColor(named: Palette.brandTint.rawValue)
+    let tabImage = Image(tab.rawValue)
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "App", "Icons.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"brandTint", "home", "home_icon", "profile", "settings_icon"}) {
+		t.Fatalf("unexpected used assets: %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"stale"}) {
+		t.Fatalf("expected enum cases never loaded as images to stay unused, got %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_PrecompiledHeaderReferencesKeepAssetsUsed(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero", "spare"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	header := `#ifdef __OBJC__
+#define APP_HERO_IMAGE [UIImage imageNamed:@"hero"]
+#endif`
+	if err := os.WriteFile(filepath.Join(root, "App", "App-Prefix.pch"), []byte(header), 0o644); err != nil {
+		t.Fatalf("write pch: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"hero"}) {
+		t.Fatalf("expected .pch reference to keep hero used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"spare"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_LayerContentsCGImageChainsKeepFramesUsed(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"frame1", "frame2", "frame3"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `layer.contents = UIImage(named: "frame1")?.cgImage
+let animation = CAKeyframeAnimation(keyPath: "contents")
+animation.values = [
+    UIImage(
+        named: "frame2"
+    )?
+    .cgImage as Any,
+]`
+	if err := os.WriteFile(filepath.Join(root, "App", "Animator.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"frame1", "frame2"}) {
+		t.Fatalf("expected cgImage-chained frames to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"frame3"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_CamelCaseResourceCollisionsResolveToOneAsset(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	appCatalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"fooBar", "foo_bar"} {
+		if err := os.MkdirAll(filepath.Join(appCatalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	featureCatalog := filepath.Join(root, "Feature", "Assets.xcassets")
+	otherCatalog := filepath.Join(root, "Other", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(featureCatalog, "bar-baz.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(otherCatalog, "bar_baz.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(`let image = UIImage(resource: .fooBar)`), 0o644); err != nil {
+		t.Fatalf("write app source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Feature", "View.swift"), []byte(`let image = UIImage(resource: .barBaz)`), 0o644); err != nil {
+		t.Fatalf("write feature source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"bar-baz", "fooBar"}) {
+		t.Fatalf("expected each collision to resolve to one asset, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"bar_baz", "foo_bar"}) {
+		t.Fatalf("expected colliding variants to stay unused, got unused %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_ObjCPropertyAssignmentSplitAcrossLines(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, name := range []string{"hero", "badge", "spare"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name+".imageset"), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `- (void)viewDidLoad {
+    self.iconView.image =
+        [UIImage imageNamed:@"hero"];
+    [self.badgeView setImage:[UIImage
+        imageNamed:@"badge"]];
+}`
+	if err := os.WriteFile(filepath.Join(root, "App", "ViewController.m"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write objc source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"badge", "hero"}) {
+		t.Fatalf("expected split property assignments to be used, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"spare"}) {
+		t.Fatalf("unexpected unused assets: %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_HiddenDirectoriesSkippedUnlessIncludeHidden(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "App", "Assets.xcassets", "logo.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".generated", "Generated.xcassets", "badge.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir hidden asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".build"), 0o755); err != nil {
+		t.Fatalf("mkdir hidden source dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".build", "Logo.swift"), []byte(`let logo = UIImage(named: "logo")`), 0o644); err != nil {
+		t.Fatalf("write hidden swift source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 1 || !slices.Equal(res.AssetNames, []string{"logo"}) {
+		t.Fatalf("expected hidden catalog to be skipped by default, got catalogs=%d names=%#v", res.AssetCatalogs, res.AssetNames)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"logo"}) {
+		t.Fatalf("expected hidden source to be skipped by default, got unused %#v", res.UnusedAssets)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, IncludeHidden: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 2 || !slices.Equal(res.AssetNames, []string{"badge", "logo"}) {
+		t.Fatalf("expected hidden catalog with --include-hidden, got catalogs=%d names=%#v", res.AssetCatalogs, res.AssetNames)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"logo"}) {
+		t.Fatalf("expected hidden source to be scanned with --include-hidden, got used %#v", res.UsedAssets)
+	}
+}
+
+func TestScan_HiddenScanRootIsWalked(t *testing.T) {
+	t.Parallel()
+	root := filepath.Join(t.TempDir(), ".generated")
+	if err := os.MkdirAll(filepath.Join(root, "Generated.xcassets", "badge.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.AssetNames, []string{"badge"}) {
+		t.Fatalf("expected an explicitly hidden root to be scanned, got %#v", res.AssetNames)
+	}
+}
+
+func TestScan_SkipsCatalogsInsideBundlesUnlessIncluded(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(root, "App", "Assets.xcassets", "hero.imageset"),
+		filepath.Join(root, "Vendor", "Widgets.bundle", "Assets.xcassets", "hero.imageset"),
+		filepath.Join(root, "Vendor", "Widgets.bundle", "Assets.xcassets", "spinner.imageset"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 1 || !slices.Equal(res.AssetNames, []string{"hero"}) {
+		t.Fatalf("expected only the source catalog by default, got %d catalogs %#v", res.AssetCatalogs, res.AssetNames)
+	}
+	if len(res.DuplicateNames) != 0 {
+		t.Fatalf("expected bundle catalog not to produce duplicates, got %#v", res.DuplicateNames)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, IncludeBundles: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 2 || !slices.Equal(res.AssetNames, []string{"hero", "spinner"}) {
+		t.Fatalf("expected bundle catalog with IncludeBundles, got %d catalogs %#v", res.AssetCatalogs, res.AssetNames)
+	}
+
+	bundleRoot := filepath.Join(root, "Vendor", "Widgets.bundle")
+	res, err = Scan(Options{Root: bundleRoot, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 1 {
+		t.Fatalf("expected a .bundle scan root to be walked, got %d catalogs", res.AssetCatalogs)
+	}
+}
+
+func TestScan_CatalogsAllowlistSkipsNonMatchingXcassetsDirectories(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(root, "App", "Assets.xcassets", "hero.imageset"),
+		filepath.Join(root, "Packages", "Feed", "Feed.xcassets", "feed.imageset"),
+		filepath.Join(root, "Tests", "__Snapshots__", "Home.xcassets", "snapshot.imageset"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 3 {
+		t.Fatalf("expected every .xcassets directory without an allowlist, got %d", res.AssetCatalogs)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, Catalogs: []string{"App/**", "Packages/*/*.xcassets"}})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if res.AssetCatalogs != 2 || !slices.Equal(res.AssetNames, []string{"feed", "hero"}) {
+		t.Fatalf("expected only allowlisted catalogs, got %d catalogs %#v", res.AssetCatalogs, res.AssetNames)
+	}
+}
+
+// extractLabeledReferencesPerLabel is the former per-label regex
+// implementation, kept as the reference for the single-pass extractor.
+func extractLabeledReferencesPerLabel(content string, labelAssetTypes map[string]map[string]struct{}) []sourceAssetReference {
+	labels := slices.Sorted(maps.Keys(labelAssetTypes))
+	seen := make(map[string]struct{})
+	var out []sourceAssetReference
+	for _, label := range labels {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(label) + `\s*:\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			for _, assetType := range slices.Sorted(maps.Keys(labelAssetTypes[label])) {
+				key := sourceAssetTypeKey(m[1], assetType)
+				if _, exists := seen[key]; exists {
+					continue
+				}
+				seen[key] = struct{}{}
+				out = append(out, sourceAssetReference{Name: m[1], AssetType: assetType, Rule: "swift-resource-label", Text: m[0]})
+			}
+		}
+	}
+	return out
+}
+
+func TestExtractSwiftResourceArgumentReferences_MatchesPerLabelScan(t *testing.T) {
+	t.Parallel()
+	labels := map[string]map[string]struct{}{
+		"icon":       {"imageset": {}},
+		"tint":       {"colorset": {}},
+		"artwork":    {"imageset": {}, "colorset": {}},
+		"background": {"colorset": {}},
+	}
+	for _, content := range []string{
+		"",
+		`view.setData(icon: .home, fieldName: "x", tint: .accent)`,
+		`row(artwork: .hero); row(artwork: .hero, icon: .hero)`,
+		`let x = foo.icon: .nested; bigicon: .notALabel; icon:.compact`,
+		"configure(\n    icon:\n        .multiline,\n    background: .surface\n)",
+		`chain(icon: .tint: .doubled)`,
+		`switch value { case .icon: .noop }`,
+		strings.Repeat(`m(a:.b,icon:.x,tint:.y,`, 200),
+	} {
+		want := extractLabeledReferencesPerLabel(content, labels)
+		got := extractSwiftResourceArgumentReferences(content, swiftLabelMemberPairRe, labels, "swift-resource-label")
+		if !slices.Equal(got, want) {
+			t.Fatalf("content %q: single pass %#v differs from per-label scan %#v", content, got, want)
+		}
+	}
+}
+
+// BenchmarkExtractSwiftResourceArgumentReferences measures a minified
+// source with many declared labels, the case that was
+// O(labels × file size) with one regex per label.
+func BenchmarkExtractSwiftResourceArgumentReferences(b *testing.B) {
+	labels := make(map[string]map[string]struct{}, 200)
+	var content strings.Builder
+	for i := range 200 {
+		label := fmt.Sprintf("label%d", i)
+		labels[label] = map[string]struct{}{"imageset": {}}
+		fmt.Fprintf(&content, "f(%s:.member%d,other%d:.value);", label, i, i)
+	}
+	source := strings.Repeat(content.String(), 5)
+
+	b.Run("single-pass", func(b *testing.B) {
+		for b.Loop() {
+			extractSwiftResourceArgumentReferences(source, swiftLabelMemberPairRe, labels, "swift-resource-label")
+		}
+	})
+	b.Run("per-label", func(b *testing.B) {
+		for b.Loop() {
+			extractLabeledReferencesPerLabel(source, labels)
+		}
+	})
+}
+
+// writeSyntheticCatalogTree creates modules module directories, each with a
+// few catalogs, group folders and asset sets, plus the excluded, hidden and
+// bundle directories discovery must skip.
+func writeSyntheticCatalogTree(tb testing.TB, root string, modules int) {
+	tb.Helper()
+	dirs := []string{
+		filepath.Join(root, "Assets.xcassets", "rootIcon.imageset"),
+		filepath.Join(root, "Pods", "Lib", "Lib.xcassets", "podIcon.imageset"),
+		filepath.Join(root, ".hidden", "Hidden.xcassets", "hiddenIcon.imageset"),
+		filepath.Join(root, "Vendor", "Res.bundle", "Bundle.xcassets", "bundleIcon.imageset"),
+	}
+	for m := range modules {
+		module := filepath.Join(root, fmt.Sprintf("Module%03d", m))
+		for c := range 3 {
+			catalog := filepath.Join(module, "Sources", fmt.Sprintf("Catalog%d.xcassets", c))
+			for a := range 5 {
+				dirs = append(dirs,
+					filepath.Join(catalog, fmt.Sprintf("icon%d_%d.imageset", c, a)),
+					filepath.Join(catalog, "Group", fmt.Sprintf("tint%d.colorset", a)),
+				)
+			}
+		}
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			tb.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("x"), 0o644); err != nil {
+		tb.Fatalf("write root file: %v", err)
+	}
+}
+
+func TestCollectAssets_ParallelCatalogsMatchesSerialDiscovery(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeSyntheticCatalogTree(t, root, 40)
+	maxDepth := 2
+
+	for _, opts := range []Options{
+		{Root: root, Workers: 4, Exclude: []string{"Pods/"}},
+		{Root: root, Workers: 4, IncludeHidden: true, IncludeBundles: true},
+		{Root: root, Workers: 1, MaxDepth: &maxDepth},
+		{Root: root, Workers: 3, Catalogs: []string{"**/Catalog1.xcassets"}},
+		{Root: filepath.Join(root, "Assets.xcassets"), Workers: 2},
+	} {
+		serialCatalogs, serialNames, serialAssets, err := collectAssets(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("serial discovery: %v", err)
+		}
+		opts.ParallelCatalogs = true
+		catalogs, names, assets, err := collectAssets(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("parallel discovery: %v", err)
+		}
+		if !reflect.DeepEqual(catalogs, serialCatalogs) || !reflect.DeepEqual(names, serialNames) || !reflect.DeepEqual(assets, serialAssets) {
+			t.Fatalf("%+v: parallel discovery differs from serial: %d/%d catalogs, %d/%d names, %d/%d assets", opts, len(catalogs), len(serialCatalogs), len(names), len(serialNames), len(assets), len(serialAssets))
+		}
+		if len(serialAssets) == 0 {
+			t.Fatalf("%+v: expected the synthetic tree to have asset sets", opts)
+		}
+	}
+}
+
+func BenchmarkCollectAssets(b *testing.B) {
+	root := b.TempDir()
+	writeSyntheticCatalogTree(b, root, 200)
+	for _, parallel := range []bool{false, true} {
+		b.Run(fmt.Sprintf("parallel=%t", parallel), func(b *testing.B) {
+			opts := Options{Root: root, Workers: runtime.NumCPU(), ParallelCatalogs: parallel}
+			for b.Loop() {
+				if _, _, _, err := collectAssets(context.Background(), opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchesAny_GlobPatternMatchesExpectedPath(t *testing.T) {
+	t.Parallel()
+	if !matchesAny("App/Main.swift", []string{"App/*.swift"}) {
+		t.Fatalf("expected glob to match path")
+	}
+}
+
+func TestMatchesAny_GlobstarPatternMatchesNestedPath(t *testing.T) {
+	t.Parallel()
+	if !matchesAny("Sources/App/Features/Home/View.swift", []string{"Sources/**/*.swift"}) {
+		t.Fatalf("expected globstar pattern to match nested path")
+	}
+}
+
+func TestMatchesAny_DirectoryPatternMatchesSubtree(t *testing.T) {
+	t.Parallel()
+	if !matchesAny("ExternalLib/Assets.xcassets/icon.imageset", []string{"ExternalLib/"}) {
+		t.Fatalf("expected directory pattern to match subtree")
+	}
+}
+
+func TestMatchesAny_DoesNotUseSubstringFallback(t *testing.T) {
+	t.Parallel()
+	if matchesAny("MyExternalLib/Assets.xcassets/icon.imageset", []string{"ExternalLib/"}) {
+		t.Fatalf("did not expect substring overlap to match")
+	}
+}
+
+func TestSwiftResourceCandidatesForAsset_HandlesMultibyteCamelCaseParts(t *testing.T) {
+	t.Parallel()
+	candidates := swiftResourceCandidatesForAsset("primary_äpfel", "imageset")
 
 	if !slices.Contains(candidates, "primaryÄpfel") {
 		t.Fatalf("expected utf-8 aware camel candidate, got %#v", candidates)
 	}
 }
+
+func TestSearchedCandidates_IncludesCamelCaseVariantForDashedName(t *testing.T) {
+	t.Parallel()
+	candidates := SearchedCandidates(filepath.Join("App", "Assets.xcassets", "tab-bar-home.imageset"))
+
+	if len(candidates) == 0 || candidates[0] != "tab-bar-home" {
+		t.Fatalf("expected the asset name first, got %#v", candidates)
+	}
+	if !slices.Contains(candidates, "tabBarHome") {
+		t.Fatalf("expected camelCase candidate, got %#v", candidates)
+	}
+	if icon := SearchedCandidates(filepath.Join("App", "Assets.xcassets", "app-icon.appiconset")); !slices.Equal(icon, []string{"app-icon"}) {
+		t.Fatalf("expected app icon sets to have no symbol candidates, got %#v", icon)
+	}
+}
+
+func TestExtractODRRequests_ReadsSwiftAndObjCTagSets(t *testing.T) {
+	t.Parallel()
+	swift := `let request = NSBundleResourceRequest(tags: ["level1", "level2"])
+let other = NSBundleResourceRequest(tags: Set(["bonus"]))`
+	objc := `NSBundleResourceRequest *request = [[NSBundleResourceRequest alloc] initWithTags:[NSSet setWithObjects:@"intro", @"outro", nil]];`
+
+	var tags []string
+	for _, content := range []string{swift, objc} {
+		for _, request := range extractODRRequests("Source", usageScopeProduction, content) {
+			tags = append(tags, request.Tag)
+		}
+	}
+	if !slices.Equal(tags, []string{"level1", "level2", "bonus", "intro", "outro"}) {
+		t.Fatalf("unexpected requested tags: %#v", tags)
+	}
+}
+
+func TestScan_ODRTaggedAssetsUsedByRequestOrFlag(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	tagsByAsset := map[string]string{
+		"boss.imageset":    `{"info":{"version":1,"author":"xcode"},"properties":{"on-demand-resource-tags":["level1"]}}`,
+		"credits.imageset": `{"info":{"version":1,"author":"xcode"},"properties":{"on-demand-resource-tags":["ending"]}}`,
+		"spare.imageset":   `{"info":{"version":1,"author":"xcode"}}`,
+	}
+	for dir, contents := range tagsByAsset {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(catalog, dir, "Contents.json"), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write Contents.json: %v", err)
+		}
+	}
+	source := `let request = NSBundleResourceRequest(tags: ["level1"])`
+	if err := os.WriteFile(filepath.Join(root, "App", "Levels.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2, TrackReferences: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"boss"}) {
+		t.Fatalf("expected the requested tag to mark boss used, got used %#v", res.UsedAssets)
+	}
+	if refs := res.References["boss"]; len(refs) != 1 || refs[0].Rule != "odr-resource-request" {
+		t.Fatalf("unexpected boss references: %#v", refs)
+	}
+
+	res, err = Scan(Options{Root: root, Workers: 2, ODRTagsUsed: true})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	if !slices.Equal(res.UsedAssets, []string{"boss", "credits"}) {
+		t.Fatalf("expected every tagged asset to be used with ODRTagsUsed, got used %#v", res.UsedAssets)
+	}
+	if !slices.Equal(res.UnusedAssets, []string{"spare"}) {
+		t.Fatalf("expected untagged asset to stay unused, got %#v", res.UnusedAssets)
+	}
+}
+
+func TestScan_ReportsTypeMismatchedReferences(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	catalog := filepath.Join(root, "App", "Assets.xcassets")
+	for _, dir := range []string{"hero.imageset", "accent.colorset", "brand.colorset", "brand.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let tint = Color("hero")
+let accent = Color("accent")
+let brand = Color("brand")
+let missing = Color("missing")`
+	sourcePath := filepath.Join(root, "App", "Theme.swift")
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	res, err := Scan(Options{Root: root, Workers: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	want := []TypeMismatch{{Name: "hero", RequestedType: "colorset", ExistingTypes: []string{"imageset"}, Source: sourcePath, Rule: "swiftui-color"}}
+	if !reflect.DeepEqual(res.TypeMismatches, want) {
+		t.Fatalf("expected type mismatches %#v, got %#v", want, res.TypeMismatches)
+	}
+	if slices.Contains(res.UsedAssets, "hero") {
+		t.Fatalf("expected a mismatched reference not to mark hero used, got %#v", res.UsedAssets)
+	}
+}
@@ -1,18 +1,115 @@
 package assets
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 )
 
+// ErrReadFailure is wrapped by errors returned when a source file cannot be read.
+var ErrReadFailure = errors.New("failed to read file")
+
+var errInvalidUTF8 = errors.New("invalid UTF-8 encoding")
+
 func osReadFile(path string) (string, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: %w", ErrReadFailure, err)
 	}
 	if !utf8.Valid(b) {
-		return "", fmt.Errorf("invalid UTF-8 encoding in %s", path)
+		return "", fmt.Errorf("%w: %w in %s", ErrReadFailure, errInvalidUTF8, path)
 	}
 	return string(b), nil
 }
+
+// readSwiftStringLiteral decodes the single-line Swift string literal whose
+// body starts at content[start], just past the opening quote. It returns the
+// value with escape sequences resolved and the index just past the closing
+// quote. ok is false for unterminated literals and for interpolated ones,
+// whose value is not known statically.
+func readSwiftStringLiteral(content string, start int) (value string, end int, ok bool) {
+	var b strings.Builder
+	for i := start; i < len(content); {
+		switch c := content[i]; c {
+		case '"':
+			return b.String(), i + 1, true
+		case '\n', '\r':
+			return "", 0, false
+		case '\\':
+			if i+1 >= len(content) {
+				return "", 0, false
+			}
+			switch escaped := content[i+1]; escaped {
+			case '\\', '"', '\'':
+				b.WriteByte(escaped)
+			case '0':
+				b.WriteByte(0)
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u':
+				// \u{XXXX} takes one to eight hex digits.
+				body, _, found := strings.Cut(content[i+2:], "}")
+				if !found || !strings.HasPrefix(body, "{") {
+					return "", 0, false
+				}
+				code, err := strconv.ParseUint(body[1:], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(code)) {
+					return "", 0, false
+				}
+				b.WriteRune(rune(code))
+				i += 2 + len(body) + 1
+				continue
+			default:
+				// Includes \( interpolation.
+				return "", 0, false
+			}
+			i += 2
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, false
+}
+
+// skipSwiftCallArguments expects content[start:] to follow the first argument
+// of a call: either the closing parenthesis or a comma and further arguments.
+// It returns the index just past the call's closing parenthesis, balancing
+// nested parentheses and skipping string literals, or ok false when the next
+// token is neither or the call never closes.
+func skipSwiftCallArguments(content string, start int) (end int, ok bool) {
+	i := start
+	for i < len(content) && strings.ContainsRune(" \t\r\n", rune(content[i])) {
+		i++
+	}
+	if i >= len(content) || (content[i] != ')' && content[i] != ',') {
+		return 0, false
+	}
+	depth := 0
+	inString := false
+	for ; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return i + 1, true
+			}
+			depth--
+		}
+	}
+	return 0, false
+}
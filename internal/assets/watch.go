@@ -0,0 +1,140 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long Watch waits after the last filesystem
+// event before re-scanning.
+const DefaultWatchDebounce = 300 * time.Millisecond
+
+// Watch scans opts.Root, passes the result to onResult, and then re-scans
+// whenever files below the root change until ctx is done. Bursts of events
+// are coalesced: a re-scan starts once no event has arrived for debounce.
+// Directories skipped by the scan (excluded, hidden or beyond MaxDepth) are
+// not watched. Each re-scan covers the whole tree, except that a burst which
+// leaves every path the scan reads as the previous scan saw it, such as an
+// editor swap file created and removed again, reuses that result with
+// CacheHit set. Watch returns nil when ctx is canceled, and the first error
+// from scanning, watching or onResult otherwise.
+func Watch(ctx context.Context, opts Options, debounce time.Duration, onResult func(Result) error) error {
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirs(watcher, opts, opts.Root); err != nil {
+		return err
+	}
+	var lastKey string
+	var lastResult Result
+	rescan := func() error {
+		key, err := scanCacheKey(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if key == lastKey {
+			lastResult.CacheHit = true
+			return onResult(lastResult)
+		}
+		result, err := ScanContext(ctx, opts)
+		if err != nil {
+			return err
+		}
+		lastKey, lastResult = key, result
+		return onResult(result)
+	}
+	if err := rescan(); err != nil {
+		return ignoreCanceled(ctx, err)
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !isWatchedEvent(opts, event) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				// New directories are not covered by their parent's watch.
+				if err := addWatchDirs(watcher, opts, event.Name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+			}
+			timer.Reset(debounce)
+		case <-timer.C:
+			if err := rescan(); err != nil {
+				return ignoreCanceled(ctx, err)
+			}
+		}
+	}
+}
+
+// addWatchDirs watches start and every directory below it that a scan of
+// opts would walk. start may be a file, in which case nothing is added.
+func addWatchDirs(watcher *fsnotify.Watcher, opts Options, start string) error {
+	return filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(opts.Root, path)
+		if relErr != nil {
+			return relErr
+		}
+		if isSkippedHiddenDir(rel, d.Name(), opts.IncludeHidden) || exceedsMaxDepth(rel, opts.MaxDepth) || matchesAny(rel, opts.Exclude) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// isWatchedEvent reports whether event can change a scan result. Attribute
+// changes, paths excluded from the scan and, as in the scan, files that the
+// include globs leave out are ignored.
+func isWatchedEvent(opts Options, event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	rel, err := filepath.Rel(opts.Root, event.Name)
+	if err != nil || matchesAny(rel, opts.Exclude) {
+		return false
+	}
+	if len(opts.Include) > 0 && !matchesAny(rel, opts.Include) {
+		// The scan still walks directories the globs leave out.
+		info, err := os.Stat(event.Name)
+		return err == nil && info.IsDir()
+	}
+	return true
+}
+
+func ignoreCanceled(ctx context.Context, err error) error {
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
+}
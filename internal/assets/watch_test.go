@@ -0,0 +1,96 @@
+package assets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWatch_RescansAfterSourceChange(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for _, name := range []string{"hero.imageset", "logo.imageset"} {
+		if err := os.MkdirAll(filepath.Join(root, "App", "Assets.xcassets", name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	for _, dir := range []string{"build", "Docs"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatalf("mkdir %s dir: %v", dir, err)
+		}
+	}
+	sourcePath := filepath.Join(root, "App", "View.swift")
+	if err := os.WriteFile(sourcePath, []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	results := make(chan Result, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, Options{Root: root, Workers: 2, Include: []string{"App/**"}, Exclude: []string{"build/"}}, 20*time.Millisecond, func(res Result) error {
+			results <- res
+			return nil
+		})
+	}()
+
+	next := func() Result {
+		t.Helper()
+		select {
+		case res := <-results:
+			return res
+		case err := <-done:
+			t.Fatalf("watch stopped early: %v", err)
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for a scan")
+		}
+		return Result{}
+	}
+
+	if res := next(); !slices.Equal(res.UnusedAssets, []string{"logo"}) {
+		t.Fatalf("expected initial scan to report logo unused, got %#v", res.UnusedAssets)
+	}
+
+	// Changes under an excluded directory or to files outside the include
+	// globs must not trigger a re-scan.
+	if err := os.WriteFile(filepath.Join(root, "build", "Generated.swift"), []byte(`let image = UIImage(named: "logo")`), 0o644); err != nil {
+		t.Fatalf("write excluded source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Docs", "Sample.swift"), []byte(`let image = UIImage(named: "logo")`), 0o644); err != nil {
+		t.Fatalf("write source outside include: %v", err)
+	}
+	select {
+	case res := <-results:
+		t.Fatalf("expected no re-scan for excluded or non-included changes, got %#v", res.UnusedAssets)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// A file created and removed again within one burst leaves the tree as
+	// the last scan saw it, so its result is reused.
+	swapPath := filepath.Join(root, "App", "View.swift.tmp")
+	if err := os.WriteFile(swapPath, nil, 0o644); err != nil {
+		t.Fatalf("write swap file: %v", err)
+	}
+	if err := os.Remove(swapPath); err != nil {
+		t.Fatalf("remove swap file: %v", err)
+	}
+	if res := next(); !res.CacheHit || !slices.Equal(res.UnusedAssets, []string{"logo"}) {
+		t.Fatalf("expected the previous result to be reused, got hit=%v unused=%#v", res.CacheHit, res.UnusedAssets)
+	}
+
+	if err := os.WriteFile(sourcePath, []byte(`let images = [UIImage(named: "hero"), UIImage(named: "logo")]`), 0o644); err != nil {
+		t.Fatalf("rewrite source: %v", err)
+	}
+	if res := next(); res.CacheHit || len(res.UnusedAssets) != 0 {
+		t.Fatalf("expected re-scan to find no unused assets, got %#v", res.UnusedAssets)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil error after cancel, got %v", err)
+	}
+}
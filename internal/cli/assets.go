@@ -1,17 +1,27 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"slices"
 	"strconv"
 	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/spf13/cobra"
@@ -35,56 +45,443 @@ func newAssetsCommand(ctx *runContext) *cobra.Command {
 	cmd.AddCommand(newAssetsScanCommand(ctx))
 	cmd.AddCommand(newAssetsUnusedCommand(ctx))
 	cmd.AddCommand(newAssetsPruneCommand(ctx))
+	cmd.AddCommand(newAssetsWatchCommand(ctx))
+	cmd.AddCommand(newAssetsDoctorCommand(ctx))
 
 	return cmd
 }
 
 type scanResult struct {
-	Command string   `json:"command"`
-	Path    string   `json:"path"`
-	Include []string `json:"include"`
-	Exclude []string `json:"exclude"`
-	Workers int      `json:"workers"`
-	Summary struct {
-		AssetCatalogs int `json:"assetCatalogs"`
-		AssetSets     int `json:"assetSets"`
-		UsedAssets    int `json:"usedAssets"`
-		UnusedAssets  int `json:"unusedAssets"`
+	Command string `json:"command"`
+	Path    string `json:"path"`
+	// InputPath is the raw --path value before tilde expansion and
+	// resolution to the absolute Path.
+	InputPath string   `json:"inputPath"`
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+	// EffectiveExclude is the exclude set the scan applied: the defaults or
+	// --exclude patterns as matched, root-relative and de-duplicated.
+	EffectiveExclude []string `json:"effectiveExclude"`
+	Workers          int      `json:"workers"`
+	// DurationMs is the scan wall time; omitted under --deterministic.
+	DurationMs *int64 `json:"durationMs,omitempty"`
+	Summary    struct {
+		AssetCatalogs  int `json:"assetCatalogs"`
+		AssetSets      int `json:"assetSets"`
+		UsedAssets     int `json:"usedAssets"`
+		UnusedAssets   int `json:"unusedAssets"`
+		EmptyAssetSets int `json:"emptyAssetSets"`
 	} `json:"summary"`
+	// AssetTypeBreakdown maps each asset type to its asset-set counts; like
+	// modules, counts are per asset set rather than per distinct name.
+	AssetTypeBreakdown map[string]assetTypeCountResult `json:"assetTypeBreakdown"`
+	// DuplicateNames is only populated when --warn-duplicate-names is set.
+	DuplicateNames []duplicateNameResult `json:"duplicateNames,omitempty"`
+	// CaseCollisions is only populated when --warn-case-collisions is set.
+	CaseCollisions []caseCollisionResult `json:"caseCollisions,omitempty"`
+	// TypeMismatches is only populated when --warn-type-mismatch is set.
+	TypeMismatches []typeMismatchResult `json:"typeMismatches,omitempty"`
+	// EmptyAssetSets is only populated when --list-empty is set.
+	EmptyAssetSets []string `json:"emptyAssetSets,omitempty"`
+	// EmptyCatalogs is only populated when --fail-on-empty-catalog is set.
+	EmptyCatalogs []string `json:"emptyCatalogs,omitempty"`
+	// UnusedByFile is only populated when --with-unused is set; it matches
+	// the unusedByFile detail of `assets unused`.
+	UnusedByFile map[string]unusedFileResult `json:"unusedByFile,omitempty"`
+	// Truncated is set when --max-results dropped unusedByFile entries; the
+	// summary counts still cover every asset.
+	Truncated bool `json:"truncated,omitempty"`
+	// TotalUnused is set with --max-results to the number of unused asset
+	// sets before truncation.
+	TotalUnused *int `json:"totalUnused,omitempty"`
+	// Modules is only populated when --group-by-module is set.
+	Modules []moduleResult `json:"modules,omitempty"`
+	// RuleMatchCounts is only populated when --report-rule-stats is set.
+	RuleMatchCounts map[string]int `json:"ruleMatchCounts,omitempty"`
+	// UnusedExplained is only populated when --explain-unused is set.
+	UnusedExplained []unusedExplanationResult `json:"unusedExplained,omitempty"`
+	// Warnings collects one entry per finding of the enabled checks
+	// (--warn-duplicate-names, --warn-case-collisions, --warn-type-mismatch,
+	// --fail-on-empty-catalog, --list-empty) in a single shape.
+	Warnings []scanWarningResult `json:"warnings,omitempty"`
+	// wide holds the extra table columns requested with --wide; it is never
+	// part of the JSON payload.
+	wide *scanWideDetails
 }
 
-func runAssetScan(path string, include []string, exclude []string, workers int) (string, []string, []string, assets.Result, error) {
-	resolvedPath, err := resolveScanPath(path)
+type scanWideDetails struct {
+	DuplicateNames int
+	EmptyCatalogs  int
+	Catalogs       []catalogResult
+}
+
+type explainResult struct {
+	Command    string            `json:"command"`
+	Asset      string            `json:"asset"`
+	Used       bool              `json:"used"`
+	References []referenceResult `json:"references"`
+}
+
+type referenceResult struct {
+	Source string `json:"source"`
+	Rule   string `json:"rule"`
+	Text   string `json:"text"`
+}
+
+type catalogsResult struct {
+	Command  string          `json:"command"`
+	Path     string          `json:"path"`
+	Catalogs []catalogResult `json:"catalogs"`
+}
+
+type catalogResult struct {
+	Path      string `json:"path"`
+	AssetSets int    `json:"assetSets"`
+}
+
+// assetTypeCountResult holds the asset-set counts for one asset type.
+type assetTypeCountResult struct {
+	AssetSets    int `json:"assetSets"`
+	UsedAssets   int `json:"usedAssets"`
+	UnusedAssets int `json:"unusedAssets"`
+}
+
+// moduleResult aggregates asset-set counts for the catalogs below one module
+// root: the nearest directory containing Package.swift or an .xcodeproj.
+type moduleResult struct {
+	Path         string `json:"path"`
+	AssetSets    int    `json:"assetSets"`
+	UsedAssets   int    `json:"usedAssets"`
+	UnusedAssets int    `json:"unusedAssets"`
+}
+
+// unusedExplanationResult lists the identifier forms a scan searched for
+// without finding a reference to one unused asset set.
+type unusedExplanationResult struct {
+	Name               string   `json:"name"`
+	Path               string   `json:"path"`
+	SearchedCandidates []string `json:"searchedCandidates"`
+}
+
+type duplicateNameResult struct {
+	Name      string   `json:"name"`
+	AssetType string   `json:"assetType"`
+	Catalogs  []string `json:"catalogs"`
+}
+
+type caseCollisionResult struct {
+	Catalog   string   `json:"catalog"`
+	AssetType string   `json:"assetType"`
+	Names     []string `json:"names"`
+}
+
+// scanWarningResult is a non-fatal scan finding. Path is the catalog, asset
+// set or source file the warning is about, when there is a single one.
+type scanWarningResult struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Path    string `json:"path,omitempty"`
+}
+
+type typeMismatchResult struct {
+	Name          string   `json:"name"`
+	RequestedType string   `json:"requestedType"`
+	ExistingTypes []string `json:"existingTypes"`
+	Source        string   `json:"source"`
+	Rule          string   `json:"rule"`
+}
+
+// assetScanFlags holds the scan scope flags shared by scan and unused.
+type assetScanFlags struct {
+	path               string
+	include            []string
+	exclude            []string
+	generated          []string
+	assetsFrom         []string
+	workers            int
+	dynamicNames       bool
+	maxDepth           int
+	scanBundleResource bool
+	scanDocC           bool
+	scanHTML           bool
+	scanAttributedMD   bool
+	scanLocalizedKeys  bool
+	resolveLocalized   bool
+	scanDefaults       bool
+	includeHidden      bool
+	includeBundles     bool
+	scanKeyPaths       bool
+	keyPathAccessors   []string
+	objcColorSelectors []string
+	ibAttributes       []string
+	ibDesignable       bool
+	odrTagsUsed        bool
+	activeConfig       string
+	parallelCatalogs   bool
+	cacheDir           string
+	cacheKey           string
+	// trackReferences is set by commands that report reference provenance.
+	trackReferences bool
+	// ruleStats is set by commands that report per-rule match counts.
+	ruleStats bool
+
+	cmd *cobra.Command
+}
+
+func (f *assetScanFlags) register(cmd *cobra.Command) {
+	f.cmd = cmd
+	cmd.Flags().StringVar(&f.path, "path", ".", "Path to scan")
+	cmd.Flags().StringSliceVar(&f.include, "include", nil, "Include path globs")
+	cmd.Flags().StringSliceVar(&f.exclude, "exclude", append([]string{}, defaultExcludedPaths...), "Exclude path globs (replaces defaults; repeatable, comma-separated)")
+	cmd.Flags().StringSliceVar(&f.generated, "exclude-generated", nil, "Generated accessor file globs (SwiftGen, R.swift) whose asset names do not count as references; their accessors still count where used")
+	cmd.Flags().StringSliceVar(&f.assetsFrom, "assets-from", nil, "Only treat .xcassets directories matching these globs as asset catalogs (repeatable, comma-separated)")
+	cmd.Flags().IntVar(&f.workers, "workers", defaultWorkers(), "Worker count")
+	cmd.Flags().BoolVar(&f.parallelCatalogs, "parallel-catalogs", false, "Discover asset catalogs by walking each top-level directory concurrently (bounded by --workers; same results)")
+	cmd.Flags().BoolVar(&f.dynamicNames, "dynamic-names", false, "Treat assets matching the static prefix/suffix of interpolated Swift names as used")
+	cmd.Flags().IntVar(&f.maxDepth, "max-depth", 0, "Maximum directory depth below --path to walk; 0 scans only files in --path (default unlimited)")
+	cmd.Flags().BoolVar(&f.scanBundleResource, "scan-bundle-resource", false, "Treat assets named by Swift Bundle forResource: lookups as used (lower confidence)")
+	cmd.Flags().BoolVar(&f.scanLocalizedKeys, "scan-localized-keys", false, "Treat assets named by Swift String(localized:) keys as used (heuristic, lower confidence)")
+	cmd.Flags().BoolVar(&f.resolveLocalized, "resolve-localized-names", false, "Treat the key of NSLocalizedString(\"key\", ...) passed to UIImage(named:) as an image set name (heuristic, lower confidence)")
+	cmd.Flags().BoolVar(&f.scanKeyPaths, "scan-keypaths", false, "Resolve key path arguments to design-system accessors, e.g. icons[\\.home], to generated resource identifiers (heuristic)")
+	cmd.Flags().StringSliceVar(&f.keyPathAccessors, "keypath-accessor", append([]string{}, defaultKeyPathAccessors...), "Accessor names checked by --scan-keypaths (replaces defaults; repeatable, comma-separated)")
+	cmd.Flags().StringSliceVar(&f.objcColorSelectors, "objc-color-selectors", nil, "Objective-C selectors such as colorNamed: whose @\"...\" argument names a color set on any receiver, e.g. a theme class wrapping UIColor (repeatable, comma-separated)")
+	cmd.Flags().StringVar(&f.activeConfig, "active-config", "", "Only count Swift references in #if branches compiled when this is the only custom compilation condition, e.g. RELEASE (default counts every branch)")
+	cmd.Flags().BoolVar(&f.odrTagsUsed, "odr-tags-used", false, "Treat every asset set with On-Demand Resources tags as used (tagged sets requested by NSBundleResourceRequest are always used)")
+	cmd.Flags().BoolVar(&f.ibDesignable, "ibdesignable", false, "Resolve storyboard/XIB runtime attributes: image values, and string values for IBInspectable properties named like imageName or iconName")
+	cmd.Flags().StringSliceVar(&f.ibAttributes, "ib-attr", nil, "Extra Interface Builder attribute whose value names an asset, as name=type, e.g. iconName=imageset (repeatable, comma-separated)")
+	cmd.Flags().BoolVar(&f.includeHidden, "include-hidden", false, "Also walk dot-directories such as .generated (.git is always skipped)")
+	cmd.Flags().BoolVar(&f.includeBundles, "include-bundles", false, "Also discover asset catalogs inside .bundle directories (skipped by default as packaged resources)")
+	cmd.Flags().BoolVar(&f.scanDefaults, "scan-defaults", false, "Treat image sets named by @AppStorage or UserDefaults register(defaults:) string defaults as used (heuristic, lower confidence)")
+	cmd.Flags().BoolVar(&f.scanDocC, "scan-docc", false, "Scan DocC .md/.tutorial files for @Image(source:) and markdown image references")
+	cmd.Flags().BoolVar(&f.scanHTML, "scan-html", false, "Scan .html/.htm help pages for <img src> references to image sets")
+	cmd.Flags().BoolVar(&f.scanAttributedMD, "scan-attributed-markdown", false, "Scan Swift sources for markdown image syntax such as ![](hero) rendered by AttributedString(markdown:) and match it to image sets")
+	cmd.Flags().StringVar(&f.cacheDir, "cache-dir", "", "Reuse scan results stored in this directory while no scanned file changes (default no cache)")
+	cmd.Flags().StringVar(&f.cacheKey, "cache-key", assets.CacheKeyMTime, "How --cache-dir detects file changes: mtime, or git to use blob IDs of tracked files so CI checkouts that reset mtimes still hit")
+}
+
+// defaultKeyPathAccessors are the design-system accessor names checked by
+// --scan-keypaths unless --keypath-accessor replaces them.
+var defaultKeyPathAccessors = []string{"color", "colors", "icon", "icons", "image", "images"}
+
+var keyPathAccessorNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// keyPathAccessorsOption returns the sorted accessor names when
+// --scan-keypaths is set, and nil otherwise.
+func (f *assetScanFlags) keyPathAccessorsOption() ([]string, error) {
+	changed := f.cmd != nil && f.cmd.Flags().Changed("keypath-accessor")
+	if !f.scanKeyPaths {
+		if changed {
+			return nil, usageError{Message: "--keypath-accessor requires --scan-keypaths"}
+		}
+		return nil, nil
+	}
+	accessors := make([]string, 0, len(f.keyPathAccessors))
+	for _, accessor := range f.keyPathAccessors {
+		accessor = strings.TrimSpace(accessor)
+		if !keyPathAccessorNameRe.MatchString(accessor) {
+			return nil, usageError{Message: fmt.Sprintf("invalid value for --keypath-accessor: %q (must be a Swift identifier)", accessor)}
+		}
+		accessors = append(accessors, accessor)
+	}
+	slices.Sort(accessors)
+	return slices.Compact(accessors), nil
+}
+
+var objcSelectorNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*:?$`)
+
+// objcColorSelectorsOption returns the sorted --objc-color-selectors names
+// without their trailing colon, or nil when none are set.
+func (f *assetScanFlags) objcColorSelectorsOption() ([]string, error) {
+	if len(f.objcColorSelectors) == 0 {
+		return nil, nil
+	}
+	selectors := make([]string, 0, len(f.objcColorSelectors))
+	for _, selector := range f.objcColorSelectors {
+		selector = strings.TrimSpace(selector)
+		if !objcSelectorNameRe.MatchString(selector) {
+			return nil, usageError{Message: fmt.Sprintf("invalid value for --objc-color-selectors: %q (must be a one-argument Objective-C selector such as colorNamed:)", selector)}
+		}
+		selectors = append(selectors, strings.TrimSuffix(selector, ":"))
+	}
+	slices.Sort(selectors)
+	return slices.Compact(selectors), nil
+}
+
+var ibAttributeNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ibAttributesOption parses the --ib-attr name=type pairs into the scanner's
+// attribute map, returning nil when none are set.
+func (f *assetScanFlags) ibAttributesOption() (map[string]string, error) {
+	if len(f.ibAttributes) == 0 {
+		return nil, nil
+	}
+	attributes := make(map[string]string, len(f.ibAttributes))
+	for _, value := range f.ibAttributes {
+		name, assetType, ok := strings.Cut(strings.TrimSpace(value), "=")
+		name = strings.TrimSpace(name)
+		assetType = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(assetType)), ".")
+		if !ok || !ibAttributeNameRe.MatchString(name) {
+			return nil, usageError{Message: fmt.Sprintf("invalid value for --ib-attr: %q (must be name=type)", value)}
+		}
+		if !isPrunableAssetSetPath("x." + assetType) {
+			return nil, usageError{Message: fmt.Sprintf("invalid value for --ib-attr: %q (allowed types: imageset, colorset, dataset, appiconset, symbolset, textureset)", value)}
+		}
+		if existing, exists := attributes[name]; exists && existing != assetType {
+			return nil, usageError{Message: fmt.Sprintf("invalid value for --ib-attr: %q (attribute %s is already mapped to %s)", value, name, existing)}
+		}
+		attributes[name] = assetType
+	}
+	return attributes, nil
+}
+
+// activeConfigOption validates --active-config as a compilation condition
+// name; empty keeps every #if branch.
+func (f *assetScanFlags) activeConfigOption() (string, error) {
+	activeConfig := strings.TrimSpace(f.activeConfig)
+	if activeConfig == "" && (f.cmd == nil || !f.cmd.Flags().Changed("active-config")) {
+		return "", nil
+	}
+	if !keyPathAccessorNameRe.MatchString(activeConfig) || activeConfig == "true" || activeConfig == "false" {
+		return "", usageError{Message: fmt.Sprintf("invalid value for --active-config: %q (must be a compilation condition name such as RELEASE)", f.activeConfig)}
+	}
+	return activeConfig, nil
+}
+
+// cacheKeyOption validates --cache-key, which only applies with --cache-dir.
+func (f *assetScanFlags) cacheKeyOption() (string, error) {
+	if f.cacheKey != assets.CacheKeyMTime && f.cacheKey != assets.CacheKeyGit {
+		return "", usageError{Message: fmt.Sprintf("invalid value for --cache-key: %q (allowed: mtime, git)", f.cacheKey)}
+	}
+	if f.cacheDir == "" && f.cmd != nil && f.cmd.Flags().Changed("cache-key") {
+		return "", usageError{Message: "--cache-key requires --cache-dir"}
+	}
+	return f.cacheKey, nil
+}
+
+// maxDepthOption returns nil unless --max-depth was set explicitly.
+func (f *assetScanFlags) maxDepthOption() (*int, error) {
+	if f.cmd == nil || !f.cmd.Flags().Changed("max-depth") {
+		return nil, nil
+	}
+	if f.maxDepth < 0 {
+		return nil, usageError{Message: "invalid value for --max-depth: must be >= 0"}
+	}
+	maxDepth := f.maxDepth
+	return &maxDepth, nil
+}
+
+// durationMs returns the milliseconds elapsed since start for a result's
+// durationMs field, or nil under --deterministic so reruns compare equal.
+func (ctx *runContext) durationMs(start time.Time) *int64 {
+	if ctx.deterministic {
+		return nil
+	}
+	elapsed := time.Since(start).Milliseconds()
+	return &elapsed
+}
+
+func runAssetScan(flags assetScanFlags) (string, []string, []string, assets.Result, error) {
+	opts, err := flags.scanOptions()
+	if err != nil {
+		return "", nil, nil, assets.Result{}, err
+	}
+
+	scan, err := assets.Scan(opts)
 	if err != nil {
 		return "", nil, nil, assets.Result{}, err
 	}
 
-	if workers < 1 {
-		return "", nil, nil, assets.Result{}, usageError{Message: "invalid value for --workers: must be >= 1"}
+	return opts.Root, opts.Include, opts.Exclude, scan, nil
+}
+
+// scanOptions validates the scan flags and resolves them into scanner
+// options with a resolved root and sorted include/exclude patterns.
+func (f *assetScanFlags) scanOptions() (assets.Options, error) {
+	resolvedPath, err := resolveScanPath(f.path)
+	if err != nil {
+		return assets.Options{}, err
+	}
+
+	if f.workers < 1 {
+		return assets.Options{}, usageError{Message: "invalid value for --workers: must be >= 1"}
+	}
+	maxDepth, err := f.maxDepthOption()
+	if err != nil {
+		return assets.Options{}, err
+	}
+
+	keyPathAccessors, err := f.keyPathAccessorsOption()
+	if err != nil {
+		return assets.Options{}, err
+	}
+	ibAttributes, err := f.ibAttributesOption()
+	if err != nil {
+		return assets.Options{}, err
+	}
+	objcColorSelectors, err := f.objcColorSelectorsOption()
+	if err != nil {
+		return assets.Options{}, err
+	}
+	activeConfig, err := f.activeConfigOption()
+	if err != nil {
+		return assets.Options{}, err
+	}
+	cacheKey, err := f.cacheKeyOption()
+	if err != nil {
+		return assets.Options{}, err
 	}
 
-	sortedInclude := normalizePatterns(include)
-	sortedExclude := normalizePatterns(exclude)
+	sortedInclude := normalizePatterns(f.include)
+	sortedExclude := normalizePatterns(f.exclude)
+	sortedGenerated := normalizePatterns(f.generated)
+	sortedCatalogs := normalizePatterns(f.assetsFrom)
 	slices.Sort(sortedInclude)
 	slices.Sort(sortedExclude)
+	slices.Sort(sortedGenerated)
+	slices.Sort(sortedCatalogs)
 	if err := validateGlobPatterns(sortedInclude, "include"); err != nil {
-		return "", nil, nil, assets.Result{}, err
+		return assets.Options{}, err
 	}
 	if err := validateGlobPatterns(sortedExclude, "exclude"); err != nil {
-		return "", nil, nil, assets.Result{}, err
+		return assets.Options{}, err
 	}
-
-	scan, err := assets.Scan(assets.Options{
-		Root:    resolvedPath,
-		Include: sortedInclude,
-		Exclude: sortedExclude,
-		Workers: workers,
-	})
-	if err != nil {
-		return "", nil, nil, assets.Result{}, err
+	if err := validateGlobPatterns(sortedGenerated, "exclude-generated"); err != nil {
+		return assets.Options{}, err
+	}
+	if err := validateGlobPatterns(sortedCatalogs, "assets-from"); err != nil {
+		return assets.Options{}, err
 	}
 
-	return resolvedPath, sortedInclude, sortedExclude, scan, nil
+	return assets.Options{
+		Root:               resolvedPath,
+		Include:            sortedInclude,
+		Exclude:            sortedExclude,
+		Generated:          sortedGenerated,
+		Catalogs:           sortedCatalogs,
+		Workers:            f.workers,
+		DynamicNames:       f.dynamicNames,
+		MaxDepth:           maxDepth,
+		BundleResources:    f.scanBundleResource,
+		TrackReferences:    f.trackReferences,
+		RuleStats:          f.ruleStats,
+		DocC:               f.scanDocC,
+		HTML:               f.scanHTML,
+		AttributedMarkdown: f.scanAttributedMD,
+		LocalizedKeys:      f.scanLocalizedKeys,
+		LocalizedNames:     f.resolveLocalized,
+		Defaults:           f.scanDefaults,
+		IncludeHidden:      f.includeHidden,
+		IncludeBundles:     f.includeBundles,
+		KeyPathAccessors:   keyPathAccessors,
+		IBAttributes:       ibAttributes,
+		ObjCColorSelectors: objcColorSelectors,
+		IBDesignable:       f.ibDesignable,
+		ODRTagsUsed:        f.odrTagsUsed,
+		ActiveConfig:       activeConfig,
+		ParallelCatalogs:   f.parallelCatalogs,
+		CacheDir:           f.cacheDir,
+		CacheKey:           cacheKey,
+	}, nil
 }
 
 func normalizePatterns(patterns []string) []string {
@@ -100,44 +497,431 @@ func normalizePatterns(patterns []string) []string {
 }
 
 func newAssetsScanCommand(ctx *runContext) *cobra.Command {
-	var path string
-	var include []string
-	var exclude []string
-	var workers int
+	var flags assetScanFlags
+	var warnDuplicateNames bool
+	var warnCaseCollisions bool
+	var warnTypeMismatch bool
+	var emitAssetNames bool
+	var explain string
+	var listEmpty bool
+	var catalogsOnly bool
+	var profile bool
+	var failOnEmptyCatalog bool
+	var wide bool
+	var withUnused bool
+	var groupByModule bool
+	var reportRuleStats bool
+	var explainUnused bool
+	var failIfUsedBelow int
+	var warningsAsErrors bool
+	var maxResults int
 
 	cmd := &cobra.Command{
 		Use:   "scan",
 		Short: "Scan project assets and references",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			resolvedPath, sortedInclude, sortedExclude, scan, err := runAssetScan(path, include, exclude, workers)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if catalogsOnly {
+				for _, name := range []string{"emit-asset-names", "explain", "list-empty", "warn-duplicate-names", "warn-case-collisions", "warn-type-mismatch", "profile", "fail-on-empty-catalog", "wide", "with-unused", "group-by-module", "report-rule-stats", "explain-unused", "fail-if-used-below", "warnings-as-errors", "max-results"} {
+					if cmd.Flags().Changed(name) {
+						return usageError{Message: fmt.Sprintf("--catalogs-only cannot be combined with --%s", name)}
+					}
+				}
+				return runCatalogsOnly(ctx, flags)
+			}
+
+			if failIfUsedBelow < 0 {
+				return usageError{Message: fmt.Sprintf("invalid value for --fail-if-used-below: %d (must be >= 0)", failIfUsedBelow)}
+			}
+			if err := validateMaxResults(maxResults); err != nil {
+				return err
+			}
+			if maxResults > 0 && !withUnused {
+				return usageError{Message: "--max-results requires --with-unused"}
+			}
+			if wide && (ctx.output != outputTable || emitAssetNames || cmd.Flags().Changed("explain")) {
+				return usageError{Message: "--wide requires --output table and the scan summary"}
+			}
+
+			explaining := cmd.Flags().Changed("explain")
+			if explaining {
+				explain = strings.TrimSpace(explain)
+				if explain == "" {
+					return usageError{Message: "invalid value for --explain: asset name must not be empty"}
+				}
+				if emitAssetNames {
+					return usageError{Message: "--explain cannot be combined with --emit-asset-names"}
+				}
+				if failOnEmptyCatalog {
+					return usageError{Message: "--explain cannot be combined with --fail-on-empty-catalog"}
+				}
+				flags.trackReferences = true
+			}
+
+			flags.ruleStats = reportRuleStats
+			start := time.Now()
+			resolvedPath, sortedInclude, sortedExclude, scan, err := runAssetScan(flags)
 			if err != nil {
 				return err
 			}
+			durationMs := ctx.durationMs(start)
+			if profile {
+				if err := writeScanProfile(ctx.stderr, scan); err != nil {
+					return err
+				}
+			}
+			if emitAssetNames {
+				return render(ctx, scan.AssetNames, renderAssetNames)
+			}
+			if explaining {
+				result, err := buildExplainResult(scan, explain)
+				if err != nil {
+					return err
+				}
+				return render(ctx, result, renderExplainResult)
+			}
 
 			result := scanResult{
-				Command: "assets scan",
-				Path:    resolvedPath,
-				Include: sortedInclude,
-				Exclude: sortedExclude,
-				Workers: workers,
+				Command:          "assets scan",
+				Path:             resolvedPath,
+				InputPath:        flags.path,
+				Include:          sortedInclude,
+				Exclude:          sortedExclude,
+				EffectiveExclude: assets.EffectivePatterns(sortedExclude),
+				Workers:          flags.workers,
+				DurationMs:       durationMs,
 			}
 			result.Summary.AssetCatalogs = scan.AssetCatalogs
 			result.Summary.AssetSets = len(scan.AssetNames)
 			result.Summary.UsedAssets = len(scan.UsedAssets)
 			result.Summary.UnusedAssets = len(scan.UnusedAssets)
+			result.Summary.EmptyAssetSets = len(scan.EmptyAssetSets)
+			result.AssetTypeBreakdown = buildAssetTypeBreakdown(scan)
+			if listEmpty {
+				result.EmptyAssetSets = append([]string{}, scan.EmptyAssetSets...)
+			}
+			if warnDuplicateNames {
+				result.DuplicateNames = buildDuplicateNamesPayload(scan.DuplicateNames)
+			}
+			if warnCaseCollisions {
+				result.CaseCollisions = buildCaseCollisionsPayload(scan.CaseCollisions)
+			}
+			if warnTypeMismatch {
+				result.TypeMismatches = buildTypeMismatchesPayload(scan.TypeMismatches)
+			}
+			if failOnEmptyCatalog {
+				result.EmptyCatalogs = append([]string{}, scan.EmptyCatalogs...)
+			}
+			if wide {
+				result.wide = buildScanWideDetails(scan)
+			}
+			if withUnused {
+				listed, truncated := truncateUnusedPaths(scan.UnusedByFile, maxResults)
+				result.UnusedByFile = buildUnusedByFilePayload(listed)
+				result.Truncated = truncated
+				result.TotalUnused = totalUnusedOption(scan.UnusedByFile, maxResults)
+			}
+			if groupByModule {
+				result.Modules = buildModulesPayload(resolvedPath, scan)
+			}
+			if reportRuleStats {
+				result.RuleMatchCounts = scan.RuleMatchCounts
+			}
+			if explainUnused {
+				result.UnusedExplained = buildUnusedExplanations(scan.UnusedByFile)
+			}
+			result.Warnings = buildScanWarnings(result)
 
-			return renderScanResult(ctx.stdout, ctx.output, result)
+			if err := render(ctx, result, renderScanResult); err != nil {
+				return err
+			}
+			if len(result.DuplicateNames) > 0 {
+				return duplicateAssetNamesFoundError{}
+			}
+			if len(result.CaseCollisions) > 0 {
+				return caseCollisionsFoundError{}
+			}
+			if len(result.TypeMismatches) > 0 {
+				return typeMismatchesFoundError{}
+			}
+			if len(result.EmptyCatalogs) > 0 {
+				return emptyCatalogsFoundError{}
+			}
+			if result.Summary.UsedAssets < failIfUsedBelow {
+				return usedAssetsBelowThresholdError{}
+			}
+			if warningsAsErrors && len(result.Warnings) > 0 {
+				return warningsFoundError{}
+			}
+			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&path, "path", ".", "Path to scan")
-	cmd.Flags().StringSliceVar(&include, "include", nil, "Include path globs")
-	cmd.Flags().StringSliceVar(&exclude, "exclude", append([]string{}, defaultExcludedPaths...), "Exclude path globs (replaces defaults; repeatable, comma-separated)")
-	cmd.Flags().IntVar(&workers, "workers", defaultWorkers(), "Worker count")
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&warnDuplicateNames, "warn-duplicate-names", false, "Report asset names defined in more than one catalog and exit non-zero when found")
+	cmd.Flags().BoolVar(&warnCaseCollisions, "warn-case-collisions", false, "Report same-typed asset names in one catalog that differ only by case, e.g. Hero and hero, and exit non-zero when found")
+	cmd.Flags().BoolVar(&warnTypeMismatch, "warn-type-mismatch", false, "Report typed references, e.g. Color(\"hero\"), that only match a same-named asset of another type and exit non-zero when found")
+	cmd.Flags().BoolVar(&emitAssetNames, "emit-asset-names", false, "Output only the sorted asset name list instead of the scan summary")
+	cmd.Flags().BoolVar(&profile, "profile", false, "Write a timing breakdown of the scan phases to stderr")
+	cmd.Flags().BoolVar(&catalogsOnly, "catalogs-only", false, "Output only the asset catalogs and their asset-set counts, skipping usage analysis")
+	cmd.Flags().BoolVar(&wide, "wide", false, "With --output table, add duplicate-name and empty-catalog counts and a per-catalog breakdown")
+	cmd.Flags().BoolVar(&failOnEmptyCatalog, "fail-on-empty-catalog", false, "Report .xcassets catalogs without asset sets and exit non-zero when found")
+	cmd.Flags().BoolVar(&listEmpty, "list-empty", false, "List asset sets that contain only Contents.json")
+	cmd.Flags().BoolVar(&warningsAsErrors, "warnings-as-errors", false, "Exit non-zero when the enabled checks report any warnings, including --list-empty findings")
+	cmd.Flags().IntVar(&failIfUsedBelow, "fail-if-used-below", 0, "Exit non-zero when fewer than this many assets are used (0 disables the check)")
+	cmd.Flags().BoolVar(&withUnused, "with-unused", false, "Include the unused assets grouped by catalog (unusedByFile) in the scan output")
+	cmd.Flags().IntVar(&maxResults, "max-results", 0, "With --with-unused, list at most this many unused asset sets and set truncated when more exist (0 lists all)")
+	cmd.Flags().BoolVar(&explainUnused, "explain-unused", false, "Add unusedExplained: the name and Swift resource symbol forms searched for each unused asset set")
+	cmd.Flags().BoolVar(&reportRuleStats, "report-rule-stats", false, "Add ruleMatchCounts: the number of references each detection rule resolved to an asset")
+	cmd.Flags().BoolVar(&groupByModule, "group-by-module", false, "Add per-module asset-set counts, using the nearest directory with Package.swift or an .xcodeproj above each catalog")
+	cmd.Flags().StringVar(&explain, "explain", "", "Explain why the named asset is used or unused, listing each matching reference")
 
 	return cmd
 }
 
+// writeScanProfile reports scan phase timings as plain text on stderr so the
+// stdout report stays machine-readable.
+func writeScanProfile(w io.Writer, scan assets.Result) error {
+	profile := scan.Profile
+	total := profile.CatalogDiscovery + profile.LabelCollection + profile.UsageDetection
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Profile"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(tw, "  Catalog Discovery:\t%s\t%d catalogs, %d asset sets\n", profile.CatalogDiscovery, scan.AssetCatalogs, len(scan.AssetNames)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(tw, "  Label Collection:\t%s\t%d Swift files\n", profile.LabelCollection, profile.LabelFiles); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(tw, "  Usage Detection:\t%s\t%d source files\n", profile.UsageDetection, profile.SourceFiles); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(tw, "  Total:\t%s\t\n", total); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+func buildScanWideDetails(scan assets.Result) *scanWideDetails {
+	details := &scanWideDetails{
+		DuplicateNames: len(scan.DuplicateNames),
+		EmptyCatalogs:  len(scan.EmptyCatalogs),
+		Catalogs:       make([]catalogResult, 0, len(scan.Catalogs)),
+	}
+	for _, catalog := range scan.Catalogs {
+		details.Catalogs = append(details.Catalogs, catalogResult{Path: catalog.Path, AssetSets: catalog.AssetSets})
+	}
+	return details
+}
+
+// buildUnusedExplanations lists the searched candidates of every unused asset
+// set, ordered by catalog and then by asset set path.
+func buildUnusedExplanations(unusedByFile map[string][]string) []unusedExplanationResult {
+	explanations := make([]unusedExplanationResult, 0)
+	for _, catalog := range sortedStringKeys(unusedByFile) {
+		for _, assetPath := range unusedByFile[catalog] {
+			explanations = append(explanations, unusedExplanationResult{
+				Name:               assetNameFromPath(assetPath),
+				Path:               assetPath,
+				SearchedCandidates: assets.SearchedCandidates(assetPath),
+			})
+		}
+	}
+	return explanations
+}
+
+// buildAssetTypeBreakdown counts asset sets per type. Used counts are asset
+// sets not reported unused, as in buildModulesPayload.
+func buildAssetTypeBreakdown(scan assets.Result) map[string]assetTypeCountResult {
+	breakdown := make(map[string]assetTypeCountResult, len(scan.AssetSetsByType))
+	for assetType, count := range scan.AssetSetsByType {
+		breakdown[assetType] = assetTypeCountResult{AssetSets: count, UsedAssets: count}
+	}
+	for _, paths := range scan.UnusedByFile {
+		for _, assetPath := range paths {
+			assetType := strings.TrimPrefix(filepath.Ext(assetPath), ".")
+			counts := breakdown[assetType]
+			counts.UsedAssets--
+			counts.UnusedAssets++
+			breakdown[assetType] = counts
+		}
+	}
+	return breakdown
+}
+
+// buildModulesPayload groups the scanned catalogs by module root and sums
+// their asset-set counts. Used counts are asset sets not reported unused, so
+// they are per asset set rather than per distinct name like the summary.
+func buildModulesPayload(root string, scan assets.Result) []moduleResult {
+	byPath := make(map[string]*moduleResult)
+	moduleRoots := make(map[string]string)
+	moduleFor := func(catalog string) *moduleResult {
+		dir := filepath.Dir(catalog)
+		modulePath, ok := moduleRoots[dir]
+		if !ok {
+			modulePath = findModuleRoot(root, dir)
+			moduleRoots[dir] = modulePath
+		}
+		module, ok := byPath[modulePath]
+		if !ok {
+			module = &moduleResult{Path: modulePath}
+			byPath[modulePath] = module
+		}
+		return module
+	}
+	for _, catalog := range scan.Catalogs {
+		module := moduleFor(catalog.Path)
+		unused := len(scan.UnusedByFile[catalog.Path])
+		module.AssetSets += catalog.AssetSets
+		module.UnusedAssets += unused
+		module.UsedAssets += catalog.AssetSets - unused
+	}
+
+	modules := make([]moduleResult, 0, len(byPath))
+	for _, path := range sortedStringKeys(byPath) {
+		modules = append(modules, *byPath[path])
+	}
+	return modules
+}
+
+// findModuleRoot walks up from dir to the nearest directory containing
+// Package.swift or an .xcodeproj bundle, stopping at root. It returns the
+// module path relative to root, or "." when no module marker is found.
+func findModuleRoot(root, dir string) string {
+	for {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return "."
+		}
+		if isModuleRoot(dir) {
+			return filepath.ToSlash(rel)
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func isModuleRoot(dir string) bool {
+	if info, err := os.Stat(filepath.Join(dir, "Package.swift")); err == nil && !info.IsDir() {
+		return true
+	}
+	projects, err := filepath.Glob(filepath.Join(dir, "*.xcodeproj"))
+	return err == nil && len(projects) > 0
+}
+
+func runCatalogsOnly(ctx *runContext, flags assetScanFlags) error {
+	opts, err := flags.scanOptions()
+	if err != nil {
+		return err
+	}
+	catalogs, err := assets.ScanCatalogs(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	result := catalogsResult{
+		Command:  "assets scan",
+		Path:     opts.Root,
+		Catalogs: make([]catalogResult, 0, len(catalogs)),
+	}
+	for _, catalog := range catalogs {
+		result.Catalogs = append(result.Catalogs, catalogResult{Path: catalog.Path, AssetSets: catalog.AssetSets})
+	}
+	return render(ctx, result, renderCatalogsResult)
+}
+
+func buildExplainResult(scan assets.Result, name string) (explainResult, error) {
+	if _, found := slices.BinarySearch(scan.AssetNames, name); !found {
+		return explainResult{}, usageError{Message: fmt.Sprintf("invalid value for --explain: asset %q not found", name)}
+	}
+
+	_, used := slices.BinarySearch(scan.UsedAssets, name)
+	result := explainResult{
+		Command:    "assets scan",
+		Asset:      name,
+		Used:       used,
+		References: make([]referenceResult, 0, len(scan.References[name])),
+	}
+	for _, reference := range scan.References[name] {
+		result.References = append(result.References, referenceResult{
+			Source: reference.Source,
+			Rule:   reference.Rule,
+			Text:   reference.Text,
+		})
+	}
+	return result, nil
+}
+
+func buildDuplicateNamesPayload(duplicates []assets.DuplicateName) []duplicateNameResult {
+	out := make([]duplicateNameResult, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		out = append(out, duplicateNameResult{
+			Name:      duplicate.Name,
+			AssetType: duplicate.AssetType,
+			Catalogs:  append([]string{}, duplicate.Catalogs...),
+		})
+	}
+	return out
+}
+
+func buildCaseCollisionsPayload(collisions []assets.CaseCollision) []caseCollisionResult {
+	out := make([]caseCollisionResult, 0, len(collisions))
+	for _, collision := range collisions {
+		out = append(out, caseCollisionResult{
+			Catalog:   collision.Catalog,
+			AssetType: collision.AssetType,
+			Names:     append([]string{}, collision.Names...),
+		})
+	}
+	return out
+}
+
+// buildScanWarnings flattens the findings of the enabled checks in result
+// into warnings, in check order.
+func buildScanWarnings(result scanResult) []scanWarningResult {
+	var warnings []scanWarningResult
+	for _, duplicate := range result.DuplicateNames {
+		warnings = append(warnings, scanWarningResult{
+			Code:    "duplicate-name",
+			Message: fmt.Sprintf("%s asset %q is defined in %d catalogs: %s", duplicate.AssetType, duplicate.Name, len(duplicate.Catalogs), strings.Join(duplicate.Catalogs, ", ")),
+		})
+	}
+	for _, collision := range result.CaseCollisions {
+		warnings = append(warnings, scanWarningResult{
+			Code:    "case-collision",
+			Message: fmt.Sprintf("%s asset names differ only by case: %s", collision.AssetType, strings.Join(collision.Names, ", ")),
+			Path:    collision.Catalog,
+		})
+	}
+	for _, mismatch := range result.TypeMismatches {
+		warnings = append(warnings, scanWarningResult{
+			Code:    "type-mismatch",
+			Message: fmt.Sprintf("%s reference %q only matches %s assets", mismatch.RequestedType, mismatch.Name, strings.Join(mismatch.ExistingTypes, ", ")),
+			Path:    mismatch.Source,
+		})
+	}
+	for _, catalog := range result.EmptyCatalogs {
+		warnings = append(warnings, scanWarningResult{Code: "empty-catalog", Message: "asset catalog has no asset sets", Path: catalog})
+	}
+	for _, asset := range result.EmptyAssetSets {
+		warnings = append(warnings, scanWarningResult{Code: "empty-asset-set", Message: "asset set contains only Contents.json", Path: asset})
+	}
+	return warnings
+}
+
+func buildTypeMismatchesPayload(mismatches []assets.TypeMismatch) []typeMismatchResult {
+	out := make([]typeMismatchResult, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		out = append(out, typeMismatchResult{
+			Name:          mismatch.Name,
+			RequestedType: mismatch.RequestedType,
+			ExistingTypes: append([]string{}, mismatch.ExistingTypes...),
+			Source:        mismatch.Source,
+			Rule:          mismatch.Rule,
+		})
+	}
+	return out
+}
+
 type unusedResult struct {
 	Command             string                      `json:"command"`
 	Path                string                      `json:"path"`
@@ -145,42 +929,125 @@ type unusedResult struct {
 	PruneCandidateCount int                         `json:"pruneCandidateCount"`
 	Unused              []string                    `json:"unused"`
 	UnusedByFile        map[string]unusedFileResult `json:"unusedByFile"`
+	GroupBy             string                      `json:"groupBy"`
+	UnusedByGroup       map[string]unusedFileResult `json:"unusedByGroup"`
+	// unusedPathsByFile and unusedPathsByGroup keep the concrete asset-set
+	// paths per catalog and per group. Minified JSON streams entries straight
+	// from them; other formats build UnusedByFile and UnusedByGroup first.
+	unusedPathsByFile  map[string][]string
+	unusedPathsByGroup map[string][]string
+	// UsedOnlyInTests is only populated when --report-test-only is set.
+	UsedOnlyInTests []string `json:"usedOnlyInTests,omitempty"`
+	// UsedOnlyInPreviews is only populated when --report-preview-only is set.
+	UsedOnlyInPreviews []string `json:"usedOnlyInPreviews,omitempty"`
+	// Truncated is set when --max-results dropped entries from the unused
+	// lists; unusedCount and pruneCandidateCount still cover every asset.
+	Truncated bool `json:"truncated,omitempty"`
+	// TotalUnused is set with --max-results to the number of unused asset
+	// sets before truncation.
+	TotalUnused *int `json:"totalUnused,omitempty"`
+	// DurationMs is the scan wall time; omitted under --deterministic.
+	DurationMs *int64 `json:"durationMs,omitempty"`
 }
 
 type unusedFileResult struct {
 	UnusedAssets []string `json:"unusedAssets"`
 }
 
+const (
+	groupByCatalog   = "catalog"
+	groupByType      = "type"
+	groupByDirectory = "directory"
+)
+
+func isAllowedGroupBy(groupBy string) bool {
+	switch groupBy {
+	case groupByCatalog, groupByType, groupByDirectory:
+		return true
+	default:
+		return false
+	}
+}
+
+// groupUnusedAssetPaths regroups catalog-keyed unused asset-set paths by the
+// given strategy: the catalog path, the asset type, or the parent directory.
+func groupUnusedAssetPaths(unusedByFile map[string][]string, groupBy string) map[string][]string {
+	if groupBy == groupByCatalog {
+		return unusedByFile
+	}
+	out := make(map[string][]string, len(unusedByFile))
+	for _, assetPaths := range unusedByFile {
+		for _, assetPath := range assetPaths {
+			key := filepath.Dir(assetPath)
+			if groupBy == groupByType {
+				key = strings.TrimPrefix(filepath.Ext(assetPath), ".")
+			}
+			out[key] = append(out[key], assetPath)
+		}
+	}
+	for key, assetPaths := range out {
+		slices.Sort(assetPaths)
+		out[key] = assetPaths
+	}
+	return out
+}
+
 func newAssetsUnusedCommand(ctx *runContext) *cobra.Command {
-	var path string
-	var include []string
-	var exclude []string
-	var workers int
+	var flags assetScanFlags
+	var reportTestOnly bool
+	var reportPreviewOnly bool
+	var groupBy string
+	var maxResults int
 
 	cmd := &cobra.Command{
 		Use:   "unused",
 		Short: "Detect unused assets",
 		RunE: func(_ *cobra.Command, _ []string) error {
-			resolvedPath, _, _, scan, err := runAssetScan(path, include, exclude, workers)
+			if !isAllowedGroupBy(groupBy) {
+				return usageError{Message: fmt.Sprintf("invalid value for --group-by: %q (allowed: catalog, type, directory)", groupBy)}
+			}
+			if err := validateMaxResults(maxResults); err != nil {
+				return err
+			}
+			start := time.Now()
+			resolvedPath, _, _, scan, err := runAssetScan(flags)
 			if err != nil {
 				return err
 			}
+			durationMs := ctx.durationMs(start)
 
-			pruneCandidates := collectPruneTargets(scan.UnusedByFile)
-			unusedByFile := buildUnusedByFilePayload(scan.UnusedByFile)
+			pruneCandidates, _ := splitProtectedTargets(collectPruneTargets(scan.UnusedByFile, nil), scan.DynamicNameMatches)
 			unusedSummary := scan.UnusedAssets
-			if len(unusedSummary) == 0 && len(unusedByFile) > 0 {
-				unusedSummary = flattenUnusedByFileNames(unusedByFile)
+			if len(unusedSummary) == 0 && len(scan.UnusedByFile) > 0 {
+				unusedSummary = flattenUnusedByFileNames(buildUnusedByFilePayload(scan.UnusedByFile))
 			}
+			unusedCount := len(unusedSummary)
+			// Counts above cover every unused asset; only the lists are capped.
+			listedPaths, pathsTruncated := truncateUnusedPaths(scan.UnusedByFile, maxResults)
+			unusedSummary, namesTruncated := truncateList(unusedSummary, maxResults)
 			result := unusedResult{
 				Command:             "assets unused",
 				Path:                resolvedPath,
-				UnusedCount:         len(unusedSummary),
+				UnusedCount:         unusedCount,
 				PruneCandidateCount: len(pruneCandidates),
 				Unused:              unusedSummary,
-				UnusedByFile:        unusedByFile,
+				GroupBy:             groupBy,
+				unusedPathsByFile:   listedPaths,
+				unusedPathsByGroup:  groupUnusedAssetPaths(listedPaths, groupBy),
+				Truncated:           pathsTruncated || namesTruncated,
+				TotalUnused:         totalUnusedOption(scan.UnusedByFile, maxResults),
+				DurationMs:          durationMs,
 			}
-			if err := renderUnusedResult(ctx.stdout, ctx.output, result); err != nil {
+			if ctx.output == outputTemplate {
+				result = result.withGroupedPayloads()
+			}
+			if reportTestOnly {
+				result.UsedOnlyInTests = scan.UsedOnlyInTests
+			}
+			if reportPreviewOnly {
+				result.UsedOnlyInPreviews = scan.UsedOnlyInPreviews
+			}
+			if err := render(ctx, result, renderUnusedResult); err != nil {
 				return err
 			}
 			if result.UnusedCount > 0 {
@@ -190,46 +1057,255 @@ func newAssetsUnusedCommand(ctx *runContext) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&path, "path", ".", "Path to scan")
-	cmd.Flags().StringSliceVar(&include, "include", nil, "Include path globs")
-	cmd.Flags().StringSliceVar(&exclude, "exclude", append([]string{}, defaultExcludedPaths...), "Exclude path globs (replaces defaults; repeatable, comma-separated)")
-	cmd.Flags().IntVar(&workers, "workers", defaultWorkers(), "Worker count")
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&reportTestOnly, "report-test-only", false, "Report assets referenced only from test sources (*Tests/ directories, *Test*.swift files)")
+	cmd.Flags().BoolVar(&reportPreviewOnly, "report-preview-only", false, "Report assets referenced only inside #Preview macro bodies")
+	cmd.Flags().StringVar(&groupBy, "group-by", groupByCatalog, "Group unused assets by: catalog|type|directory")
+	cmd.Flags().IntVar(&maxResults, "max-results", 0, "List at most this many unused assets in each list and set truncated when more exist; counts stay exact (0 lists all)")
 	return cmd
 }
 
-type pruneResult struct {
-	Command             string   `json:"command"`
-	Path                string   `json:"path"`
-	Apply               bool     `json:"apply"`
-	Force               bool     `json:"force"`
-	UnusedCount         int      `json:"unusedCount"`
-	PruneCandidateCount int      `json:"pruneCandidateCount"`
-	// Deleted is backward-compatible JSON output; in dry-run mode it contains
-	// prune candidates that would be deleted with --apply.
-	Deleted             []string `json:"deleted"`
-	DryRun              bool     `json:"dryRun"`
+type watchResult struct {
+	Command string `json:"command"`
+	Path    string `json:"path"`
+	// Scan numbers the reports of one watch session, starting at 1 for the
+	// initial scan.
+	Scan        int      `json:"scan"`
+	UnusedCount int      `json:"unusedCount"`
+	Unused      []string `json:"unused"`
 }
 
-func newAssetsPruneCommand(ctx *runContext) *cobra.Command {
-	var path string
-	var apply bool
-	var force bool
+func newAssetsWatchCommand(ctx *runContext) *cobra.Command {
+	var flags assetScanFlags
+	var debounce time.Duration
+	var maxScans int
 
 	cmd := &cobra.Command{
-		Use:   "prune",
-		Short: "Prune unused assets (dry-run by default)",
+		Use:   "watch",
+		Short: "Re-scan and report unused assets whenever files change",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if debounce <= 0 {
+				return usageError{Message: fmt.Sprintf("invalid value for --debounce: %s (must be > 0)", debounce)}
+			}
+			if maxScans < 0 {
+				return usageError{Message: fmt.Sprintf("invalid value for --max-scans: %d (must be >= 0)", maxScans)}
+			}
+			opts, err := flags.scanOptions()
+			if err != nil {
+				return err
+			}
+
+			watchCtx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			scans := 0
+			return assets.Watch(watchCtx, opts, debounce, func(scan assets.Result) error {
+				scans++
+				unused := scan.UnusedAssets
+				if unused == nil {
+					unused = []string{}
+				}
+				result := watchResult{
+					Command:     "assets watch",
+					Path:        opts.Root,
+					Scan:        scans,
+					UnusedCount: len(unused),
+					Unused:      unused,
+				}
+				if err := render(ctx, result, renderWatchResult); err != nil {
+					return err
+				}
+				if maxScans > 0 && scans >= maxScans {
+					stop()
+				}
+				return nil
+			})
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().DurationVar(&debounce, "debounce", assets.DefaultWatchDebounce, "Quiet period after the last file change before re-scanning")
+	cmd.Flags().IntVar(&maxScans, "max-scans", 0, "Stop after this many scans, including the initial one (0 watches until interrupted)")
+	return cmd
+}
+
+// doctorResult reports what a scan of Path would see, with warnings for
+// likely misconfigurations. It never affects the exit code.
+type doctorResult struct {
+	Command      string `json:"command"`
+	Path         string `json:"path"`
+	Catalogs     int    `json:"catalogs"`
+	GitAvailable bool   `json:"gitAvailable"`
+	// DefaultExcludesMatched is set when any built-in exclude pattern
+	// skipped a path.
+	DefaultExcludesMatched bool                  `json:"defaultExcludesMatched"`
+	Excludes               []doctorExcludeResult `json:"excludes"`
+	// SourceFiles counts the files scanned for references by extension.
+	SourceFiles map[string]int `json:"sourceFiles"`
+	Warnings    []string       `json:"warnings"`
+}
+
+type doctorExcludeResult struct {
+	Pattern string `json:"pattern"`
+	Default bool   `json:"default"`
+	Matches int    `json:"matches"`
+}
+
+func newAssetsDoctorCommand(ctx *runContext) *cobra.Command {
+	var flags assetScanFlags
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose scan scope misconfigurations such as a wrong path or excludes",
 		RunE: func(_ *cobra.Command, _ []string) error {
+			opts, err := flags.scanOptions()
+			if err != nil {
+				return err
+			}
+			diagnosis, err := assets.Diagnose(opts)
+			if err != nil {
+				return err
+			}
+			_, gitErr := exec.LookPath("git")
+			return render(ctx, buildDoctorResult(opts.Root, diagnosis, gitErr == nil), renderDoctorResult)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+func buildDoctorResult(root string, diagnosis assets.Diagnosis, gitAvailable bool) doctorResult {
+	result := doctorResult{
+		Command:      "assets doctor",
+		Path:         root,
+		Catalogs:     diagnosis.Catalogs,
+		GitAvailable: gitAvailable,
+		Excludes:     make([]doctorExcludeResult, 0, len(diagnosis.ExcludeMatches)),
+		SourceFiles:  diagnosis.SourceFiles,
+		Warnings:     []string{},
+	}
+	sourceFiles := 0
+	for _, count := range diagnosis.SourceFiles {
+		sourceFiles += count
+	}
+
+	if result.Catalogs == 0 {
+		result.Warnings = append(result.Warnings, "0 asset catalogs found; is --path the right directory?")
+	}
+	if sourceFiles == 0 {
+		result.Warnings = append(result.Warnings, "0 source files found; nothing can reference assets, check --path, --include and --exclude")
+	}
+	if !gitAvailable {
+		result.Warnings = append(result.Warnings, "git not found on PATH; assets prune --apply cannot check for a clean working tree without --force")
+	}
+	for _, pattern := range sortedStringKeys(diagnosis.ExcludeMatches) {
+		exclude := doctorExcludeResult{
+			Pattern: pattern,
+			Default: slices.Contains(defaultExcludedPaths, pattern),
+			Matches: diagnosis.ExcludeMatches[pattern],
+		}
+		result.Excludes = append(result.Excludes, exclude)
+		if exclude.Default && exclude.Matches > 0 {
+			result.DefaultExcludesMatched = true
+		}
+		if !exclude.Default && exclude.Matches == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("--exclude pattern %q matched nothing", pattern))
+		}
+	}
+	return result
+}
+
+type pruneResult struct {
+	Command             string `json:"command"`
+	Path                string `json:"path"`
+	Apply               bool   `json:"apply"`
+	Force               bool   `json:"force"`
+	UnusedCount         int    `json:"unusedCount"`
+	PruneCandidateCount int    `json:"pruneCandidateCount"`
+	// Deleted is backward-compatible JSON output; in dry-run mode it contains
+	// prune candidates that would be deleted with --apply.
+	Deleted []string `json:"deleted"`
+	DryRun  bool     `json:"dryRun"`
+	// AppliedCount is the number of asset sets --apply deleted or moved; it
+	// is 0 in dry-run mode and when --apply found nothing to prune.
+	AppliedCount int `json:"appliedCount"`
+	// Types lists the asset types selected with --type; empty means all.
+	Types []string `json:"types,omitempty"`
+	// BackupDir is set when --backup-dir moved targets instead of deleting.
+	BackupDir string `json:"backupDir,omitempty"`
+	// Staged is set when --git-add or --commit staged the removals in git.
+	Staged bool `json:"staged,omitempty"`
+	// Commit is the hash of the commit created by --commit.
+	Commit string `json:"commit,omitempty"`
+	// Protected lists unused asset sets kept because an interpolated name
+	// family such as "flag_\(code)" may load them at runtime.
+	Protected []string `json:"protected,omitempty"`
+	// RemovedCatalogs lists catalogs removed by --remove-empty-catalogs after
+	// their last asset set was pruned.
+	RemovedCatalogs []string `json:"removedCatalogs,omitempty"`
+	// RemovedGroups lists catalog group folders (such as namespace folders)
+	// deleted because the prune left them holding only Contents.json.
+	RemovedGroups []string `json:"removedGroups,omitempty"`
+	// Truncated is set when --max-results dropped entries from deleted;
+	// pruneCandidateCount still covers every candidate.
+	Truncated bool `json:"truncated,omitempty"`
+	// TotalUnused is set with --max-results to the number of unused asset
+	// sets before truncation.
+	TotalUnused *int `json:"totalUnused,omitempty"`
+	// DurationMs is the scan wall time; omitted under --deterministic.
+	DurationMs *int64 `json:"durationMs,omitempty"`
+}
+
+func newAssetsPruneCommand(ctx *runContext) *cobra.Command {
+	var path string
+	var apply bool
+	var force bool
+	var backupDir string
+	var types []string
+	var gitAdd bool
+	var commitMessage string
+	var removeEmptyCatalogs bool
+	var maxResults int
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Prune unused assets (dry-run by default)",
+		RunE: func(c *cobra.Command, _ []string) error {
 			resolvedPath, err := resolveScanPath(path)
 			if err != nil {
 				return err
 			}
 
+			committing := c.Flags().Changed("commit")
 			if force && !apply {
 				return usageError{Message: "--force requires --apply"}
 			}
+			if backupDir != "" && !apply {
+				return usageError{Message: "--backup-dir requires --apply"}
+			}
+			if gitAdd && !apply {
+				return usageError{Message: "--git-add requires --apply"}
+			}
+			if removeEmptyCatalogs && !apply {
+				return usageError{Message: "--remove-empty-catalogs requires --apply"}
+			}
+			if committing && !apply {
+				return usageError{Message: "--commit requires --apply"}
+			}
+			if committing && strings.TrimSpace(commitMessage) == "" {
+				return usageError{Message: "invalid value for --commit: message must not be empty"}
+			}
+			if err := validateMaxResults(maxResults); err != nil {
+				return err
+			}
+			pruneTypes, err := normalizePruneTypes(types)
+			if err != nil {
+				return err
+			}
 
 			// Prune intentionally scans with conservative defaults to keep delete
 			// candidates deterministic across local/CI runs.
+			start := time.Now()
 			scan, err := assets.Scan(assets.Options{
 				Root:    resolvedPath,
 				Exclude: append([]string{}, defaultExcludedPaths...),
@@ -238,24 +1314,16 @@ func newAssetsPruneCommand(ctx *runContext) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			durationMs := ctx.durationMs(start)
 
-			pruneTargets := collectPruneTargets(scan.UnusedByFile)
+			// Assets matched by an interpolated name family may be loaded at
+			// runtime, so they stay reported as unused but are never pruned.
+			pruneTargets, protected := splitProtectedTargets(collectPruneTargets(scan.UnusedByFile, pruneTypes), scan.DynamicNameMatches)
 			unusedByFile := buildUnusedByFilePayload(scan.UnusedByFile)
 			unusedSummary := scan.UnusedAssets
 			if len(unusedSummary) == 0 && len(unusedByFile) > 0 {
 				unusedSummary = flattenUnusedByFileNames(unusedByFile)
 			}
-			if apply {
-				if !force {
-					if err := requireCleanGitWorkingTree(resolvedPath); err != nil {
-						return err
-					}
-				}
-				if err := deletePruneTargets(resolvedPath, pruneTargets); err != nil {
-					return err
-				}
-			}
-
 			result := pruneResult{
 				Command:             "assets prune",
 				Path:                resolvedPath,
@@ -265,14 +1333,103 @@ func newAssetsPruneCommand(ctx *runContext) *cobra.Command {
 				PruneCandidateCount: len(pruneTargets),
 				Deleted:             pruneTargets,
 				DryRun:              !apply,
+				Types:               pruneTypes,
+				Protected:           protected,
+				DurationMs:          durationMs,
+			}
+			// Only the listed detail is capped; --apply still removes every
+			// target.
+			result.Deleted, result.Truncated = truncateList(pruneTargets, maxResults)
+			result.TotalUnused = totalUnusedOption(scan.UnusedByFile, maxResults)
+			if apply {
+				if committing {
+					// Fail before deleting anything when no commit can be made.
+					if err := requireGitWorkTree(resolvedPath); err != nil {
+						return err
+					}
+				}
+				if !force {
+					if err := requireCleanGitWorkingTree(resolvedPath); err != nil {
+						var dirtyErr gitWorkingTreeDirtyError
+						if errors.As(err, &dirtyErr) {
+							// Show the would-delete plan so users can review it
+							// before cleaning their tree.
+							result.DryRun = true
+							if renderErr := render(ctx, result, renderPruneResult); renderErr != nil {
+								return renderErr
+							}
+						}
+						return err
+					}
+				}
+				if backupDir != "" {
+					backupAbs, err := resolveBackupDir(resolvedPath, backupDir)
+					if err != nil {
+						return err
+					}
+					result.BackupDir = backupAbs
+					if err := movePruneTargets(resolvedPath, backupAbs, pruneTargets); err != nil {
+						return err
+					}
+				} else {
+					removedGroups, err := deletePruneTargets(resolvedPath, pruneTargets)
+					if err != nil {
+						return err
+					}
+					result.RemovedGroups = removedGroups
+				}
+				result.AppliedCount = len(pruneTargets)
+				if removeEmptyCatalogs {
+					removed, err := removeEmptiedCatalogs(resolvedPath, pruneTargetCatalogs(scan.UnusedByFile, pruneTargets))
+					if err != nil {
+						return err
+					}
+					result.RemovedCatalogs = removed
+				}
+				if gitAdd {
+					staged, err := stageGitRemovals(resolvedPath, slices.Concat(pruneTargets, result.RemovedGroups, result.RemovedCatalogs))
+					if err != nil {
+						return err
+					}
+					if !staged {
+						if _, err := fmt.Fprintf(ctx.stderr, "warning: --git-add ignored: %s is not inside a git work tree\n", resolvedPath); err != nil {
+							return err
+						}
+					}
+					result.Staged = staged
+				}
+				if committing {
+					removed := slices.Concat(pruneTargets, result.RemovedGroups, result.RemovedCatalogs)
+					if _, err := stageGitRemovals(resolvedPath, removed); err != nil {
+						return err
+					}
+					result.Staged = true
+					commit, err := commitGitRemovals(resolvedPath, removed, commitMessage)
+					if err != nil {
+						return err
+					}
+					if commit == "" {
+						if _, err := fmt.Fprintln(ctx.stderr, "warning: --commit skipped: no tracked files were removed"); err != nil {
+							return err
+						}
+					}
+					result.Commit = commit
+				}
 			}
-			return renderPruneResult(ctx.stdout, ctx.output, result)
+
+			return render(ctx, result, renderPruneResult)
 		},
 	}
 
 	cmd.Flags().StringVar(&path, "path", ".", "Path to scan")
 	cmd.Flags().BoolVar(&apply, "apply", false, "Apply deletions")
 	cmd.Flags().BoolVar(&force, "force", false, "Override safety checks for --apply")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Limit pruning to these asset types (repeatable): imageset|colorset|dataset|appiconset|symbolset|textureset")
+	cmd.Flags().BoolVar(&gitAdd, "git-add", false, "With --apply, stage the removed asset sets in git")
+	cmd.Flags().IntVar(&maxResults, "max-results", 0, "List at most this many paths under deleted and set truncated when more exist; --apply still removes all (0 lists all)")
+	cmd.Flags().StringVar(&commitMessage, "commit", "", "With --apply, stage the removals and commit only them in git with this message")
+	cmd.Flags().BoolVar(&removeEmptyCatalogs, "remove-empty-catalogs", false, "With --apply, also remove .xcassets catalogs left without asset sets by the prune")
+	cmd.Flags().StringVar(&backupDir, "backup-dir", "", "With --apply, move pruned asset sets into this directory (preserving relative paths) instead of deleting them")
 	return cmd
 }
 
@@ -305,7 +1462,7 @@ func resolveScanPath(path string) (string, error) {
 
 	info, err := os.Stat(absolutePath)
 	if err != nil {
-		return "", fmt.Errorf("path does not exist or is inaccessible: %s", absolutePath)
+		return "", pathNotFoundError{Path: absolutePath}
 	}
 	if !info.IsDir() {
 		return "", fmt.Errorf("path is not a directory: %s", absolutePath)
@@ -320,124 +1477,710 @@ func expandTildePath(path string) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("failed to resolve home directory: %w", err)
 		}
-		return home, nil
-	}
-
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to resolve home directory: %w", err)
+		return home, nil
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve home directory: %w", err)
+		}
+		return filepath.Join(home, path[2:]), nil
+	}
+
+	return path, nil
+}
+
+func renderScanResult(w io.Writer, format outputFormat, result scanResult) error {
+	switch format.name {
+	case outputJSON:
+		return writeJSON(w, result, format.indent)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		header := "command\tpath\tworkers\tasset_catalogs\tasset_sets\tused_assets\tunused_assets\tempty_asset_sets"
+		row := fmt.Sprintf(
+			"%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d",
+			result.Command,
+			result.Path,
+			result.Workers,
+			result.Summary.AssetCatalogs,
+			result.Summary.AssetSets,
+			result.Summary.UsedAssets,
+			result.Summary.UnusedAssets,
+			result.Summary.EmptyAssetSets,
+		)
+		if result.wide != nil {
+			header += "\tduplicate_names\tempty_catalogs"
+			row += fmt.Sprintf("\t%d\t%d", result.wide.DuplicateNames, result.wide.EmptyCatalogs)
+		}
+		if _, err := fmt.Fprintf(tw, "%s\n%s\n", header, row); err != nil {
+			return err
+		}
+		if result.wide != nil && len(result.wide.Catalogs) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nCatalogs"); err != nil {
+				return err
+			}
+			for _, catalog := range result.wide.Catalogs {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\t%d\n", catalog.Path, catalog.AssetSets); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.AssetTypeBreakdown) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nAsset Types\nasset_type\tasset_sets\tused_assets\tunused_assets"); err != nil {
+				return err
+			}
+			for _, assetType := range sortedStringKeys(result.AssetTypeBreakdown) {
+				counts := result.AssetTypeBreakdown[assetType]
+				if _, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", assetType, counts.AssetSets, counts.UsedAssets, counts.UnusedAssets); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.Modules) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nModules\nmodule\tasset_sets\tused_assets\tunused_assets"); err != nil {
+				return err
+			}
+			for _, module := range result.Modules {
+				if _, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", module.Path, module.AssetSets, module.UsedAssets, module.UnusedAssets); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.RuleMatchCounts) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nRule Match Counts"); err != nil {
+				return err
+			}
+			for _, rule := range sortedStringKeys(result.RuleMatchCounts) {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\t%d\n", rule, result.RuleMatchCounts[rule]); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.UnusedExplained) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nSearched Candidates For Unused Assets"); err != nil {
+				return err
+			}
+			for _, explanation := range result.UnusedExplained {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\t%s\n", explanation.Path, strings.Join(explanation.SearchedCandidates, ", ")); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.UnusedByFile) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nUnused Assets (Grouped By File)"); err != nil {
+				return err
+			}
+			for _, file := range sortedStringKeys(result.UnusedByFile) {
+				if _, err := fmt.Fprintf(tw, "%s\n", file); err != nil {
+					return err
+				}
+				for _, asset := range result.UnusedByFile[file].UnusedAssets {
+					if _, err := fmt.Fprintf(tw, "  -\t%s\n", asset); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(result.EmptyAssetSets) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nEmpty Asset Sets"); err != nil {
+				return err
+			}
+			for _, path := range result.EmptyAssetSets {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\n", path); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.EmptyCatalogs) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nEmpty Asset Catalogs"); err != nil {
+				return err
+			}
+			for _, path := range result.EmptyCatalogs {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\n", path); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.TypeMismatches) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nAsset Type Mismatches"); err != nil {
+				return err
+			}
+			for _, mismatch := range result.TypeMismatches {
+				if _, err := fmt.Fprintf(tw, "  -\t%s.%s\t%s\t%s\n", mismatch.Name, mismatch.RequestedType, strings.Join(mismatch.ExistingTypes, ", "), mismatch.Source); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.DuplicateNames) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nDuplicate Asset Names"); err != nil {
+				return err
+			}
+			for _, duplicate := range result.DuplicateNames {
+				if _, err := fmt.Fprintf(tw, "%s.%s\n", duplicate.Name, duplicate.AssetType); err != nil {
+					return err
+				}
+				for _, catalog := range duplicate.Catalogs {
+					if _, err := fmt.Fprintf(tw, "  -\t%s\n", catalog); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(result.CaseCollisions) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nAsset Name Case Collisions"); err != nil {
+				return err
+			}
+			for _, collision := range result.CaseCollisions {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\t%s\t%s\n", strings.Join(collision.Names, ", "), collision.AssetType, collision.Catalog); err != nil {
+					return err
+				}
+			}
+		}
+		return tw.Flush()
+	case outputMarkdown:
+		if _, err := fmt.Fprintf(w,
+			"| command | path | workers | asset_catalogs | asset_sets | used_assets | unused_assets | empty_asset_sets |\n|---|---|---:|---:|---:|---:|---:|---:|\n| %s | %s | %d | %d | %d | %d | %d | %d |\n",
+			result.Command,
+			result.Path,
+			result.Workers,
+			result.Summary.AssetCatalogs,
+			result.Summary.AssetSets,
+			result.Summary.UsedAssets,
+			result.Summary.UnusedAssets,
+			result.Summary.EmptyAssetSets,
+		); err != nil {
+			return err
+		}
+		if len(result.AssetTypeBreakdown) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| asset_type | asset_sets | used_assets | unused_assets |\n|---|---:|---:|---:|"); err != nil {
+				return err
+			}
+			for _, assetType := range sortedStringKeys(result.AssetTypeBreakdown) {
+				counts := result.AssetTypeBreakdown[assetType]
+				if _, err := fmt.Fprintf(w, "| %s | %d | %d | %d |\n", assetType, counts.AssetSets, counts.UsedAssets, counts.UnusedAssets); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.Modules) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| module | asset_sets | used_assets | unused_assets |\n|---|---:|---:|---:|"); err != nil {
+				return err
+			}
+			for _, module := range result.Modules {
+				if _, err := fmt.Fprintf(w, "| %s | %d | %d | %d |\n", module.Path, module.AssetSets, module.UsedAssets, module.UnusedAssets); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.RuleMatchCounts) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| rule | matches |\n|---|---:|"); err != nil {
+				return err
+			}
+			for _, rule := range sortedStringKeys(result.RuleMatchCounts) {
+				if _, err := fmt.Fprintf(w, "| %s | %d |\n", rule, result.RuleMatchCounts[rule]); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.UnusedExplained) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| unused_asset | searched_candidates |\n|---|---|"); err != nil {
+				return err
+			}
+			for _, explanation := range result.UnusedExplained {
+				if _, err := fmt.Fprintf(w, "| %s | %s |\n", explanation.Path, strings.Join(explanation.SearchedCandidates, ", ")); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.UnusedByFile) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| file | asset |\n|---|---|"); err != nil {
+				return err
+			}
+			for _, file := range sortedStringKeys(result.UnusedByFile) {
+				for _, asset := range result.UnusedByFile[file].UnusedAssets {
+					if _, err := fmt.Fprintf(w, "| %s | %s |\n", file, asset); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(result.EmptyAssetSets) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| empty_asset_set |\n|---|"); err != nil {
+				return err
+			}
+			for _, path := range result.EmptyAssetSets {
+				if _, err := fmt.Fprintf(w, "| %s |\n", path); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.EmptyCatalogs) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| empty_catalog |\n|---|"); err != nil {
+				return err
+			}
+			for _, path := range result.EmptyCatalogs {
+				if _, err := fmt.Fprintf(w, "| %s |\n", path); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.TypeMismatches) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| asset | requested_type | existing_types | source |\n|---|---|---|---|"); err != nil {
+				return err
+			}
+			for _, mismatch := range result.TypeMismatches {
+				if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s |\n", mismatch.Name, mismatch.RequestedType, strings.Join(mismatch.ExistingTypes, ", "), mismatch.Source); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.CaseCollisions) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| case_collision | asset_type | catalog |\n|---|---|---|"); err != nil {
+				return err
+			}
+			for _, collision := range result.CaseCollisions {
+				if _, err := fmt.Fprintf(w, "| %s | %s | %s |\n", strings.Join(collision.Names, ", "), collision.AssetType, collision.Catalog); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.DuplicateNames) == 0 {
+			return nil
+		}
+		if _, err := fmt.Fprintln(w, "\n| asset | asset_type | catalog |\n|---|---|---|"); err != nil {
+			return err
+		}
+		for _, duplicate := range result.DuplicateNames {
+			for _, catalog := range duplicate.Catalogs {
+				if _, err := fmt.Fprintf(w, "| %s | %s | %s |\n", duplicate.Name, duplicate.AssetType, catalog); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		return writeCSV(w, []string{"command", "path", "workers", "asset_catalogs", "asset_sets", "used_assets", "unused_assets", "empty_asset_sets"}, [][]string{{
+			result.Command,
+			result.Path,
+			strconv.Itoa(result.Workers),
+			strconv.Itoa(result.Summary.AssetCatalogs),
+			strconv.Itoa(result.Summary.AssetSets),
+			strconv.Itoa(result.Summary.UsedAssets),
+			strconv.Itoa(result.Summary.UnusedAssets),
+			strconv.Itoa(result.Summary.EmptyAssetSets),
+		}})
+	default:
+		return invalidOutputError(format.name)
+	}
+}
+
+func renderAssetNames(w io.Writer, format outputFormat, names []string) error {
+	switch format.name {
+	case outputJSON:
+		if names == nil {
+			names = []string{}
+		}
+		return writeJSON(w, names, format.indent)
+	case outputTable:
+		for _, name := range names {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputMarkdown:
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "- %s\n", name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputYAML:
+		if names == nil {
+			names = []string{}
+		}
+		return writeYAML(w, names)
+	case outputCSV:
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			rows = append(rows, []string{name})
+		}
+		return writeCSV(w, []string{"asset"}, rows)
+	default:
+		return invalidOutputError(format.name)
+	}
+}
+
+func renderCatalogsResult(w io.Writer, format outputFormat, result catalogsResult) error {
+	switch format.name {
+	case outputJSON:
+		return writeJSON(w, result, format.indent)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintln(tw, "catalog\tasset_sets"); err != nil {
+			return err
+		}
+		for _, catalog := range result.Catalogs {
+			if _, err := fmt.Fprintf(tw, "%s\t%d\n", catalog.Path, catalog.AssetSets); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	case outputMarkdown:
+		if _, err := fmt.Fprintln(w, "| catalog | asset_sets |\n|---|---:|"); err != nil {
+			return err
+		}
+		for _, catalog := range result.Catalogs {
+			if _, err := fmt.Fprintf(w, "| %s | %d |\n", catalog.Path, catalog.AssetSets); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		rows := make([][]string, 0, len(result.Catalogs))
+		for _, catalog := range result.Catalogs {
+			rows = append(rows, []string{catalog.Path, strconv.Itoa(catalog.AssetSets)})
+		}
+		return writeCSV(w, []string{"catalog", "asset_sets"}, rows)
+	default:
+		return invalidOutputError(format.name)
+	}
+}
+
+func renderExplainResult(w io.Writer, format outputFormat, result explainResult) error {
+	switch format.name {
+	case outputJSON:
+		return writeJSON(w, result, format.indent)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintf(tw, "asset\t%s\nused\t%t\n", result.Asset, result.Used); err != nil {
+			return err
+		}
+		if len(result.References) == 0 {
+			if _, err := fmt.Fprintln(tw, "\nno references found"); err != nil {
+				return err
+			}
+			return tw.Flush()
+		}
+		if _, err := fmt.Fprintln(tw, "\nsource\trule\ttext"); err != nil {
+			return err
+		}
+		for _, reference := range result.References {
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", reference.Source, reference.Rule, reference.Text); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	case outputMarkdown:
+		if _, err := fmt.Fprintf(w, "| asset | used |\n|---|---|\n| %s | %t |\n", result.Asset, result.Used); err != nil {
+			return err
+		}
+		if len(result.References) == 0 {
+			_, err := fmt.Fprintln(w, "\nno references found")
+			return err
+		}
+		if _, err := fmt.Fprintln(w, "\n| source | rule | text |\n|---|---|---|"); err != nil {
+			return err
+		}
+		for _, reference := range result.References {
+			if _, err := fmt.Fprintf(w, "| %s | %s | `%s` |\n", reference.Source, reference.Rule, reference.Text); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		rows := make([][]string, 0, len(result.References))
+		for _, reference := range result.References {
+			rows = append(rows, []string{result.Asset, strconv.FormatBool(result.Used), reference.Source, reference.Rule, reference.Text})
+		}
+		return writeCSV(w, []string{"asset", "used", "source", "rule", "text"}, rows)
+	default:
+		return invalidOutputError(format.name)
+	}
+}
+
+// withGroupedPayloads returns result with UnusedByFile and UnusedByGroup built
+// from the asset-set paths unless they are already set.
+func (result unusedResult) withGroupedPayloads() unusedResult {
+	if result.UnusedByFile == nil {
+		result.UnusedByFile = buildUnusedByFilePayload(result.unusedPathsByFile)
+	}
+	if result.UnusedByGroup == nil {
+		result.UnusedByGroup = buildUnusedByFilePayload(result.unusedPathsByGroup)
+	}
+	return result
+}
+
+func renderUnusedResult(w io.Writer, format outputFormat, result unusedResult) error {
+	if format.name == outputJSON && format.indent == "" {
+		return writeUnusedResultJSON(w, result)
+	}
+	result = result.withGroupedPayloads()
+	switch format.name {
+	case outputJSON:
+		return writeJSON(w, result, format.indent)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintln(tw, "Summary"); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(tw, "  Command:\t%s\n", result.Command); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(tw, "  Path:\t%s\n", result.Path); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(tw, "  Unused Count:\t%d\n", result.UnusedCount); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(tw, "  Prune Candidate Count:\t%d\n", result.PruneCandidateCount); err != nil {
+			return err
+		}
+		if len(result.Unused) > 0 {
+			if _, err := fmt.Fprintf(tw, "\nUnused Assets (Grouped By %s)\n", groupByTitle(result.GroupBy)); err != nil {
+				return err
+			}
+			for _, group := range sortedStringKeys(result.UnusedByGroup) {
+				if _, err := fmt.Fprintf(tw, "%s\n", group); err != nil {
+					return err
+				}
+				for _, asset := range result.UnusedByGroup[group].UnusedAssets {
+					if _, err := fmt.Fprintf(tw, "  -\t%s\n", asset); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(result.UsedOnlyInTests) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nUsed Only In Tests"); err != nil {
+				return err
+			}
+			for _, asset := range result.UsedOnlyInTests {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\n", asset); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.UsedOnlyInPreviews) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nUsed Only In Previews"); err != nil {
+				return err
+			}
+			for _, asset := range result.UsedOnlyInPreviews {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\n", asset); err != nil {
+					return err
+				}
+			}
+		}
+		return tw.Flush()
+	case outputMarkdown:
+		if _, err := fmt.Fprintf(w, "| command | path | unused_count | prune_candidate_count |\n|---|---|---:|---:|\n| %s | %s | %d | %d |\n", result.Command, result.Path, result.UnusedCount, result.PruneCandidateCount); err != nil {
+			return err
+		}
+		if len(result.Unused) > 0 {
+			groupColumn := "file"
+			if result.GroupBy != "" && result.GroupBy != groupByCatalog {
+				groupColumn = result.GroupBy
+			}
+			if _, err := fmt.Fprintf(w, "\n| %s | asset |\n|---|---|\n", groupColumn); err != nil {
+				return err
+			}
+			for _, group := range sortedStringKeys(result.UnusedByGroup) {
+				for _, asset := range result.UnusedByGroup[group].UnusedAssets {
+					if _, err := fmt.Fprintf(w, "| %s | %s |\n", group, asset); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(result.UsedOnlyInTests) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| used_only_in_tests |\n|---|"); err != nil {
+				return err
+			}
+			for _, asset := range result.UsedOnlyInTests {
+				if _, err := fmt.Fprintf(w, "| %s |\n", asset); err != nil {
+					return err
+				}
+			}
+		}
+		if len(result.UsedOnlyInPreviews) > 0 {
+			if _, err := fmt.Fprintln(w, "\n| used_only_in_previews |\n|---|"); err != nil {
+				return err
+			}
+			for _, asset := range result.UsedOnlyInPreviews {
+				if _, err := fmt.Fprintf(w, "| %s |\n", asset); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		groupColumn := groupByCatalog
+		if result.GroupBy != "" {
+			groupColumn = result.GroupBy
 		}
-		return filepath.Join(home, path[2:]), nil
+		rows := make([][]string, 0, len(result.unusedPathsByGroup))
+		for _, group := range sortedStringKeys(result.unusedPathsByGroup) {
+			for _, assetPath := range result.unusedPathsByGroup[group] {
+				assetType := strings.TrimPrefix(filepath.Ext(assetPath), ".")
+				rows = append(rows, []string{group, assetNameFromPath(assetPath), assetType})
+			}
+		}
+		return writeCSV(w, []string{groupColumn, "asset", "assetType"}, rows)
+	default:
+		return invalidOutputError(format.name)
 	}
+}
 
-	return path, nil
+func groupByTitle(groupBy string) string {
+	switch groupBy {
+	case groupByType:
+		return "Type"
+	case groupByDirectory:
+		return "Directory"
+	default:
+		return "Catalog"
+	}
 }
 
-func renderScanResult(w io.Writer, output string, result scanResult) error {
-	switch output {
+// renderWatchResult writes one report per scan; JSON output is one object
+// per line and YAML output one document per scan.
+func renderWatchResult(w io.Writer, format outputFormat, result watchResult) error {
+	switch format.name {
 	case outputJSON:
-		return writeJSON(w, result)
+		return writeJSON(w, result, format.indent)
 	case outputTable:
 		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-		if _, err := fmt.Fprintln(tw, "command\tpath\tworkers\tasset_catalogs\tasset_sets\tused_assets\tunused_assets"); err != nil {
+		if _, err := fmt.Fprintf(tw, "scan\tpath\tunused_count\n%d\t%s\t%d\n", result.Scan, result.Path, result.UnusedCount); err != nil {
 			return err
 		}
-		if _, err := fmt.Fprintf(
-			tw,
-			"%s\t%s\t%d\t%d\t%d\t%d\t%d\n",
-			result.Command,
-			result.Path,
-			result.Workers,
-			result.Summary.AssetCatalogs,
-			result.Summary.AssetSets,
-			result.Summary.UsedAssets,
-			result.Summary.UnusedAssets,
-		); err != nil {
-			return err
+		for _, asset := range result.Unused {
+			if _, err := fmt.Fprintf(tw, "  -\t%s\n", asset); err != nil {
+				return err
+			}
 		}
 		return tw.Flush()
 	case outputMarkdown:
-		_, err := fmt.Fprintf(w,
-			"| command | path | workers | asset_catalogs | asset_sets | used_assets | unused_assets |\n|---|---|---:|---:|---:|---:|---:|\n| %s | %s | %d | %d | %d | %d | %d |\n",
-			result.Command,
+		if _, err := fmt.Fprintf(w, "| scan | path | unused_count |\n|---:|---|---:|\n| %d | %s | %d |\n", result.Scan, result.Path, result.UnusedCount); err != nil {
+			return err
+		}
+		for _, asset := range result.Unused {
+			if _, err := fmt.Fprintf(w, "- %s\n", asset); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputYAML:
+		if _, err := fmt.Fprintln(w, "---"); err != nil {
+			return err
+		}
+		return writeYAML(w, result)
+	case outputCSV:
+		return writeCSV(w, []string{"scan", "path", "unused_count"}, [][]string{{
+			strconv.Itoa(result.Scan),
 			result.Path,
-			result.Workers,
-			result.Summary.AssetCatalogs,
-			result.Summary.AssetSets,
-			result.Summary.UsedAssets,
-			result.Summary.UnusedAssets,
-		)
-		return err
+			strconv.Itoa(result.UnusedCount),
+		}})
 	default:
-		return usageError{Message: fmt.Sprintf("invalid value for --output: %q (allowed: json, table, markdown)", output)}
+		return invalidOutputError(format.name)
 	}
 }
 
-func renderUnusedResult(w io.Writer, output string, result unusedResult) error {
-	switch output {
+func renderDoctorResult(w io.Writer, format outputFormat, result doctorResult) error {
+	extensions := sortedStringKeys(result.SourceFiles)
+	switch format.name {
 	case outputJSON:
-		return writeJSON(w, result)
+		return writeJSON(w, result, format.indent)
 	case outputTable:
 		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-		if _, err := fmt.Fprintln(tw, "Summary"); err != nil {
+		if _, err := fmt.Fprintf(tw, "path\tcatalogs\tgit_available\tdefault_excludes_matched\twarnings\n%s\t%d\t%t\t%t\t%d\n", result.Path, result.Catalogs, result.GitAvailable, result.DefaultExcludesMatched, len(result.Warnings)); err != nil {
 			return err
 		}
-		if _, err := fmt.Fprintf(tw, "  Command:\t%s\n", result.Command); err != nil {
+		if _, err := fmt.Fprintln(tw, "\nSource files"); err != nil {
 			return err
 		}
-		if _, err := fmt.Fprintf(tw, "  Path:\t%s\n", result.Path); err != nil {
-			return err
+		for _, ext := range extensions {
+			if _, err := fmt.Fprintf(tw, "  -\t%s\t%d\n", ext, result.SourceFiles[ext]); err != nil {
+				return err
+			}
 		}
-		if _, err := fmt.Fprintf(tw, "  Unused Count:\t%d\n", result.UnusedCount); err != nil {
+		if _, err := fmt.Fprintln(tw, "\nExcludes"); err != nil {
 			return err
 		}
-		if _, err := fmt.Fprintf(tw, "  Prune Candidate Count:\t%d\n", result.PruneCandidateCount); err != nil {
-			return err
+		for _, exclude := range result.Excludes {
+			if _, err := fmt.Fprintf(tw, "  -\t%s\tdefault=%t\t%d\n", exclude.Pattern, exclude.Default, exclude.Matches); err != nil {
+				return err
+			}
 		}
-		if len(result.Unused) > 0 {
-			if _, err := fmt.Fprintln(tw, "\nUnused Assets (Grouped By Catalog)"); err != nil {
+		if len(result.Warnings) > 0 {
+			if _, err := fmt.Fprintln(tw, "\nWarnings"); err != nil {
 				return err
 			}
-			for _, file := range sortedStringKeys(result.UnusedByFile) {
-				if _, err := fmt.Fprintf(tw, "%s\n", file); err != nil {
+			for _, warning := range result.Warnings {
+				if _, err := fmt.Fprintf(tw, "  -\t%s\n", warning); err != nil {
 					return err
 				}
-				for _, asset := range result.UnusedByFile[file].UnusedAssets {
-					if _, err := fmt.Fprintf(tw, "  -\t%s\n", asset); err != nil {
-						return err
-					}
-				}
 			}
 		}
 		return tw.Flush()
 	case outputMarkdown:
-		if _, err := fmt.Fprintf(w, "| command | path | unused_count | prune_candidate_count |\n|---|---|---:|---:|\n| %s | %s | %d | %d |\n", result.Command, result.Path, result.UnusedCount, result.PruneCandidateCount); err != nil {
+		if _, err := fmt.Fprintf(w, "| path | catalogs | git_available | default_excludes_matched | warnings |\n|---|---:|---|---|---:|\n| %s | %d | %t | %t | %d |\n", result.Path, result.Catalogs, result.GitAvailable, result.DefaultExcludesMatched, len(result.Warnings)); err != nil {
 			return err
 		}
-		if len(result.Unused) == 0 {
-			return nil
+		if _, err := fmt.Fprintln(w, "\n| extension | source_files |\n|---|---:|"); err != nil {
+			return err
 		}
-		if _, err := fmt.Fprintln(w, "\n| file | asset |\n|---|---|"); err != nil {
+		for _, ext := range extensions {
+			if _, err := fmt.Fprintf(w, "| %s | %d |\n", ext, result.SourceFiles[ext]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, "\n| exclude | default | matches |\n|---|---|---:|"); err != nil {
 			return err
 		}
-		for _, file := range sortedStringKeys(result.UnusedByFile) {
-			for _, asset := range result.UnusedByFile[file].UnusedAssets {
-				if _, err := fmt.Fprintf(w, "| %s | %s |\n", file, asset); err != nil {
+		for _, exclude := range result.Excludes {
+			if _, err := fmt.Fprintf(w, "| %s | %t | %d |\n", exclude.Pattern, exclude.Default, exclude.Matches); err != nil {
+				return err
+			}
+		}
+		if len(result.Warnings) > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+			for _, warning := range result.Warnings {
+				if _, err := fmt.Fprintf(w, "- %s\n", warning); err != nil {
 					return err
 				}
 			}
 		}
 		return nil
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		return writeCSV(w, []string{"path", "catalogs", "git_available", "default_excludes_matched", "warnings"}, [][]string{{
+			result.Path,
+			strconv.Itoa(result.Catalogs),
+			strconv.FormatBool(result.GitAvailable),
+			strconv.FormatBool(result.DefaultExcludesMatched),
+			strconv.Itoa(len(result.Warnings)),
+		}})
 	default:
-		return usageError{Message: fmt.Sprintf("invalid value for --output: %q (allowed: json, table, markdown)", output)}
+		return invalidOutputError(format.name)
 	}
 }
 
-func renderPruneResult(w io.Writer, output string, result pruneResult) error {
-	switch output {
+func renderPruneResult(w io.Writer, format outputFormat, result pruneResult) error {
+	switch format.name {
 	case outputJSON:
-		return writeJSON(w, result)
+		return writeJSON(w, result, format.indent)
 	case outputTable:
 		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
 		if _, err := fmt.Fprintf(tw, "command\tpath\tapply\tforce\tdry_run\tunused_count\tprune_candidate_count\tdeleted_count\n%s\t%s\t%t\t%t\t%t\t%d\t%d\t%d\n", result.Command, result.Path, result.Apply, result.Force, result.DryRun, result.UnusedCount, result.PruneCandidateCount, len(result.Deleted)); err != nil {
@@ -447,9 +2190,147 @@ func renderPruneResult(w io.Writer, output string, result pruneResult) error {
 	case outputMarkdown:
 		_, err := fmt.Fprintf(w, "| command | path | apply | force | dry_run | unused_count | prune_candidate_count | deleted_count |\n|---|---|---|---|---|---:|---:|---:|\n| %s | %s | %t | %t | %t | %d | %d | %d |\n", result.Command, result.Path, result.Apply, result.Force, result.DryRun, result.UnusedCount, result.PruneCandidateCount, len(result.Deleted))
 		return err
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		return writeCSV(w, []string{"command", "path", "apply", "force", "dry_run", "unused_count", "prune_candidate_count", "deleted_count"}, [][]string{{
+			result.Command,
+			result.Path,
+			strconv.FormatBool(result.Apply),
+			strconv.FormatBool(result.Force),
+			strconv.FormatBool(result.DryRun),
+			strconv.Itoa(result.UnusedCount),
+			strconv.Itoa(result.PruneCandidateCount),
+			strconv.Itoa(len(result.Deleted)),
+		}})
 	default:
-		return usageError{Message: fmt.Sprintf("invalid value for --output: %q (allowed: json, table, markdown)", output)}
+		return invalidOutputError(format.name)
+	}
+}
+
+// writeUnusedResultJSON writes result.withGroupedPayloads() exactly as
+// writeJSON would without indentation, but encodes each catalog and group
+// entry straight from the asset-set paths, so the grouped display-name maps
+// are never built in memory. Keep the field order in sync with unusedResult.
+func writeUnusedResultJSON(w io.Writer, result unusedResult) error {
+	bw := bufio.NewWriter(w)
+	sw := &jsonStreamWriter{w: bw}
+	sw.raw(`{"command":`)
+	sw.value(result.Command)
+	sw.raw(`,"path":`)
+	sw.value(result.Path)
+	sw.raw(`,"unusedCount":`)
+	sw.value(result.UnusedCount)
+	sw.raw(`,"pruneCandidateCount":`)
+	sw.value(result.PruneCandidateCount)
+	sw.raw(`,"unused":`)
+	sw.strings(result.Unused)
+	sw.raw(`,"unusedByFile":`)
+	sw.groupedAssets(result.UnusedByFile, result.unusedPathsByFile)
+	sw.raw(`,"groupBy":`)
+	sw.value(result.GroupBy)
+	sw.raw(`,"unusedByGroup":`)
+	sw.groupedAssets(result.UnusedByGroup, result.unusedPathsByGroup)
+	if len(result.UsedOnlyInTests) > 0 {
+		sw.raw(`,"usedOnlyInTests":`)
+		sw.strings(result.UsedOnlyInTests)
+	}
+	if len(result.UsedOnlyInPreviews) > 0 {
+		sw.raw(`,"usedOnlyInPreviews":`)
+		sw.strings(result.UsedOnlyInPreviews)
+	}
+	if result.Truncated {
+		sw.raw(`,"truncated":true`)
+	}
+	if result.TotalUnused != nil {
+		sw.raw(`,"totalUnused":`)
+		sw.value(*result.TotalUnused)
+	}
+	if result.DurationMs != nil {
+		sw.raw(`,"durationMs":`)
+		sw.value(*result.DurationMs)
+	}
+	sw.raw("}\n")
+	if sw.err != nil {
+		return sw.err
+	}
+	return bw.Flush()
+}
+
+// jsonStreamWriter writes JSON fragments, keeping the first error.
+type jsonStreamWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (sw *jsonStreamWriter) raw(s string) {
+	if sw.err == nil {
+		_, sw.err = io.WriteString(sw.w, s)
+	}
+}
+
+func (sw *jsonStreamWriter) value(v any) {
+	if sw.err != nil {
+		return
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		sw.err = err
+		return
+	}
+	_, sw.err = sw.w.Write(payload)
+}
+
+func (sw *jsonStreamWriter) strings(values []string) {
+	if values == nil {
+		sw.raw("null")
+		return
+	}
+	sw.raw("[")
+	for i, value := range values {
+		if i > 0 {
+			sw.raw(",")
+		}
+		sw.value(value)
+	}
+	sw.raw("]")
+}
+
+// groupedAssets writes payload when it is set, and otherwise encodes paths
+// one entry at a time as buildUnusedByFilePayload would.
+func (sw *jsonStreamWriter) groupedAssets(payload map[string]unusedFileResult, paths map[string][]string) {
+	sw.raw("{")
+	if payload != nil {
+		for i, key := range sortedStringKeys(payload) {
+			sw.groupEntry(i, key, payload[key].UnusedAssets)
+		}
+	} else {
+		for i, key := range sortedStringKeys(paths) {
+			sw.groupEntry(i, key, unusedAssetDisplayNames(paths[key]))
+		}
+	}
+	sw.raw("}")
+}
+
+func (sw *jsonStreamWriter) groupEntry(i int, key string, unusedAssets []string) {
+	if i > 0 {
+		sw.raw(",")
+	}
+	sw.value(key)
+	sw.raw(`:{"unusedAssets":`)
+	sw.strings(unusedAssets)
+	sw.raw("}")
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
 	}
+	return cw.Error()
 }
 
 func sortedStringKeys[T any](m map[string]T) []string {
@@ -476,6 +2357,58 @@ func buildUnusedByFilePayload(grouped map[string][]string) map[string]unusedFile
 	return out
 }
 
+func validateMaxResults(maxResults int) error {
+	if maxResults < 0 {
+		return usageError{Message: fmt.Sprintf("invalid value for --max-results: %d (must be >= 0)", maxResults)}
+	}
+	return nil
+}
+
+// totalUnusedOption returns the number of unused asset sets in unusedByFile
+// when --max-results is set, and nil otherwise.
+func totalUnusedOption(unusedByFile map[string][]string, maxResults int) *int {
+	if maxResults == 0 {
+		return nil
+	}
+	total := 0
+	for _, paths := range unusedByFile {
+		total += len(paths)
+	}
+	return &total
+}
+
+// truncateList keeps the first limit values and reports whether any were
+// dropped. A limit of 0 keeps every value.
+func truncateList(values []string, limit int) ([]string, bool) {
+	if limit == 0 || len(values) <= limit {
+		return values, false
+	}
+	return values[:limit], true
+}
+
+// truncateUnusedPaths keeps the first limit asset-set paths of unusedByFile
+// in catalog order and reports whether any were dropped. A limit of 0 keeps
+// every path.
+func truncateUnusedPaths(unusedByFile map[string][]string, limit int) (map[string][]string, bool) {
+	if limit == 0 {
+		return unusedByFile, false
+	}
+	out := make(map[string][]string, len(unusedByFile))
+	remaining := limit
+	for _, catalog := range sortedStringKeys(unusedByFile) {
+		paths := unusedByFile[catalog]
+		if len(paths) > remaining {
+			if remaining > 0 {
+				out[catalog] = paths[:remaining]
+			}
+			return out, true
+		}
+		out[catalog] = paths
+		remaining -= len(paths)
+	}
+	return out, false
+}
+
 func flattenUnusedByFileNames(grouped map[string]unusedFileResult) []string {
 	if len(grouped) == 0 {
 		return []string{}
@@ -543,13 +2476,18 @@ func validateGlobPatterns(patterns []string, flagName string) error {
 	return nil
 }
 
-func collectPruneTargets(grouped map[string][]string) []string {
+// collectPruneTargets returns the sorted prunable asset-set paths, limited to
+// the given asset types when types is non-empty.
+func collectPruneTargets(grouped map[string][]string, types []string) []string {
 	set := make(map[string]struct{})
 	for _, assetPaths := range grouped {
 		for _, assetPath := range assetPaths {
 			if !isPrunableAssetSetPath(assetPath) {
 				continue
 			}
+			if len(types) > 0 && !slices.Contains(types, strings.TrimPrefix(filepath.Ext(assetPath), ".")) {
+				continue
+			}
 			set[assetPath] = struct{}{}
 		}
 	}
@@ -562,16 +2500,116 @@ func collectPruneTargets(grouped map[string][]string) []string {
 	return out
 }
 
+// splitProtectedTargets removes protected paths from targets, returning the
+// remaining targets and the protected ones that were removed.
+func splitProtectedTargets(targets []string, protected []string) ([]string, []string) {
+	kept := make([]string, 0, len(targets))
+	var skipped []string
+	for _, target := range targets {
+		if _, found := slices.BinarySearch(protected, target); found {
+			skipped = append(skipped, target)
+			continue
+		}
+		kept = append(kept, target)
+	}
+	return kept, skipped
+}
+
+// normalizePruneTypes validates --type values against the prunable asset-set
+// extensions, accepting an optional leading dot, and returns them sorted.
+func normalizePruneTypes(types []string) ([]string, error) {
+	out := make([]string, 0, len(types))
+	for _, value := range types {
+		assetType := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), ".")
+		if !isPrunableAssetSetPath("x." + assetType) {
+			return nil, usageError{Message: fmt.Sprintf("invalid value for --type: %q (allowed: imageset, colorset, dataset, appiconset, symbolset, textureset)", value)}
+		}
+		out = append(out, assetType)
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
 func isPrunableAssetSetPath(path string) bool {
 	switch filepath.Ext(path) {
-	case ".imageset", ".colorset", ".dataset":
+	case ".imageset", ".colorset", ".dataset", ".appiconset", ".symbolset", ".textureset":
 		return true
 	default:
 		return false
 	}
 }
 
-func deletePruneTargets(root string, paths []string) error {
+func deletePruneTargets(root string, paths []string) ([]string, error) {
+	var removedGroups []string
+	err := applyPruneTargets(root, paths, func(path string, _ string) error {
+		// Remove the original path (the symlink entry itself), not the
+		// resolved target.
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", path, err)
+		}
+		removed, err := removeEmptiedGroupFolders(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		removedGroups = append(removedGroups, removed...)
+		return nil
+	})
+	return removedGroups, err
+}
+
+// removeEmptiedGroupFolders deletes dir and its parent group folders while
+// they sit inside a catalog and hold nothing but Contents.json and hidden
+// files. Group folder Contents.json files (including namespace folders) only
+// carry info and properties, never child references, so once a folder's last
+// asset set is gone the folder itself is the only reference left. It stops at
+// the catalog, at root, and at the first folder that still has content.
+func removeEmptiedGroupFolders(root string, dir string) ([]string, error) {
+	var removed []string
+	for filepath.Ext(dir) == "" {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			break
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return removed, fmt.Errorf("failed to inspect group folder %s: %w", dir, err)
+		}
+		if slices.ContainsFunc(entries, func(entry fs.DirEntry) bool {
+			name := entry.Name()
+			return entry.IsDir() || (name != "Contents.json" && !strings.HasPrefix(name, "."))
+		}) {
+			break
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, fmt.Errorf("failed to delete %s: %w", dir, err)
+		}
+		removed = append(removed, dir)
+		dir = filepath.Dir(dir)
+	}
+	return removed, nil
+}
+
+// movePruneTargets moves each target into backupDir at its path relative to
+// root, so pruned asset sets can be restored by moving them back.
+func movePruneTargets(root string, backupDir string, paths []string) error {
+	return applyPruneTargets(root, paths, func(path string, rel string) error {
+		destination := filepath.Join(backupDir, rel)
+		if _, err := os.Lstat(destination); err == nil {
+			return fmt.Errorf("refusing to overwrite existing backup path: %s", destination)
+		}
+		if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+			return fmt.Errorf("failed to create backup directory for %s: %w", path, err)
+		}
+		if err := os.Rename(path, destination); err != nil {
+			return fmt.Errorf("failed to move %s to %s: %w", path, destination, err)
+		}
+		return nil
+	})
+}
+
+// applyPruneTargets validates that every target is an asset set inside root
+// and then runs action with the target path and its path relative to root.
+func applyPruneTargets(root string, paths []string, action func(path string, rel string) error) error {
 	rootAbs, err := filepath.Abs(root)
 	if err != nil {
 		return fmt.Errorf("failed to resolve prune root %q: %w", root, err)
@@ -603,15 +2641,223 @@ func deletePruneTargets(root string, paths []string) error {
 		if !isPrunableAssetSetPath(path) {
 			return fmt.Errorf("refusing to delete non-asset-set path: %s", path)
 		}
-		// Remove the original path (the symlink entry itself), not the resolved
-		// absPath target.
-		if err := os.RemoveAll(path); err != nil {
-			return fmt.Errorf("failed to delete %s: %w", path, err)
+		if err := action(path, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneTargetCatalogs returns the sorted catalogs, keyed as in grouped, that
+// contain at least one of targets.
+func pruneTargetCatalogs(grouped map[string][]string, targets []string) []string {
+	targetSet := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		targetSet[target] = struct{}{}
+	}
+	catalogs := make([]string, 0)
+	for catalog, assetPaths := range grouped {
+		for _, assetPath := range assetPaths {
+			if _, ok := targetSet[assetPath]; ok {
+				catalogs = append(catalogs, catalog)
+				break
+			}
+		}
+	}
+	slices.Sort(catalogs)
+	return catalogs
+}
+
+// removeEmptiedCatalogs removes each catalog under root that no longer holds
+// anything but group folders, Contents.json and hidden files, and returns the
+// removed paths. The scan root itself and symlinked catalogs are never
+// removed.
+func removeEmptiedCatalogs(root string, catalogs []string) ([]string, error) {
+	removed := make([]string, 0, len(catalogs))
+	for _, catalog := range catalogs {
+		rel, err := filepath.Rel(root, catalog)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			continue
+		}
+		if !strings.EqualFold(filepath.Ext(catalog), ".xcassets") {
+			return nil, fmt.Errorf("refusing to delete non-catalog path: %s", catalog)
+		}
+		info, err := os.Lstat(catalog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect catalog %s: %w", catalog, err)
+		}
+		if !info.IsDir() {
+			continue
 		}
+		empty, err := isCatalogWithoutAssetSets(catalog)
+		if err != nil {
+			return nil, err
+		}
+		if !empty {
+			continue
+		}
+		if err := os.RemoveAll(catalog); err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", catalog, err)
+		}
+		removed = append(removed, catalog)
+	}
+	return removed, nil
+}
+
+// isCatalogWithoutAssetSets reports whether catalog holds only Contents.json
+// files, hidden files and extensionless group folders. Any other file or
+// typed folder, including set types xcwrap does not scan, keeps it.
+func isCatalogWithoutAssetSets(catalog string) (bool, error) {
+	empty := true
+	err := filepath.WalkDir(catalog, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == catalog {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if filepath.Ext(name) != "" {
+				empty = false
+				return filepath.SkipAll
+			}
+			return nil
+		}
+		if name == "Contents.json" || strings.HasPrefix(name, ".") {
+			return nil
+		}
+		empty = false
+		return filepath.SkipAll
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect catalog %s: %w", catalog, err)
+	}
+	return empty, nil
+}
+
+// resolveBackupDir returns the absolute backup directory after checking it
+// lies outside root and is writable, creating it when missing.
+func resolveBackupDir(root string, backupDir string) (string, error) {
+	expanded, err := expandTildePath(backupDir)
+	if err != nil {
+		return "", err
+	}
+	backupAbs, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve backup directory %q: %w", backupDir, err)
+	}
+	rel, err := filepath.Rel(root, backupAbs)
+	if err == nil && (rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)))) {
+		return "", usageError{Message: fmt.Sprintf("invalid value for --backup-dir: %q must be outside --path", backupDir)}
+	}
+	if err := os.MkdirAll(backupAbs, 0o755); err != nil {
+		return "", fmt.Errorf("backup directory is not writable: %s: %w", backupAbs, err)
+	}
+	probe, err := os.CreateTemp(backupAbs, ".xcwrap-write-check-*")
+	if err != nil {
+		return "", fmt.Errorf("backup directory is not writable: %s: %w", backupAbs, err)
+	}
+	probe.Close()
+	if err := os.Remove(probe.Name()); err != nil {
+		return "", fmt.Errorf("backup directory is not writable: %s: %w", backupAbs, err)
+	}
+	return backupAbs, nil
+}
+
+type pathNotFoundError struct {
+	Path string
+}
+
+func (e pathNotFoundError) Error() string {
+	return "path does not exist or is inaccessible: " + e.Path
+}
+
+type gitWorkingTreeDirtyError struct{}
+
+func (e gitWorkingTreeDirtyError) Error() string {
+	return "git working tree is not clean; commit/stash changes or rerun with --force"
+}
+
+// stageGitRemovals stages the removal of paths in the git index. It reports
+// false without error when root is not inside a git work tree.
+func stageGitRemovals(root string, paths []string) (bool, error) {
+	check := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree")
+	check.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	if out, err := check.Output(); err != nil || strings.TrimSpace(string(out)) != "true" {
+		return false, nil
+	}
+	if len(paths) == 0 {
+		return true, nil
+	}
+	// --cached with --ignore-unmatch only touches the index, so paths that
+	// were never tracked do not fail the command.
+	args := append([]string{"-C", root, "rm", "-r", "--cached", "--quiet", "--ignore-unmatch", "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(out))
+		if message == "" {
+			return false, fmt.Errorf("failed to stage removals: %w", err)
+		}
+		return false, fmt.Errorf("failed to stage removals: %w: %s", err, message)
+	}
+	return true, nil
+}
+
+// requireGitWorkTree fails unless git is installed and root lies inside a
+// git work tree, so --commit can report the problem before deleting files.
+func requireGitWorkTree(root string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("--commit requires git, which was not found in PATH: %w", err)
+	}
+	check := exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree")
+	check.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	if out, err := check.Output(); err != nil || strings.TrimSpace(string(out)) != "true" {
+		return fmt.Errorf("--commit requires %s to be inside a git work tree", root)
 	}
 	return nil
 }
 
+// commitGitRemovals commits the staged deletions of tracked files below
+// paths with message. Other staged changes stay out of the commit. It
+// returns the new commit hash, or "" when none of paths held tracked files.
+func commitGitRemovals(root string, paths []string, message string) (string, error) {
+	if len(paths) == 0 {
+		return "", nil
+	}
+	list := exec.Command("git", append([]string{"-C", root, "diff", "--cached", "--name-only", "--relative", "--diff-filter=D", "-z", "--"}, paths...)...)
+	list.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	out, err := list.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list staged removals: %w", err)
+	}
+	files := strings.Split(strings.TrimRight(string(out), "\x00"), "\x00")
+	if len(files) == 1 && files[0] == "" {
+		return "", nil
+	}
+
+	// Naming the files makes git commit only them, even under --force with
+	// other changes already staged.
+	commit := exec.Command("git", append([]string{"-C", root, "commit", "--quiet", "-m", message, "--"}, files...)...)
+	commit.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	if out, err := commit.CombinedOutput(); err != nil {
+		detail := strings.TrimSpace(string(out))
+		if detail == "" {
+			return "", fmt.Errorf("failed to commit removals: %w", err)
+		}
+		return "", fmt.Errorf("failed to commit removals: %w: %s", err, detail)
+	}
+	head := exec.Command("git", "-C", root, "rev-parse", "HEAD")
+	head.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	out, err = head.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit hash: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 func requireCleanGitWorkingTree(root string) error {
 	cmd := exec.Command("git", "-C", root, "status", "--porcelain")
 	cmd.Env = append(os.Environ(),
@@ -627,7 +2873,7 @@ func requireCleanGitWorkingTree(root string) error {
 		return fmt.Errorf("failed to check git working tree: %w: %s", err, message)
 	}
 	if len(bytes.TrimSpace(out)) > 0 {
-		return fmt.Errorf("git working tree is not clean; commit/stash changes or rerun with --force")
+		return gitWorkingTreeDirtyError{}
 	}
 	return nil
 }
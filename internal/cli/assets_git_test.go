@@ -38,7 +38,7 @@ func TestDeletePruneTargets_AllowsCatalogRootAsPruneRoot(t *testing.T) {
 		t.Fatalf("mkdir prune target: %v", err)
 	}
 
-	if err := deletePruneTargets(catalogRoot, []string{target}); err != nil {
+	if _, err := deletePruneTargets(catalogRoot, []string{target}); err != nil {
 		t.Fatalf("delete prune targets: %v", err)
 	}
 	if _, err := os.Stat(target); !os.IsNotExist(err) {
@@ -67,7 +67,7 @@ func TestDeletePruneTargets_RejectsSymlinkTargetOutsideRoot(t *testing.T) {
 		t.Fatalf("create symlink target: %v", err)
 	}
 
-	err := deletePruneTargets(root, []string{linkPath})
+	_, err := deletePruneTargets(root, []string{linkPath})
 	if err == nil {
 		t.Fatalf("expected symlink escape to be rejected")
 	}
@@ -99,7 +99,7 @@ func TestDeletePruneTargets_AllowsSymlinkedCatalogRoot(t *testing.T) {
 		t.Fatalf("mkdir prune target: %v", err)
 	}
 
-	if err := deletePruneTargets(symlinkCatalogRoot, []string{target}); err != nil {
+	if _, err := deletePruneTargets(symlinkCatalogRoot, []string{target}); err != nil {
 		t.Fatalf("delete prune targets: %v", err)
 	}
 	if _, err := os.Stat(target); !os.IsNotExist(err) {
@@ -2,6 +2,11 @@ package cli
 
 import (
 	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -19,7 +24,7 @@ func TestRenderPruneResult_TableUsesAlignedColumns(t *testing.T) {
 		Deleted:             []string{"a", "b"},
 	}
 
-	if err := renderPruneResult(&out, outputTable, result); err != nil {
+	if err := renderPruneResult(&out, outputFormat{name: outputTable}, result); err != nil {
 		t.Fatalf("render prune table: %v", err)
 	}
 
@@ -43,13 +48,14 @@ func TestRenderUnusedResult_TablePreservesCatalogIdentity(t *testing.T) {
 		UnusedCount:         2,
 		PruneCandidateCount: 2,
 		Unused:              []string{"icon"},
-		UnusedByFile: map[string]unusedFileResult{
+		GroupBy:             groupByCatalog,
+		UnusedByGroup: map[string]unusedFileResult{
 			"/tmp/repo/Modules/A/Assets.xcassets": {UnusedAssets: []string{"icon.imageset"}},
 			"/tmp/repo/Modules/B/Assets.xcassets": {UnusedAssets: []string{"icon.imageset"}},
 		},
 	}
 
-	if err := renderUnusedResult(&out, outputTable, result); err != nil {
+	if err := renderUnusedResult(&out, outputFormat{name: outputTable}, result); err != nil {
 		t.Fatalf("render unused table: %v", err)
 	}
 
@@ -64,3 +70,129 @@ func TestRenderUnusedResult_TablePreservesCatalogIdentity(t *testing.T) {
 		t.Fatalf("expected module B catalog path in output, got %q", rendered)
 	}
 }
+
+func TestRenderUnusedResult_StreamedJSONMatchesMarshal(t *testing.T) {
+	result := unusedResult{
+		Command:             "assets unused",
+		Path:                "/tmp/<repo>&\"quoted\"",
+		GroupBy:             groupByType,
+		UsedOnlyInTests:     []string{"Tests/Fixture"},
+		UsedOnlyInPreviews:  []string{"Previews/Hero"},
+		PruneCandidateCount: 1,
+		Truncated:           true,
+		unusedPathsByFile:   map[string][]string{},
+	}
+	durationMs := int64(1234)
+	result.DurationMs = &durationMs
+	totalUnused := 5000
+	result.TotalUnused = &totalUnused
+	for i := range 5000 {
+		catalog := fmt.Sprintf("/tmp/repo/Module%03d/Assets.xcassets", i%250)
+		name := fmt.Sprintf("icon_%05d<&>\u2028é", i)
+		ext := ".imageset"
+		if i%3 == 0 {
+			ext = ".colorset"
+		}
+		result.Unused = append(result.Unused, name)
+		result.unusedPathsByFile[catalog] = append(result.unusedPathsByFile[catalog], filepath.Join(catalog, name+ext))
+	}
+	result.unusedPathsByGroup = groupUnusedAssetPaths(result.unusedPathsByFile, result.GroupBy)
+	result.UnusedCount = len(result.Unused)
+
+	full := result.withGroupedPayloads()
+	value := reflect.ValueOf(full)
+	for i := range value.NumField() {
+		if value.Type().Field(i).IsExported() && value.Field(i).IsZero() {
+			t.Fatalf("expected field %s to be populated so the comparison covers it", value.Type().Field(i).Name)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := renderUnusedResult(&out, outputFormat{name: outputJSON}, result); err != nil {
+		t.Fatalf("render unused json: %v", err)
+	}
+	want, err := json.Marshal(full)
+	if err != nil {
+		t.Fatalf("marshal unused result: %v", err)
+	}
+	if got := out.String(); got != string(want)+"\n" {
+		t.Fatalf("expected streamed output to match json.Marshal (%d vs %d bytes)", len(got), len(want)+1)
+	}
+
+	out.Reset()
+	if err := renderUnusedResult(&out, outputFormat{name: outputJSON}, full); err != nil {
+		t.Fatalf("render prebuilt unused json: %v", err)
+	}
+	if got := out.String(); got != string(want)+"\n" {
+		t.Fatalf("expected prebuilt payloads to match json.Marshal (%d vs %d bytes)", len(got), len(want)+1)
+	}
+
+	out.Reset()
+	empty := unusedResult{Command: "assets unused"}
+	if err := renderUnusedResult(&out, outputFormat{name: outputJSON}, empty); err != nil {
+		t.Fatalf("render empty unused json: %v", err)
+	}
+	want, _ = json.Marshal(empty.withGroupedPayloads())
+	if got := out.String(); got != string(want)+"\n" {
+		t.Fatalf("expected empty result to match json.Marshal, got %q want %q", got, want)
+	}
+}
+
+func TestRenderScanResult_CSVEmitsSingleQuotedSummaryRow(t *testing.T) {
+	var out bytes.Buffer
+	result := scanResult{
+		Command: "assets scan",
+		Path:    "/tmp/repo, \"quoted\"",
+		Workers: 4,
+	}
+	result.Summary.AssetCatalogs = 1
+	result.Summary.AssetSets = 3
+	result.Summary.UsedAssets = 2
+	result.Summary.UnusedAssets = 1
+
+	if err := renderScanResult(&out, outputFormat{name: outputCSV}, result); err != nil {
+		t.Fatalf("render scan csv: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected header and one row, got %#v", records)
+	}
+	if records[0][0] != "command" || records[0][6] != "unused_assets" {
+		t.Fatalf("unexpected csv header: %#v", records[0])
+	}
+	if records[1][1] != result.Path || records[1][6] != "1" {
+		t.Fatalf("unexpected csv row: %#v", records[1])
+	}
+}
+
+func TestRenderResults_JSONEmitsLargeCountsAsIntegerLiterals(t *testing.T) {
+	const count = 100000
+	scan := scanResult{Command: "assets scan", Path: "/tmp/repo"}
+	scan.Summary.AssetSets = count
+	scan.Summary.UnusedAssets = count
+	unused := unusedResult{Command: "assets unused", UnusedCount: count, PruneCandidateCount: count}
+
+	var out bytes.Buffer
+	if err := renderScanResult(&out, outputFormat{name: outputJSON}, scan); err != nil {
+		t.Fatalf("render scan json: %v", err)
+	}
+	if err := renderUnusedResult(&out, outputFormat{name: outputJSON}, unused); err != nil {
+		t.Fatalf("render unused json: %v", err)
+	}
+	if err := renderUnusedResult(&out, outputFormat{name: outputYAML}, unused); err != nil {
+		t.Fatalf("render unused yaml: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{`"assetSets":100000,`, `"unusedAssets":100000,`, `"unusedCount":100000,`, `"pruneCandidateCount":100000,`, "unusedCount: 100000\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected integer literal %q in output, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, "e+") {
+		t.Fatalf("expected no scientific notation in output, got %s", got)
+	}
+}
@@ -1,18 +1,29 @@
 package cli
 
 import (
-	"errors"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"xcwrap/internal/assets"
 )
 
 const (
-	exitSuccess      = 0
-	exitFailure      = 1
-	exitUsage        = 2
-	exitUnusedAssets = 3
+	exitSuccess       = 0
+	exitFailure       = 1
+	exitUsage         = 2
+	exitUnusedAssets  = 3
+	exitDuplicates    = 4
+	exitEmptyCatalog  = 5
+	exitUsedBelow     = 6
+	exitTypeMismatch  = 7
+	exitWarnings      = 8
+	exitCaseCollision = 9
 )
 
 type usageError struct {
@@ -29,6 +40,42 @@ func (e unusedAssetsFoundError) Error() string {
 	return "unused assets detected"
 }
 
+type duplicateAssetNamesFoundError struct{}
+
+func (e duplicateAssetNamesFoundError) Error() string {
+	return "duplicate asset names detected"
+}
+
+type caseCollisionsFoundError struct{}
+
+func (e caseCollisionsFoundError) Error() string {
+	return "asset name case collisions detected"
+}
+
+type emptyCatalogsFoundError struct{}
+
+func (e emptyCatalogsFoundError) Error() string {
+	return "empty asset catalogs detected"
+}
+
+type usedAssetsBelowThresholdError struct{}
+
+func (e usedAssetsBelowThresholdError) Error() string {
+	return "used assets below threshold"
+}
+
+type typeMismatchesFoundError struct{}
+
+func (e typeMismatchesFoundError) Error() string {
+	return "asset type mismatches detected"
+}
+
+type warningsFoundError struct{}
+
+func (e warningsFoundError) Error() string {
+	return "scan warnings reported"
+}
+
 type errorEnvelope struct {
 	Error errorBody `json:"error"`
 }
@@ -43,22 +90,64 @@ func Execute(args []string, stdout io.Writer, stderr io.Writer) int {
 	root.SetArgs(args)
 
 	if err := root.Execute(); err != nil {
+		jsonErrors := jsonErrorsEnabled(root)
 		if isUsageExecutionError(err) {
-			writeError(stderr, "usage_error", err.Error())
+			writeError(stderr, jsonErrors, "usage_error", err.Error())
 			return exitUsage
 		}
 		var unusedErr unusedAssetsFoundError
 		if errors.As(err, &unusedErr) {
 			return exitUnusedAssets
 		}
+		var duplicatesErr duplicateAssetNamesFoundError
+		if errors.As(err, &duplicatesErr) {
+			return exitDuplicates
+		}
+		var caseCollisionsErr caseCollisionsFoundError
+		if errors.As(err, &caseCollisionsErr) {
+			return exitCaseCollision
+		}
+		var emptyCatalogsErr emptyCatalogsFoundError
+		if errors.As(err, &emptyCatalogsErr) {
+			return exitEmptyCatalog
+		}
+		var usedBelowErr usedAssetsBelowThresholdError
+		if errors.As(err, &usedBelowErr) {
+			return exitUsedBelow
+		}
+		var typeMismatchErr typeMismatchesFoundError
+		if errors.As(err, &typeMismatchErr) {
+			return exitTypeMismatch
+		}
+		var warningsErr warningsFoundError
+		if errors.As(err, &warningsErr) {
+			return exitWarnings
+		}
 
-		writeError(stderr, "runtime_error", err.Error())
+		writeError(stderr, jsonErrors, runtimeErrorCode(err), err.Error())
 		return exitFailure
 	}
 
 	return exitSuccess
 }
 
+// runtimeErrorCode classifies runtime failures into stable error codes,
+// falling back to runtime_error for anything unrecognized.
+func runtimeErrorCode(err error) string {
+	var pathErr pathNotFoundError
+	if errors.As(err, &pathErr) {
+		return "path_not_found"
+	}
+	var dirtyErr gitWorkingTreeDirtyError
+	if errors.As(err, &dirtyErr) {
+		return "git_dirty"
+	}
+	if errors.Is(err, assets.ErrReadFailure) {
+		return "read_failure"
+	}
+	return "runtime_error"
+}
+
 func isUsageExecutionError(err error) bool {
 	var usageErr usageError
 	if errors.As(err, &usageErr) {
@@ -86,8 +175,16 @@ func isUsageExecutionError(err error) bool {
 	return false
 }
 
-func writeJSON(w io.Writer, value any) error {
-	payload, err := json.Marshal(value)
+// writeJSON writes value as one JSON document followed by a newline, indented
+// by indent when it is non-empty.
+func writeJSON(w io.Writer, value any, indent string) error {
+	var payload []byte
+	var err error
+	if indent == "" {
+		payload, err = json.Marshal(value)
+	} else {
+		payload, err = json.MarshalIndent(value, "", indent)
+	}
 	if err != nil {
 		return err
 	}
@@ -96,11 +193,61 @@ func writeJSON(w io.Writer, value any) error {
 	return err
 }
 
-func writeError(w io.Writer, code, message string) {
+// writeYAML renders value as block-style YAML with the same keys and order
+// as its JSON encoding.
+func writeYAML(w io.Writer, value any) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(payload, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetYAMLStyle drops the flow and quoting styles inherited from JSON so the
+// encoder picks block style and only quotes scalars that need it.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// jsonErrorsEnabled reports whether errors use the JSON envelope. Only a
+// successfully parsed --json-errors overrides the XCWRAP_JSON_ERRORS default,
+// since a rejected flag value must still produce a JSON usage error.
+func jsonErrorsEnabled(root *cobra.Command) bool {
+	flag := root.PersistentFlags().Lookup("json-errors")
+	if flag == nil || !flag.Changed {
+		return defaultJSONErrors()
+	}
+	enabled, err := strconv.ParseBool(flag.Value.String())
+	if err != nil {
+		return true
+	}
+	return enabled
+}
+
+func writeError(w io.Writer, jsonErrors bool, code, message string) {
+	if !jsonErrors {
+		_, _ = fmt.Fprintf(w, "error: %s\n", message)
+		return
+	}
 	_ = writeJSON(w, errorEnvelope{
 		Error: errorBody{
 			Code:    code,
 			Message: message,
 		},
-	})
+	}, "")
 }
@@ -2,14 +2,23 @@ package cli
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
+	"xcwrap/internal/assets"
 )
 
 func TestAssetsScan_DefaultJSONOutput(t *testing.T) {
@@ -92,6 +101,35 @@ func TestUnknownSubcommand_ReturnsUsageError(t *testing.T) {
 	}
 }
 
+func TestVersionFlag_ReportsRulesVersion(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"--version"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if payload["version"] != version {
+		t.Fatalf("expected version %q, got %v", version, payload["version"])
+	}
+	if payload["rulesVersion"] != float64(assets.RulesVersion) {
+		t.Fatalf("expected rulesVersion %d, got %v", assets.RulesVersion, payload["rulesVersion"])
+	}
+}
+
+func TestVersionFlag_InvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"--version=latest"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
 func TestAssetsPrune_ForceWithoutApply_IsUsageError(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -132,9 +170,47 @@ func TestAssetsScan_ExpandsTildePath(t *testing.T) {
 	if payload["path"] != projectDir {
 		t.Fatalf("expected expanded path %q, got %v", projectDir, payload["path"])
 	}
+	if payload["inputPath"] != "~/Developer/fsm-ios" {
+		t.Fatalf("expected raw input path, got %v", payload["inputPath"])
+	}
+}
+
+func TestAssetsScan_ReportsRawInputPathAlongsideResolvedPath(t *testing.T) {
+	parent := t.TempDir()
+	projectDir := filepath.Join(parent, "App")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+	t.Chdir(parent)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", "./App/../App"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if payload.InputPath != "./App/../App" {
+		t.Fatalf("expected inputPath to match the raw flag value, got %q", payload.InputPath)
+	}
+	resolved, err := filepath.EvalSymlinks(payload.Path)
+	if err != nil {
+		t.Fatalf("resolve reported path: %v", err)
+	}
+	want, err := filepath.EvalSymlinks(projectDir)
+	if err != nil {
+		t.Fatalf("resolve project dir: %v", err)
+	}
+	if !filepath.IsAbs(payload.Path) || resolved != want {
+		t.Fatalf("expected absolute path %q, got %q", projectDir, payload.Path)
+	}
 }
 
-func TestAssetsScan_InvalidPath_ReturnsRuntimeError(t *testing.T) {
+func TestAssetsScan_InvalidPath_ReturnsPathNotFoundError(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 
@@ -155,11 +231,55 @@ func TestAssetsScan_InvalidPath_ReturnsRuntimeError(t *testing.T) {
 	if !ok {
 		t.Fatalf("missing error object: %v", payload)
 	}
-	if errVal["code"] != "runtime_error" {
+	if errVal["code"] != "path_not_found" {
 		t.Fatalf("unexpected error code: %v", errVal["code"])
 	}
 }
 
+func TestAssetsScan_JSONErrorsFalse_WritesPlainTextError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	invalidPath := filepath.Join(t.TempDir(), "xcwrap-path-that-should-not-exist")
+	exitCode := Execute([]string{"--json-errors=false", "assets", "scan", "--path", invalidPath}, &stdout, &stderr)
+	if exitCode != 1 {
+		t.Fatalf("expected exit code 1, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+	if got, want := stderr.String(), "error: path does not exist or is inaccessible: "+invalidPath+"\n"; got != want {
+		t.Fatalf("expected plain-text error %q, got %q", want, got)
+	}
+}
+
+func TestAssetsScan_JSONErrorsEnvFalse_WritesPlainTextUsageError(t *testing.T) {
+	t.Setenv("XCWRAP_JSON_ERRORS", "false")
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := Execute([]string{"assets", "scan", "--workers", "0"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if got, want := stderr.String(), "error: invalid value for --workers: must be >= 1\n"; got != want {
+		t.Fatalf("expected plain-text error %q, got %q", want, got)
+	}
+}
+
+func TestAssetsScan_JSONErrorsInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := Execute([]string{"--json-errors=maybe", "assets", "scan"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), `"usage_error"`) {
+		t.Fatalf("expected JSON usage error, got %s", stderr.String())
+	}
+}
+
 func TestAssetsUnused_ReturnsExitCode3WhenUnusedFound(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
@@ -269,709 +389,3420 @@ func TestAssetsScan_TableOutput_IsNotJSON(t *testing.T) {
 	}
 }
 
-func TestAssetsScan_DefaultExcludesExternalLibraries(t *testing.T) {
+func TestAssetsUnused_CSVOutputEmitsOneRowPerUnusedAsset(t *testing.T) {
 	root := t.TempDir()
-	podAssetDir := filepath.Join(root, "Pods", "SomeLib", "Assets.xcassets", "ic_unassigned_2_28.imageset")
-	if err := os.MkdirAll(podAssetDir, 0o755); err != nil {
-		t.Fatalf("mkdir pod asset set: %v", err)
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"used.imageset", "unused.imageset", "brand,primary.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
-	if exitCode != 0 {
-		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	exitCode := Execute([]string{"--output", "csv", "assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v", err)
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatalf("expected CSV output, got err: %v", err)
 	}
-	summary, ok := payload["summary"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing summary payload: %#v", payload)
+	if len(records) == 0 || strings.Join(records[0], ",") != "catalog,asset,assetType" {
+		t.Fatalf("unexpected csv header: %#v", records)
 	}
-	if summary["assetSets"] != float64(0) {
-		t.Fatalf("expected assetSets=0 when only Pods assets exist, got %v", summary["assetSets"])
+	rows := records[1:]
+	if len(rows) != 2 {
+		t.Fatalf("expected row count to equal unused count 2, got %#v", rows)
+	}
+	if rows[0][0] != catalog || rows[0][1] != "brand,primary" || rows[0][2] != "colorset" {
+		t.Fatalf("unexpected first csv row: %#v", rows[0])
+	}
+	if rows[1][1] != "unused" || rows[1][2] != "imageset" {
+		t.Fatalf("unexpected second csv row: %#v", rows[1])
 	}
 }
 
-func TestAssetsScan_ExcludeFlag_AllowsAdditionalExcludes(t *testing.T) {
+func TestAssets_MaxResultsTruncatesListsButKeepsCounts(t *testing.T) {
 	root := t.TempDir()
-	assetDir := filepath.Join(root, "ExternalLib", "Assets.xcassets", "externalIcon.imageset")
-	if err := os.MkdirAll(assetDir, 0o755); err != nil {
-		t.Fatalf("mkdir external asset set: %v", err)
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"a.imageset", "b.imageset", "c.imageset", "d.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "scan", "--path", root, "--exclude", "ExternalLib/"}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "unused", "--path", root, "--max-results", "2"}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var unused struct {
+		UnusedCount  int                         `json:"unusedCount"`
+		Unused       []string                    `json:"unused"`
+		UnusedByFile map[string]unusedFileResult `json:"unusedByFile"`
+		Truncated    bool                        `json:"truncated"`
+		TotalUnused  *int                        `json:"totalUnused"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &unused); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if unused.UnusedCount != 4 || !unused.Truncated || unused.TotalUnused == nil || *unused.TotalUnused != 4 {
+		t.Fatalf("expected unusedCount 4, totalUnused 4 and truncated, got %+v", unused)
+	}
+	if !slices.Equal(unused.Unused, []string{"a", "b"}) {
+		t.Fatalf("expected first two unused names, got %#v", unused.Unused)
+	}
+	if got := unused.UnusedByFile[catalog].UnusedAssets; !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("expected first two unusedByFile names, got %#v", got)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "scan", "--path", root, "--with-unused", "--max-results", "3"}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
+	var scan struct {
+		Summary struct {
+			UnusedAssets int `json:"unusedAssets"`
+		} `json:"summary"`
+		UnusedByFile map[string]unusedFileResult `json:"unusedByFile"`
+		Truncated    bool                        `json:"truncated"`
+		TotalUnused  *int                        `json:"totalUnused"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &scan); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if scan.Summary.UnusedAssets != 4 || !scan.Truncated || len(scan.UnusedByFile[catalog].UnusedAssets) != 3 || scan.TotalUnused == nil || *scan.TotalUnused != 4 {
+		t.Fatalf("expected 3 of 4 unused assets listed, totalUnused 4 and truncated, got %+v", scan)
+	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "prune", "--path", root, "--max-results", "1"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var prune struct {
+		Deleted     []string `json:"deleted"`
+		Truncated   bool     `json:"truncated"`
+		TotalUnused *int     `json:"totalUnused"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &prune); err != nil {
 		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	summary, ok := payload["summary"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing summary payload: %#v", payload)
+	if len(prune.Deleted) != 1 || !prune.Truncated || prune.TotalUnused == nil || *prune.TotalUnused != 4 {
+		t.Fatalf("expected 1 deleted path, totalUnused 4 and truncated, got %+v", prune)
 	}
-	if summary["assetSets"] != float64(0) {
-		t.Fatalf("expected assetSets=0 with explicit --exclude, got %v", summary["assetSets"])
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--max-results", "4"}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), `"truncated"`) || !strings.Contains(stdout.String(), `"totalUnused":4`) {
+		t.Fatalf("expected totalUnused but no truncated key when every asset is listed, got %s", stdout.String())
+	}
+
+	for _, args := range [][]string{
+		{"assets", "unused", "--path", root, "--max-results", "-1"},
+		{"assets", "scan", "--path", root, "--max-results", "2"},
+	} {
+		stdout.Reset()
+		stderr.Reset()
+		if exitCode := Execute(args, &stdout, &stderr); exitCode != 2 {
+			t.Fatalf("expected usage exit code 2 for %v, got %d, stderr=%s", args, exitCode, stderr.String())
+		}
 	}
 }
 
-func TestAssetsScan_ExcludeFlag_CommaSeparatedValuesExcludeMultiplePaths(t *testing.T) {
+func TestAssetsScan_EmitAssetNamesMatchesScannerAssetNames(t *testing.T) {
 	root := t.TempDir()
-	externalAssetDir := filepath.Join(root, "ExternalLib", "Assets.xcassets", "externalIcon.imageset")
-	vendorAssetDir := filepath.Join(root, "vendor", "SomeLib", "Assets.xcassets", "vendorIcon.imageset")
-	if err := os.MkdirAll(externalAssetDir, 0o755); err != nil {
-		t.Fatalf("mkdir external asset set: %v", err)
-	}
-	if err := os.MkdirAll(vendorAssetDir, 0o755); err != nil {
-		t.Fatalf("mkdir vendor asset set: %v", err)
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"logo.imageset", "logo.colorset", "hero.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "scan", "--path", root, "--exclude", "ExternalLib/,vendor/"}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--emit-asset-names"}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v", err)
+	var names []string
+	if err := json.Unmarshal(stdout.Bytes(), &names); err != nil {
+		t.Fatalf("expected JSON string array, got err: %v, stdout=%s", err, stdout.String())
 	}
-	summary, ok := payload["summary"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing summary payload: %#v", payload)
+	expected := []string{"hero", "logo.colorset", "logo.imageset"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected names %#v, got %#v", expected, names)
 	}
-	if summary["assetSets"] != float64(0) {
-		t.Fatalf("expected assetSets=0 with comma-separated --exclude, got %v", summary["assetSets"])
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"--output", "table", "assets", "scan", "--path", root, "--emit-asset-names"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stdout.String() != strings.Join(expected, "\n")+"\n" {
+		t.Fatalf("expected newline-separated names, got %q", stdout.String())
 	}
 }
 
-func TestAssetsScan_OutputNormalizesIncludeExcludePatterns(t *testing.T) {
+func TestAssetsScan_EmitAssetNamesEmptyCatalogEmitsEmptyArray(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--emit-asset-names"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if strings.TrimSpace(stdout.String()) != "[]" {
+		t.Fatalf("expected empty JSON array, got %q", stdout.String())
+	}
+}
+
+func TestAssetsScan_ExplainListsReferenceSourceAndRule(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir asset set: %v", err)
+	for _, name := range []string{"hero.imageset", "spare.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
-	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
-		t.Fatalf("write source: %v", err)
+	source := filepath.Join(root, "HeroView.swift")
+	if err := os.WriteFile(source, []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute(
-		[]string{"assets", "scan", "--path", root, "--include", " Sources/** ", "--exclude", " Pods/** ,  vendor/** "},
-		&stdout,
-		&stderr,
-	)
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--explain", "hero"}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
-	}
 
-	var payload map[string]any
+	var payload explainResult
 	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v", err)
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
 	}
-
-	include, ok := payload["include"].([]any)
-	if !ok {
-		t.Fatalf("missing include payload: %#v", payload["include"])
+	if payload.Asset != "hero" || !payload.Used {
+		t.Fatalf("expected hero to be explained as used, got %#v", payload)
 	}
-	if len(include) != 1 || include[0] != "Sources/**" {
-		t.Fatalf("unexpected include payload: %#v", include)
+	expected := referenceResult{Source: source, Rule: "swift-image-named", Text: `UIImage(named: "hero"`}
+	if len(payload.References) != 1 || payload.References[0] != expected {
+		t.Fatalf("expected reference %#v, got %#v", expected, payload.References)
 	}
 
-	exclude, ok := payload["exclude"].([]any)
-	if !ok {
-		t.Fatalf("missing exclude payload: %#v", payload["exclude"])
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"--output", "table", "assets", "scan", "--path", root, "--explain", "spare"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if len(exclude) != 2 || exclude[0] != "Pods/**" || exclude[1] != "vendor/**" {
-		t.Fatalf("unexpected exclude payload: %#v", exclude)
+	if !strings.Contains(stdout.String(), "no references found") {
+		t.Fatalf("expected unused asset explanation, got %q", stdout.String())
 	}
 }
 
-func TestAssetsScan_InvalidIncludeGlobReturnsUsageError(t *testing.T) {
+func TestAssetsScan_ExplainUnknownAssetReturnsUsageError(t *testing.T) {
 	root := t.TempDir()
-	catalog := filepath.Join(root, "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
 		t.Fatalf("mkdir asset set: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "scan", "--path", root, "--include", "["}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--explain", "missing"}, &stdout, &stderr)
 	if exitCode != 2 {
 		t.Fatalf("expected exit code 2, got %d", exitCode)
 	}
 	if stdout.Len() != 0 {
 		t.Fatalf("expected empty stdout, got %s", stdout.String())
 	}
+	if !strings.Contains(stderr.String(), `asset \"missing\" not found`) {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsScan_ListEmptyReportsContentsOnlyAssetSets(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"placeholder.imageset", "hero.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(catalog, name, "Contents.json"), []byte("{}"), 0o644); err != nil {
+			t.Fatalf("write contents: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(catalog, "hero.imageset", "hero.png"), []byte("png"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
 
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
 	var payload map[string]any
-	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	errVal, ok := payload["error"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing error object: %v", payload)
+	summary, ok := payload["summary"].(map[string]any)
+	if !ok || summary["emptyAssetSets"] != float64(1) {
+		t.Fatalf("expected emptyAssetSets=1, got %#v", payload["summary"])
 	}
-	if errVal["code"] != "usage_error" {
-		t.Fatalf("unexpected error code: %v", errVal["code"])
+	if _, ok := payload["emptyAssetSets"]; ok {
+		t.Fatalf("expected emptyAssetSets list to be omitted without --list-empty")
 	}
-	message, _ := errVal["message"].(string)
-	if !strings.Contains(message, "invalid value for --include") {
-		t.Fatalf("expected include validation message, got: %q", message)
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "scan", "--path", root, "--list-empty"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var listed scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &listed); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	expected := filepath.Join(catalog, "placeholder.imageset")
+	if len(listed.EmptyAssetSets) != 1 || listed.EmptyAssetSets[0] != expected {
+		t.Fatalf("expected %q to be listed as empty, got %#v", expected, listed.EmptyAssetSets)
 	}
 }
 
-func TestAssetsScan_InvalidExcludeGlobReturnsUsageError(t *testing.T) {
+func TestAssetsScan_WarningsAsErrorsFlipsExitCode(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
+	placeholder := filepath.Join(catalog, "placeholder.imageset")
+	if err := os.MkdirAll(placeholder, 0o755); err != nil {
 		t.Fatalf("mkdir asset set: %v", err)
 	}
+	if err := os.WriteFile(filepath.Join(placeholder, "Contents.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write contents: %v", err)
+	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "scan", "--path", root, "--exclude", "["}, &stdout, &stderr)
-	if exitCode != 2 {
-		t.Fatalf("expected exit code 2, got %d", exitCode)
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--list-empty"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stdout.Len() != 0 {
-		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	var payload scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	expected := []scanWarningResult{{Code: "empty-asset-set", Message: "asset set contains only Contents.json", Path: placeholder}}
+	if !slices.Equal(payload.Warnings, expected) {
+		t.Fatalf("expected warnings %#v, got %#v", expected, payload.Warnings)
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "scan", "--path", root, "--list-empty", "--warnings-as-errors"}, &stdout, &stderr)
+	if exitCode != 8 {
+		t.Fatalf("expected exit code 8, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	errVal, ok := payload["error"].(map[string]any)
+	if !strings.Contains(stdout.String(), `"warnings"`) {
+		t.Fatalf("expected warnings in output, got %s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "scan", "--path", root, "--warnings-as-errors"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 without enabled checks, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssets_DurationMsOmittedUnderDeterministic(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	for _, command := range []string{"scan", "unused", "prune"} {
+		for _, deterministic := range []bool{false, true} {
+			args := []string{"assets", command, "--path", root}
+			if deterministic {
+				args = append(args, "--deterministic")
+			}
+			var stdout bytes.Buffer
+			var stderr bytes.Buffer
+			if exitCode := Execute(args, &stdout, &stderr); exitCode != 0 && exitCode != 3 {
+				t.Fatalf("%v: unexpected exit code %d, stderr=%s", args, exitCode, stderr.String())
+			}
+			var payload map[string]any
+			if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+				t.Fatalf("%v: expected JSON output, got err: %v", args, err)
+			}
+			durationMs, ok := payload["durationMs"]
+			if deterministic && ok {
+				t.Fatalf("%v: expected durationMs to be omitted, got %#v", args, durationMs)
+			}
+			if !deterministic {
+				if value, isNumber := durationMs.(float64); !ok || !isNumber || value < 0 {
+					t.Fatalf("%v: expected non-negative durationMs, got %#v", args, durationMs)
+				}
+			}
+		}
+	}
+}
+
+func TestAssetsScan_WithUnusedEmbedsUnusedByFileDetail(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"used.imageset", "stale.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if _, ok := payload["unusedByFile"]; ok {
+		t.Fatalf("expected unusedByFile to be omitted without --with-unused")
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "scan", "--path", root, "--with-unused"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with unused assets, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var scanned scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &scanned); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if scanned.Summary.UnusedAssets != 1 {
+		t.Fatalf("expected summary to count one unused asset, got %#v", scanned.Summary)
+	}
+
+	var unusedOut bytes.Buffer
+	Execute([]string{"assets", "unused", "--path", root}, &unusedOut, &stderr)
+	var unused unusedResult
+	if err := json.Unmarshal(unusedOut.Bytes(), &unused); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if !reflect.DeepEqual(scanned.UnusedByFile, unused.UnusedByFile) {
+		t.Fatalf("expected scan detail %#v to match unused %#v", scanned.UnusedByFile, unused.UnusedByFile)
+	}
+}
+
+func TestAssetsScan_WithUnusedInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--with-unused=garbage"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsScan_AssetTypeBreakdownSumsToSummary(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, dir := range []string{"hero.imageset", "banner.imageset", "stale.imageset", "brand.colorset", "muted.colorset", "config.dataset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let hero = UIImage(named: "hero")
+let banner = UIImage(named: "banner")
+let brand = UIColor(named: "brand")`
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var payload scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	expected := map[string]assetTypeCountResult{
+		"imageset": {AssetSets: 3, UsedAssets: 2, UnusedAssets: 1},
+		"colorset": {AssetSets: 2, UsedAssets: 1, UnusedAssets: 1},
+		"dataset":  {AssetSets: 1, UsedAssets: 0, UnusedAssets: 1},
+	}
+	if !maps.Equal(payload.AssetTypeBreakdown, expected) {
+		t.Fatalf("expected breakdown %#v, got %#v", expected, payload.AssetTypeBreakdown)
+	}
+	var total assetTypeCountResult
+	for _, counts := range payload.AssetTypeBreakdown {
+		total.AssetSets += counts.AssetSets
+		total.UsedAssets += counts.UsedAssets
+		total.UnusedAssets += counts.UnusedAssets
+	}
+	if total.AssetSets != payload.Summary.AssetSets || total.UsedAssets != payload.Summary.UsedAssets || total.UnusedAssets != payload.Summary.UnusedAssets {
+		t.Fatalf("expected breakdown totals %#v to match summary %#v", total, payload.Summary)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"--output", "table", "assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "\nAsset Types\n") || !regexp.MustCompile(`(?m)^imageset\s+3\s+2\s+1$`).MatchString(stdout.String()) {
+		t.Fatalf("expected asset type rows in table output, got %s", stdout.String())
+	}
+}
+
+func TestAssetsScan_GroupByModuleAggregatesPerPackage(t *testing.T) {
+	root := t.TempDir()
+	for _, module := range []string{"Packages/Feed", "Packages/Profile"} {
+		if err := os.MkdirAll(filepath.Join(root, module), 0o755); err != nil {
+			t.Fatalf("mkdir module: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, module, "Package.swift"), []byte("// swift-tools-version:5.9\n"), 0o644); err != nil {
+			t.Fatalf("write manifest: %v", err)
+		}
+	}
+	assetSets := []string{
+		"Packages/Feed/Sources/Feed/Resources/Feed.xcassets/feedHeader.imageset",
+		"Packages/Feed/Sources/Feed/Resources/Feed.xcassets/feedStale.imageset",
+		"Packages/Feed/Sources/Feed/Other.xcassets/feedBadge.imageset",
+		"Packages/Profile/Sources/Profile/Profile.xcassets/avatar.imageset",
+		"Packages/Profile/Sources/Profile/Profile.xcassets/profileStale.imageset",
+		"Packages/Profile/Sources/Profile/Profile.xcassets/coverStale.imageset",
+		"App/Assets.xcassets/appIcon.imageset",
+	}
+	for _, path := range assetSets {
+		if err := os.MkdirAll(filepath.Join(root, path), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `let a = UIImage(named: "feedHeader")
+let b = UIImage(named: "feedBadge")
+let c = UIImage(named: "avatar")
+let d = UIImage(named: "appIcon")`
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--group-by-module"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var scanned scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &scanned); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	want := []moduleResult{
+		{Path: ".", AssetSets: 1, UsedAssets: 1, UnusedAssets: 0},
+		{Path: "Packages/Feed", AssetSets: 3, UsedAssets: 2, UnusedAssets: 1},
+		{Path: "Packages/Profile", AssetSets: 3, UsedAssets: 1, UnusedAssets: 2},
+	}
+	if !reflect.DeepEqual(scanned.Modules, want) {
+		t.Fatalf("expected modules %#v, got %#v", want, scanned.Modules)
+	}
+
+	stdout.Reset()
+	Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if _, ok := payload["modules"]; ok {
+		t.Fatalf("expected modules to be omitted without --group-by-module")
+	}
+}
+
+func TestAssetsScan_GroupByModuleInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--group-by-module=garbage"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsScan_ReportRuleStatsAddsRuleMatchCounts(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--report-rule-stats"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var scanned scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &scanned); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if scanned.RuleMatchCounts["swift-image-named"] != 1 {
+		t.Fatalf("expected one swift-image-named match, got %#v", scanned.RuleMatchCounts)
+	}
+
+	stdout.Reset()
+	Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "ruleMatchCounts") {
+		t.Fatalf("expected ruleMatchCounts to be omitted without --report-rule-stats, got %s", stdout.String())
+	}
+}
+
+func TestAssetsScan_ExplainUnusedListsSearchedCandidates(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "tab-bar-home.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--explain-unused"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var scanned scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &scanned); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if len(scanned.UnusedExplained) != 1 {
+		t.Fatalf("expected one unused explanation, got %#v", scanned.UnusedExplained)
+	}
+	explanation := scanned.UnusedExplained[0]
+	if explanation.Name != "tab-bar-home" || !slices.Contains(explanation.SearchedCandidates, "tabBarHome") {
+		t.Fatalf("unexpected unused explanation: %#v", explanation)
+	}
+}
+
+func TestAssetsScan_FailIfUsedBelowGatesOnUsedCount(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "logo.imageset", "stale.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let a = UIImage(named: "hero")
+let b = UIImage(named: "logo")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	for _, tc := range []struct {
+		threshold string
+		exitCode  int
+	}{
+		{threshold: "2", exitCode: 0},
+		{threshold: "3", exitCode: 6},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute([]string{"assets", "scan", "--path", root, "--fail-if-used-below", tc.threshold}, &stdout, &stderr)
+		if exitCode != tc.exitCode {
+			t.Fatalf("threshold %s: expected exit code %d, got %d, stderr=%s", tc.threshold, tc.exitCode, exitCode, stderr.String())
+		}
+		var result scanResult
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("threshold %s: expected JSON report, got err: %v", tc.threshold, err)
+		}
+		if result.Summary.UsedAssets != 2 {
+			t.Fatalf("threshold %s: expected usedAssets=2, got %#v", tc.threshold, result.Summary)
+		}
+	}
+}
+
+func TestAssetsScan_FailIfUsedBelowInvalidValue_IsUsageError(t *testing.T) {
+	for _, value := range []string{"-1", "many"} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--fail-if-used-below", value}, &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("value %s: expected exit code 2, got %d", value, exitCode)
+		}
+	}
+}
+
+func TestAssetsScan_CatalogsOnlyReportsCatalogLevelData(t *testing.T) {
+	root := t.TempDir()
+	appCatalog := filepath.Join(root, "App", "Assets.xcassets")
+	emptyCatalog := filepath.Join(root, "Widgets", "Colors.xcassets")
+	for _, name := range []string{"hero.imageset", "brand.colorset"} {
+		if err := os.MkdirAll(filepath.Join(appCatalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	if err := os.MkdirAll(emptyCatalog, 0o755); err != nil {
+		t.Fatalf("mkdir empty catalog: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--catalogs-only"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	for _, key := range []string{"summary", "include", "exclude", "workers"} {
+		if _, ok := payload[key]; ok {
+			t.Fatalf("expected only catalog-level data, found %q in %#v", key, payload)
+		}
+	}
+	var result catalogsResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("decode catalogs result: %v", err)
+	}
+	expected := []catalogResult{
+		{Path: appCatalog, AssetSets: 2},
+		{Path: emptyCatalog, AssetSets: 0},
+	}
+	if !slices.Equal(result.Catalogs, expected) {
+		t.Fatalf("expected catalogs %#v, got %#v", expected, result.Catalogs)
+	}
+}
+
+func TestAssetsScan_CatalogsOnlyRejectsAssetLevelFlags(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--catalogs-only", "--emit-asset-names"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "--catalogs-only cannot be combined with --emit-asset-names") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsScan_ProfileWritesTimingsToStderrOnly(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--profile"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected stdout to stay valid JSON, got err: %v, stdout=%s", err, stdout.String())
+	}
+	if payload.Summary.UsedAssets != 1 {
+		t.Fatalf("unexpected summary: %#v", payload.Summary)
+	}
+	profile := stderr.String()
+	for _, section := range []string{"Profile", "Catalog Discovery:", "Label Collection:", "1 Swift files", "Usage Detection:", "1 source files", "Total:"} {
+		if !strings.Contains(profile, section) {
+			t.Fatalf("expected %q in profile output, got %q", section, profile)
+		}
+	}
+}
+
+func TestAssetsScan_FailOnEmptyCatalogListsCatalogsWithoutAssetSets(t *testing.T) {
+	root := t.TempDir()
+	populated := filepath.Join(root, "App", "Assets.xcassets")
+	empty := filepath.Join(root, "Legacy", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(populated, "logo.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.MkdirAll(empty, 0o755); err != nil {
+		t.Fatalf("mkdir empty catalog: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(empty, "Contents.json"), []byte(`{"info":{"version":1,"author":"xcode"}}`), 0o644); err != nil {
+		t.Fatalf("write catalog contents: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--fail-on-empty-catalog"}, &stdout, &stderr)
+	if exitCode != 5 {
+		t.Fatalf("expected exit code 5, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if payload.Summary.AssetCatalogs != 2 {
+		t.Fatalf("expected both catalogs to be discovered, got %d", payload.Summary.AssetCatalogs)
+	}
+	if !slices.Equal(payload.EmptyCatalogs, []string{empty}) {
+		t.Fatalf("expected only %s to be flagged, got %v", empty, payload.EmptyCatalogs)
+	}
+}
+
+func TestAssetsScan_FailOnEmptyCatalogInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--fail-on-empty-catalog=maybe"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "usage_error") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsScan_WideTableAddsCountsAndCatalogBreakdown(t *testing.T) {
+	root := t.TempDir()
+	appCatalog := filepath.Join(root, "App", "Assets.xcassets")
+	kitCatalog := filepath.Join(root, "Kit", "Assets.xcassets")
+	for _, dir := range []string{filepath.Join(appCatalog, "logo.imageset"), filepath.Join(kitCatalog, "logo.imageset"), filepath.Join(root, "Legacy", "Assets.xcassets")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+
+	var narrow bytes.Buffer
+	var stderr bytes.Buffer
+	if exitCode := Execute([]string{"--output", "table", "assets", "scan", "--path", root}, &narrow, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if strings.Contains(narrow.String(), "duplicate_names") {
+		t.Fatalf("expected default table to stay narrow, got:\n%s", narrow.String())
+	}
+
+	var wide bytes.Buffer
+	if exitCode := Execute([]string{"--output", "table", "assets", "scan", "--path", root, "--wide"}, &wide, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	lines := strings.Split(wide.String(), "\n")
+	if fields := strings.Fields(lines[0]); !slices.Equal(fields[len(fields)-2:], []string{"duplicate_names", "empty_catalogs"}) {
+		t.Fatalf("expected wide headers, got %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); !slices.Equal(fields[len(fields)-2:], []string{"1", "1"}) {
+		t.Fatalf("expected one duplicate name and one empty catalog, got %q", lines[1])
+	}
+	for _, catalog := range []string{appCatalog, kitCatalog} {
+		if !strings.Contains(wide.String(), catalog) {
+			t.Fatalf("expected catalog breakdown to list %s, got:\n%s", catalog, wide.String())
+		}
+	}
+}
+
+func TestAssetsScan_WideWithoutTableOutput_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--wide"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "--wide requires --output table") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsScan_IncludeHiddenDiscoversDotDirectoryCatalogs(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".generated", "Generated.xcassets", "badge.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		catalogs int
+	}{
+		{args: []string{"assets", "scan", "--path", root}, catalogs: 0},
+		{args: []string{"assets", "scan", "--path", root, "--include-hidden"}, catalogs: 1},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := Execute(tc.args, &stdout, &stderr); exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d, stderr=%s", tc.args, exitCode, stderr.String())
+		}
+		var payload scanResult
+		if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+			t.Fatalf("%v: expected JSON output, got err: %v", tc.args, err)
+		}
+		if payload.Summary.AssetCatalogs != tc.catalogs {
+			t.Fatalf("%v: expected %d catalogs, got %d", tc.args, tc.catalogs, payload.Summary.AssetCatalogs)
+		}
+	}
+}
+
+func TestAssetsScan_IncludeHiddenInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--include-hidden=all"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsScan_IncludeBundlesDiscoversBundleCatalogs(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Vendor", "Widgets.bundle", "Assets.xcassets", "spinner.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		catalogs int
+	}{
+		{args: []string{"assets", "scan", "--path", root}, catalogs: 0},
+		{args: []string{"assets", "scan", "--path", root, "--include-bundles"}, catalogs: 1},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := Execute(tc.args, &stdout, &stderr); exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d, stderr=%s", tc.args, exitCode, stderr.String())
+		}
+		var payload scanResult
+		if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+			t.Fatalf("%v: expected JSON output, got err: %v", tc.args, err)
+		}
+		if payload.Summary.AssetCatalogs != tc.catalogs {
+			t.Fatalf("%v: expected %d catalogs, got %d", tc.args, tc.catalogs, payload.Summary.AssetCatalogs)
+		}
+	}
+}
+
+func TestAssetsScan_IncludeBundlesInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--include-bundles=all"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsUnused_ExcludeGeneratedRevealsMaskedAssets(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"hero.imageset", "legacy.imageset"} {
+		if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	generated := `enum R { static let hero = UIImage(named: "hero"); static let legacy = UIImage(named: "legacy") }`
+	if err := os.WriteFile(filepath.Join(root, "R.generated.swift"), []byte(generated), 0o644); err != nil {
+		t.Fatalf("write generated source: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = R.hero`), 0o644); err != nil {
+		t.Fatalf("write usage: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected generated names to mask unused assets, got exit code %d, stdout=%s", exitCode, stdout.String())
+	}
+
+	stdout.Reset()
+	exitCode := Execute([]string{"assets", "unused", "--path", root, "--exclude-generated", "*.generated.swift"}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var result unusedResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if !reflect.DeepEqual(result.Unused, []string{"legacy"}) {
+		t.Fatalf("expected legacy to be revealed as unused, got %#v", result.Unused)
+	}
+}
+
+func TestAssetsUnused_ExcludeGeneratedInvalidGlob_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--exclude-generated", "Generated/[.swift"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "--exclude-generated") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsScan_AssetsFromInvalidGlob_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--assets-from", "App/[.xcassets"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "--assets-from") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsScan_MaxDepthLimitsCatalogDiscovery(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "top.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir top asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "Modules", "Feature", "Assets.xcassets", "nested.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir nested asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--max-depth", "1"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	summary, ok := payload["summary"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing summary payload: %#v", payload)
+	}
+	if summary["assetCatalogs"] != float64(1) || summary["assetSets"] != float64(1) {
+		t.Fatalf("expected only the top-level catalog with --max-depth 1, got %#v", summary)
+	}
+}
+
+func TestAssetsScan_NegativeMaxDepthReturnsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", t.TempDir(), "--max-depth", "-1"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	}
+	errVal, ok := payload["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing error object: %v", payload)
+	}
+	message, _ := errVal["message"].(string)
+	if errVal["code"] != "usage_error" || !strings.Contains(message, "invalid value for --max-depth") {
+		t.Fatalf("unexpected error payload: %#v", errVal)
+	}
+}
+
+func TestAssetsScan_DefaultExcludesExternalLibraries(t *testing.T) {
+	root := t.TempDir()
+	podAssetDir := filepath.Join(root, "Pods", "SomeLib", "Assets.xcassets", "ic_unassigned_2_28.imageset")
+	if err := os.MkdirAll(podAssetDir, 0o755); err != nil {
+		t.Fatalf("mkdir pod asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	summary, ok := payload["summary"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing summary payload: %#v", payload)
+	}
+	if summary["assetSets"] != float64(0) {
+		t.Fatalf("expected assetSets=0 when only Pods assets exist, got %v", summary["assetSets"])
+	}
+}
+
+func TestAssetsScan_ExcludeFlag_AllowsAdditionalExcludes(t *testing.T) {
+	root := t.TempDir()
+	assetDir := filepath.Join(root, "ExternalLib", "Assets.xcassets", "externalIcon.imageset")
+	if err := os.MkdirAll(assetDir, 0o755); err != nil {
+		t.Fatalf("mkdir external asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--exclude", "ExternalLib/"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	summary, ok := payload["summary"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing summary payload: %#v", payload)
+	}
+	if summary["assetSets"] != float64(0) {
+		t.Fatalf("expected assetSets=0 with explicit --exclude, got %v", summary["assetSets"])
+	}
+}
+
+func TestAssetsScan_ExcludeFlag_CommaSeparatedValuesExcludeMultiplePaths(t *testing.T) {
+	root := t.TempDir()
+	externalAssetDir := filepath.Join(root, "ExternalLib", "Assets.xcassets", "externalIcon.imageset")
+	vendorAssetDir := filepath.Join(root, "vendor", "SomeLib", "Assets.xcassets", "vendorIcon.imageset")
+	if err := os.MkdirAll(externalAssetDir, 0o755); err != nil {
+		t.Fatalf("mkdir external asset set: %v", err)
+	}
+	if err := os.MkdirAll(vendorAssetDir, 0o755); err != nil {
+		t.Fatalf("mkdir vendor asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--exclude", "ExternalLib/,vendor/"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	summary, ok := payload["summary"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing summary payload: %#v", payload)
+	}
+	if summary["assetSets"] != float64(0) {
+		t.Fatalf("expected assetSets=0 with comma-separated --exclude, got %v", summary["assetSets"])
+	}
+}
+
+func TestAssetsScan_CacheDirStoresOneEntryAndMatchesUncachedOutput(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "App", "Assets.xcassets", "stale.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	outputs := make([]string, 0, 3)
+	for _, args := range [][]string{nil, {"--cache-dir", cacheDir}, {"--cache-dir", cacheDir}} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "scan", "--path", root, "--with-unused", "--deterministic"}, args...), &stdout, &stderr)
+		if exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d, stderr=%s", args, exitCode, stderr.String())
+		}
+		outputs = append(outputs, stdout.String())
+	}
+	if outputs[0] != outputs[1] || outputs[0] != outputs[2] {
+		t.Fatalf("expected identical output with --cache-dir:\n%s\n%s\n%s", outputs[0], outputs[1], outputs[2])
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		t.Fatalf("read cache dir: %v", err)
+	}
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".json" {
+		t.Fatalf("expected one cached scan entry, got %v", entries)
+	}
+}
+
+func TestAssetsScan_CacheKeyRejectsUnknownModesAndMissingCacheDir(t *testing.T) {
+	root := t.TempDir()
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--cache-dir", t.TempDir(), "--cache-key", "ctime"}, want: "invalid value for --cache-key"},
+		{args: []string{"--cache-key", "git"}, want: "--cache-key requires --cache-dir"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "scan", "--path", root}, tc.args...), &stdout, &stderr)
+		if exitCode != 2 || !strings.Contains(stderr.String(), tc.want) {
+			t.Fatalf("%v: expected usage error %q, got exit %d, stderr=%s", tc.args, tc.want, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestAssetsScan_OutputNormalizesIncludeExcludePatterns(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute(
+		[]string{"assets", "scan", "--path", root, "--include", " Sources/** ", "--exclude", " Pods/** ,  vendor/** "},
+		&stdout,
+		&stderr,
+	)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+
+	include, ok := payload["include"].([]any)
+	if !ok {
+		t.Fatalf("missing include payload: %#v", payload["include"])
+	}
+	if len(include) != 1 || include[0] != "Sources/**" {
+		t.Fatalf("unexpected include payload: %#v", include)
+	}
+
+	exclude, ok := payload["exclude"].([]any)
+	if !ok {
+		t.Fatalf("missing exclude payload: %#v", payload["exclude"])
+	}
+	if len(exclude) != 2 || exclude[0] != "Pods/**" || exclude[1] != "vendor/**" {
+		t.Fatalf("unexpected exclude payload: %#v", exclude)
+	}
+}
+
+func TestAssetsScan_ReportsEffectiveExclude(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "used.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{args: nil, want: []string{".build/", "Carthage/", "Pods/", "SourcePackages/", "vendor/"}},
+		{args: []string{"--exclude", "./Pods/,/Legacy/**,Pods/"}, want: []string{"Legacy/**", "Pods/"}},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "scan", "--path", root}, tc.args...), &stdout, &stderr)
+		if exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d, stderr=%s", tc.args, exitCode, stderr.String())
+		}
+		var result scanResult
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("%v: expected JSON output, got err: %v", tc.args, err)
+		}
+		if !slices.Equal(result.EffectiveExclude, tc.want) {
+			t.Fatalf("%v: expected effectiveExclude %#v, got %#v", tc.args, tc.want, result.EffectiveExclude)
+		}
+	}
+}
+
+func TestAssetsScan_ParallelCatalogsMatchesSerialOutput(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(root, "App", "Assets.xcassets", "used.imageset"),
+		filepath.Join(root, "App", "Assets.xcassets", "stale.imageset"),
+		filepath.Join(root, "Widgets", "Widget.xcassets", "used.imageset"),
+		filepath.Join(root, "Root.xcassets", "tint.colorset"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "App", "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	outputs := make([]string, 0, 2)
+	for _, args := range [][]string{nil, {"--parallel-catalogs"}} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "scan", "--path", root, "--with-unused", "--deterministic"}, args...), &stdout, &stderr)
+		if exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d, stderr=%s", args, exitCode, stderr.String())
+		}
+		outputs = append(outputs, stdout.String())
+	}
+	if outputs[0] != outputs[1] {
+		t.Fatalf("expected identical output with --parallel-catalogs:\n%s\n%s", outputs[0], outputs[1])
+	}
+}
+
+func TestAssets_JSONOutputIsIdenticalAcrossWorkerCounts(t *testing.T) {
+	root := t.TempDir()
+	var source strings.Builder
+	for i := range 40 {
+		module := fmt.Sprintf("Module%02d", i%7)
+		catalog := filepath.Join(root, module, "Assets.xcassets")
+		for _, dir := range []string{
+			fmt.Sprintf("icon_%02d.imageset", i),
+			fmt.Sprintf("stale_%02d.imageset", i),
+			fmt.Sprintf("tint_%02d.colorset", i%5),
+			"shared.imageset",
+		} {
+			if err := os.MkdirAll(filepath.Join(catalog, dir), 0o755); err != nil {
+				t.Fatalf("mkdir asset set: %v", err)
+			}
+		}
+		fmt.Fprintf(&source, "let image%d = UIImage(named: \"icon_%02d\")\nlet color%d = UIColor(named: \"stale_%02d\")\n", i, i, i, i)
+		if err := os.WriteFile(filepath.Join(root, module, fmt.Sprintf("View%02d.swift", i)), []byte(source.String()), 0o644); err != nil {
+			t.Fatalf("write swift source: %v", err)
+		}
+	}
+	// The scan payload echoes --workers, the one field expected to differ.
+	workersRe := regexp.MustCompile(`"workers":\d+`)
+
+	workerVariants := [][]string{{"--workers", "1"}, {"--workers", "2"}, {"--workers", "8"}}
+	for _, tc := range []struct {
+		args     []string
+		variants [][]string
+	}{
+		{
+			args:     []string{"assets", "scan", "--with-unused", "--warn-duplicate-names", "--warn-type-mismatch", "--list-empty", "--group-by-module", "--report-rule-stats", "--explain-unused"},
+			variants: [][]string{{"--workers", "1", "--include", "Module0*,Module*"}, {"--workers", "2", "--include", "Module*,Module0*"}, {"--workers", "8", "--include", "Module*", "--include", "Module0*"}},
+		},
+		{
+			args:     []string{"assets", "unused", "--group-by", "type", "--report-test-only", "--report-preview-only"},
+			variants: workerVariants,
+		},
+		{
+			args:     []string{"assets", "unused"},
+			variants: [][]string{{"--workers", "1", "--exclude", "Pods,DerivedData"}, {"--workers", "8", "--exclude", "DerivedData", "--exclude", "Pods"}},
+		},
+		{
+			// prune always scans with the default worker count.
+			args:     []string{"assets", "prune"},
+			variants: [][]string{{"--type", "imageset,colorset"}, {"--type", "colorset", "--type", "imageset"}},
+		},
+	} {
+		var outputs []string
+		for _, variant := range tc.variants {
+			var stdout bytes.Buffer
+			var stderr bytes.Buffer
+			args := append([]string{"--deterministic"}, tc.args...)
+			exitCode := Execute(append(append(args, "--path", root), variant...), &stdout, &stderr)
+			if exitCode != 0 && exitCode != 3 {
+				t.Fatalf("%v %v: unexpected exit code %d, stderr=%s", tc.args, variant, exitCode, stderr.String())
+			}
+			outputs = append(outputs, workersRe.ReplaceAllString(stdout.String(), `"workers":0`))
+		}
+		for i, output := range outputs[1:] {
+			if output != outputs[0] {
+				t.Fatalf("%v: expected identical JSON for %v and %v:\n%s\n%s", tc.args, tc.variants[0], tc.variants[i+1], outputs[0], output)
+			}
+		}
+	}
+}
+
+func TestAssetsScan_InvalidIncludeGlobReturnsUsageError(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--include", "["}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	}
+	errVal, ok := payload["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing error object: %v", payload)
+	}
+	if errVal["code"] != "usage_error" {
+		t.Fatalf("unexpected error code: %v", errVal["code"])
+	}
+	message, _ := errVal["message"].(string)
+	if !strings.Contains(message, "invalid value for --include") {
+		t.Fatalf("expected include validation message, got: %q", message)
+	}
+}
+
+func TestAssetsScan_InvalidExcludeGlobReturnsUsageError(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--exclude", "["}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	}
+	errVal, ok := payload["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing error object: %v", payload)
+	}
+	if errVal["code"] != "usage_error" {
+		t.Fatalf("unexpected error code: %v", errVal["code"])
+	}
+	message, _ := errVal["message"].(string)
+	if !strings.Contains(message, "invalid value for --exclude") {
+		t.Fatalf("expected exclude validation message, got: %q", message)
+	}
+}
+
+func TestAssetsScan_ReadErrorReturnsReadFailureError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission model differs on windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("permission test is unreliable when running as root")
+	}
+
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source file: %v", err)
+	}
+
+	unreadable := filepath.Join(root, "Unreadable.swift")
+	if err := os.WriteFile(unreadable, []byte("let value = 1"), 0o644); err != nil {
+		t.Fatalf("write unreadable file: %v", err)
+	}
+	if err := os.Chmod(unreadable, 0o000); err != nil {
+		t.Fatalf("chmod unreadable file: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chmod(unreadable, 0o644)
+	})
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	done := make(chan int, 1)
+	go func() {
+		done <- Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	}()
+
+	timeout := 10 * time.Second
+	if testing.Short() {
+		timeout = 3 * time.Second
+	}
+
+	select {
+	case exitCode := <-done:
+		if exitCode != 1 {
+			t.Fatalf("expected exit code 1, got %d", exitCode)
+		}
+	case <-time.After(timeout):
+		t.Fatal("execute deadlocked after file read error")
+	}
+
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	}
+	errVal, ok := payload["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing error object: %v", payload)
+	}
+	if errVal["code"] != "read_failure" {
+		t.Fatalf("unexpected error code: %v", errVal["code"])
+	}
+}
+
+func TestAssetsUnused_GroupByTypeAndDirectory(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "brand.colorset", filepath.Join("Icons", "star.imageset")} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	cases := []struct {
+		groupBy  string
+		expected map[string][]string
+	}{
+		{
+			groupBy: "type",
+			expected: map[string][]string{
+				"colorset": {"brand"},
+				"imageset": {"hero", "star"},
+			},
+		},
+		{
+			groupBy: "directory",
+			expected: map[string][]string{
+				catalog:                         {"brand", "hero"},
+				filepath.Join(catalog, "Icons"): {"star"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute([]string{"assets", "unused", "--path", root, "--group-by", tc.groupBy}, &stdout, &stderr)
+		if exitCode != 3 {
+			t.Fatalf("expected exit code 3 for --group-by %s, got %d, stderr=%s", tc.groupBy, exitCode, stderr.String())
+		}
+
+		var payload unusedResult
+		if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+			t.Fatalf("expected JSON output, got err: %v", err)
+		}
+		if payload.GroupBy != tc.groupBy {
+			t.Fatalf("expected groupBy=%s, got %q", tc.groupBy, payload.GroupBy)
+		}
+		if len(payload.UnusedByGroup) != len(tc.expected) {
+			t.Fatalf("expected groups %#v, got %#v", tc.expected, payload.UnusedByGroup)
+		}
+		for group, names := range tc.expected {
+			got := payload.UnusedByGroup[group].UnusedAssets
+			if strings.Join(got, ",") != strings.Join(names, ",") {
+				t.Fatalf("expected %s group %q to contain %#v, got %#v", tc.groupBy, group, names, got)
+			}
+		}
+		if len(payload.UnusedByFile[catalog].UnusedAssets) != 3 {
+			t.Fatalf("expected unusedByFile to stay grouped by catalog, got %#v", payload.UnusedByFile)
+		}
+	}
+}
+
+func TestAssetsUnused_InvalidGroupByReturnsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--group-by", "module"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "invalid value for --group-by") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestAssetsUnused_DuplicateNamesAcrossCatalogs(t *testing.T) {
+	root := t.TempDir()
+
+	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
+	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module a asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b asset: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Modules", "ModuleA", "Feature.swift"), []byte(`let _ = UIImage(named: "icon")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	unused, ok := payload["unused"].([]any)
+	if !ok || len(unused) != 1 || unused[0] != "icon" {
+		t.Fatalf("unexpected unused payload: %#v", payload["unused"])
+	}
+	grouped, ok := payload["unusedByFile"].(map[string]any)
+	if !ok || len(grouped) != 1 {
+		t.Fatalf("expected one grouped unused catalog, got %#v", payload["unusedByFile"])
+	}
+	if _, exists := grouped[moduleBCatalog]; !exists {
+		t.Fatalf("expected grouped key %s, got %#v", moduleBCatalog, grouped)
+	}
+}
+
+func TestAssetsUnused_JSONGroupingDistinctCatalogsWithSameBasename(t *testing.T) {
+	root := t.TempDir()
+
+	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
+	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(moduleACatalog, "a.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module a asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "b.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b asset: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	grouped, ok := payload["unusedByFile"].(map[string]any)
+	if !ok || len(grouped) != 2 {
+		t.Fatalf("expected two grouped unused catalogs, got %#v", payload["unusedByFile"])
+	}
+	if _, exists := grouped[moduleACatalog]; !exists {
+		t.Fatalf("expected grouped key %s, got %#v", moduleACatalog, grouped)
+	}
+	if _, exists := grouped[moduleBCatalog]; !exists {
+		t.Fatalf("expected grouped key %s, got %#v", moduleBCatalog, grouped)
+	}
+}
+
+func TestAssetsUnused_ExplicitCountsWhenPruneCandidatesExceedUnusedNames(t *testing.T) {
+	root := t.TempDir()
+
+	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
+	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module a asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b asset: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	if payload["unusedCount"] != float64(1) {
+		t.Fatalf("expected unusedCount=1, got %v", payload["unusedCount"])
+	}
+	if payload["pruneCandidateCount"] != float64(2) {
+		t.Fatalf("expected pruneCandidateCount=2, got %v", payload["pruneCandidateCount"])
+	}
+}
+
+func TestAssetsUnused_InvalidExcludeGlobReturnsUsageError(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "unused.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root, "--exclude", "["}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	}
+	errVal, ok := payload["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing error object: %v", payload)
+	}
+	if errVal["code"] != "usage_error" {
+		t.Fatalf("unexpected error code: %v", errVal["code"])
+	}
+	message, _ := errVal["message"].(string)
+	if !strings.Contains(message, "invalid value for --exclude") {
+		t.Fatalf("expected exclude validation message, got: %q", message)
+	}
+}
+
+func TestAssetsUnused_ExcludeFlag_CommaSeparatedValuesExcludeMultiplePaths(t *testing.T) {
+	root := t.TempDir()
+	externalAssetDir := filepath.Join(root, "ExternalLib", "Assets.xcassets", "externalIcon.imageset")
+	vendorAssetDir := filepath.Join(root, "vendor", "SomeLib", "Assets.xcassets", "vendorIcon.imageset")
+	if err := os.MkdirAll(externalAssetDir, 0o755); err != nil {
+		t.Fatalf("mkdir external asset set: %v", err)
+	}
+	if err := os.MkdirAll(vendorAssetDir, 0o755); err != nil {
+		t.Fatalf("mkdir vendor asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root, "--exclude", "ExternalLib/,vendor/"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	if payload["unusedCount"] != float64(0) {
+		t.Fatalf("expected unusedCount=0 with comma-separated --exclude, got %v", payload["unusedCount"])
+	}
+	if payload["pruneCandidateCount"] != float64(0) {
+		t.Fatalf("expected pruneCandidateCount=0 with comma-separated --exclude, got %v", payload["pruneCandidateCount"])
+	}
+}
+
+func TestAssetsUnused_UnusedByFilePreservesTypeDistinctNames(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "logo.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir image asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(catalog, "logo.colorset"), 0o755); err != nil {
+		t.Fatalf("mkdir color asset set: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	grouped, ok := payload["unusedByFile"].(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected unusedByFile payload: %#v", payload["unusedByFile"])
+	}
+	rawEntry, ok := grouped[catalog]
+	if !ok {
+		t.Fatalf("expected catalog %q in unusedByFile payload, got %#v", catalog, grouped)
+	}
+	entry, ok := rawEntry.(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected entry type for catalog %s: %#v", catalog, rawEntry)
+	}
+	unusedAssets, ok := entry["unusedAssets"].([]any)
+	if !ok {
+		t.Fatalf("unexpected unusedAssets payload: %#v", entry["unusedAssets"])
+	}
+	if len(unusedAssets) != 2 || unusedAssets[0] != "logo.colorset" || unusedAssets[1] != "logo.imageset" {
+		t.Fatalf("expected type-distinct unusedAssets, got %#v", unusedAssets)
+	}
+}
+
+func TestAssetsScan_WarnTypeMismatchReportsAndExitsNonZero(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Theme.swift"), []byte(`let tint = Color("hero")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("expected exit code 0 without --warn-type-mismatch, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--warn-type-mismatch"}, &stdout, &stderr)
+	if exitCode != 7 {
+		t.Fatalf("expected exit code 7, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var scanned scanResult
+	if err := json.Unmarshal(stdout.Bytes(), &scanned); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if len(scanned.TypeMismatches) != 1 {
+		t.Fatalf("expected one type mismatch, got %#v", scanned.TypeMismatches)
+	}
+	mismatch := scanned.TypeMismatches[0]
+	if mismatch.Name != "hero" || mismatch.RequestedType != "colorset" || !slices.Equal(mismatch.ExistingTypes, []string{"imageset"}) {
+		t.Fatalf("unexpected type mismatch: %#v", mismatch)
+	}
+}
+
+func TestAssetsScan_WarnDuplicateNamesReportsCollisionsAndExitsNonZero(t *testing.T) {
+	root := t.TempDir()
+
+	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
+	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module a asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.colorset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b color asset: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root, "--warn-duplicate-names"}, &stdout, &stderr)
+	if exitCode != 4 {
+		t.Fatalf("expected exit code 4, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	duplicates, ok := payload["duplicateNames"].([]any)
+	if !ok || len(duplicates) != 1 {
+		t.Fatalf("expected one duplicate name entry, got %#v", payload["duplicateNames"])
+	}
+	entry, ok := duplicates[0].(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected duplicate entry type: %#v", duplicates[0])
+	}
+	if entry["name"] != "icon" || entry["assetType"] != "imageset" {
+		t.Fatalf("unexpected duplicate entry: %#v", entry)
+	}
+	catalogs, ok := entry["catalogs"].([]any)
+	if !ok || len(catalogs) != 2 || catalogs[0] != moduleACatalog || catalogs[1] != moduleBCatalog {
+		t.Fatalf("unexpected duplicate catalogs: %#v", entry["catalogs"])
+	}
+}
+
+func TestAssetsScan_WarnCaseCollisionsReportsCollisionsAndExitsNonZero(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	// Hero sits in a group folder so the fixture also builds on
+	// case-insensitive file systems.
+	for _, dir := range []string{filepath.Join(catalog, "Heroes", "Hero.imageset"), filepath.Join(catalog, "hero.imageset")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 without the flag, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "caseCollisions") {
+		t.Fatalf("expected caseCollisions to be omitted without flag, got %s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "scan", "--path", root, "--warn-case-collisions"}, &stdout, &stderr)
+	if exitCode != 9 {
+		t.Fatalf("expected exit code 9, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var payload struct {
+		CaseCollisions []caseCollisionResult `json:"caseCollisions"`
+		Warnings       []scanWarningResult   `json:"warnings"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	if len(payload.CaseCollisions) != 1 || payload.CaseCollisions[0].Catalog != catalog || !slices.Equal(payload.CaseCollisions[0].Names, []string{"Hero", "hero"}) {
+		t.Fatalf("unexpected case collisions: %#v", payload.CaseCollisions)
+	}
+	if len(payload.Warnings) != 1 || payload.Warnings[0].Code != "case-collision" || payload.Warnings[0].Path != catalog {
+		t.Fatalf("expected one case-collision warning, got %#v", payload.Warnings)
+	}
+}
+
+func TestAssetsScan_WithoutWarnDuplicateNamesOmitsCollisions(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "A", "Assets.xcassets", "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module a asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "B", "Assets.xcassets", "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b asset: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if _, exists := payload["duplicateNames"]; exists {
+		t.Fatalf("expected duplicateNames to be omitted without flag, got %#v", payload["duplicateNames"])
+	}
+}
+
+func TestAssetsUnused_ReportTestOnlyListsTestScopedAssetsSeparately(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir used asset set: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(catalog, "fixture.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir test asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "hero")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "AppTests"), 0o755); err != nil {
+		t.Fatalf("mkdir tests dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "AppTests", "Fixtures.swift"), []byte(`let _ = UIImage(named: "fixture")`), 0o644); err != nil {
+		t.Fatalf("write test source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root, "--report-test-only"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if payload["unusedCount"] != float64(0) {
+		t.Fatalf("expected unusedCount=0, got %v", payload["unusedCount"])
+	}
+	testOnly, ok := payload["usedOnlyInTests"].([]any)
+	if !ok || len(testOnly) != 1 || testOnly[0] != "fixture" {
+		t.Fatalf("unexpected usedOnlyInTests payload: %#v", payload["usedOnlyInTests"])
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "usedOnlyInTests") {
+		t.Fatalf("expected usedOnlyInTests to be omitted without flag, got %s", stdout.String())
+	}
+}
+
+func TestAssetsUnused_ReportPreviewOnlyListsPreviewScopedAssetsSeparately(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"hero.imageset", "sample.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
+	}
+	source := `struct HeroView: View {
+    var body: some View { Image("hero") }
+}
+
+#Preview("Sample") {
+    Image("sample")
+}`
+	if err := os.WriteFile(filepath.Join(root, "HeroView.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root, "--report-preview-only"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var result unusedResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if result.UnusedCount != 0 || !slices.Equal(result.UsedOnlyInPreviews, []string{"sample"}) {
+		t.Fatalf("unexpected preview-only report: %#v", result)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "usedOnlyInPreviews") {
+		t.Fatalf("expected usedOnlyInPreviews to be omitted without flag, got %s", stdout.String())
+	}
+}
+
+func TestAssetsUnused_DynamicNamesFlagMarksInterpolatedFamilyUsed(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(catalog, "home_selected.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "\(tab)_selected")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 without --dynamic-names, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--dynamic-names"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --dynamic-names, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_DynamicNamesFlagRejectsValue(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--dynamic-names=maybe"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	}
+}
+
+func TestAssetsUnused_ScanBundleResourceFlagResolvesForResourceLookups(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	source := `let url = Bundle.main.url(forResource: "hero", withExtension: "png")`
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--scan-bundle-resource"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --scan-bundle-resource, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanDocCFlagResolvesDocumentationImages(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte(`@Image(source: "hero", alt: "Hero")`), 0o644); err != nil {
+		t.Fatalf("write doc: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--scan-docc"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --scan-docc, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanLocalizedKeysFlagResolvesLocalizedKeys(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let name = String(localized: "hero")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--scan-localized-keys"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --scan-localized-keys, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanLocalizedKeysInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--scan-localized-keys=sometimes"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsUnused_ResolveLocalizedNamesFlagResolvesImageNameKeys(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "home_icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let icon = UIImage(named: NSLocalizedString("home_icon", comment: ""))`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--resolve-localized-names"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --resolve-localized-names, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanAttributedMarkdownFlagResolvesMarkdownImages(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let text = try AttributedString(markdown: "![Hero](hero)")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--scan-attributed-markdown"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --scan-attributed-markdown, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanDefaultsFlagResolvesAppStorageDefaults(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "classic_icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Settings.swift"), []byte(`@AppStorage("iconStyle") var style = "classic_icon"`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--scan-defaults"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --scan-defaults, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanDefaultsInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--scan-defaults=often"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsUnused_ScanHTMLFlagResolvesImageSources(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "hero.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "onboarding.html"), []byte(`<img src="hero.png">`), 0o644); err != nil {
+		t.Fatalf("write html: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3 by default, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "unused", "--path", root, "--scan-html"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0 with --scan-html, got %d, stderr=%s", exitCode, stderr.String())
+	}
+}
+
+func TestAssetsUnused_ScanHTMLInvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--scan-html=yes-please"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+}
+
+func TestAssetsUnused_ScanKeyPathsResolvesConfiguredAccessors(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "home.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let icon = DS.glyph(\.home)`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		exitCode int
+	}{
+		{args: []string{"assets", "unused", "--path", root}, exitCode: 3},
+		{args: []string{"assets", "unused", "--path", root, "--scan-keypaths"}, exitCode: 3},
+		{args: []string{"assets", "unused", "--path", root, "--scan-keypaths", "--keypath-accessor", "glyph"}, exitCode: 0},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := Execute(tc.args, &stdout, &stderr); exitCode != tc.exitCode {
+			t.Fatalf("%v: expected exit code %d, got %d, stderr=%s", tc.args, tc.exitCode, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_KeyPathAccessorInvalidValue_IsUsageError(t *testing.T) {
+	for _, args := range [][]string{
+		{"--scan-keypaths", "--keypath-accessor", "icons[0]"},
+		{"--keypath-accessor", "icons"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "unused", "--path", t.TempDir()}, args...), &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("%v: expected exit code 2, got %d", args, exitCode)
+		}
+		if !strings.Contains(stderr.String(), "--keypath-accessor") {
+			t.Fatalf("%v: unexpected stderr: %s", args, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_ObjCColorSelectorsMarksWrapperArgumentsUsed(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "brand.colorset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	source := `UIColor *tint = [MyTheme colorNamed:@"brand"];`
+	if err := os.WriteFile(filepath.Join(root, "Theme.m"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		exitCode int
+	}{
+		{args: []string{"assets", "unused", "--path", root}, exitCode: 3},
+		{args: []string{"assets", "unused", "--path", root, "--objc-color-selectors", "themeColor:"}, exitCode: 3},
+		{args: []string{"assets", "unused", "--path", root, "--objc-color-selectors", "colorNamed:"}, exitCode: 0},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := Execute(tc.args, &stdout, &stderr); exitCode != tc.exitCode {
+			t.Fatalf("%v: expected exit code %d, got %d, stderr=%s", tc.args, tc.exitCode, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_ObjCColorSelectorsInvalidValue_IsUsageError(t *testing.T) {
+	for _, selector := range []string{"colorNamed:bundle:", "color Named:", ":"} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--objc-color-selectors", selector}, &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("%q: expected exit code 2, got %d", selector, exitCode)
+		}
+		if !strings.Contains(stderr.String(), "--objc-color-selectors") {
+			t.Fatalf("%q: unexpected stderr: %s", selector, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_IBAttrMarksCustomAttributeValuesUsed(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "badgeIcon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	xib := `<document><objects><view customClass="BadgeView" iconName="badgeIcon" id="v"/></objects></document>`
+	if err := os.WriteFile(filepath.Join(root, "Badge.xib"), []byte(xib), 0o644); err != nil {
+		t.Fatalf("write xib: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		exitCode int
+	}{
+		{args: []string{"assets", "unused", "--path", root}, exitCode: 3},
+		{args: []string{"assets", "unused", "--path", root, "--ib-attr", "iconName=colorset"}, exitCode: 3},
+		{args: []string{"assets", "unused", "--path", root, "--ib-attr", "iconName=imageset"}, exitCode: 0},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := Execute(tc.args, &stdout, &stderr); exitCode != tc.exitCode {
+			t.Fatalf("%v: expected exit code %d, got %d, stderr=%s", tc.args, tc.exitCode, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_IBAttrInvalidValue_IsUsageError(t *testing.T) {
+	for _, args := range [][]string{
+		{"--ib-attr", "iconName"},
+		{"--ib-attr", "=imageset"},
+		{"--ib-attr", "iconName=picture"},
+		{"--ib-attr", "icon name=imageset"},
+		{"--ib-attr", "iconName=imageset", "--ib-attr", "iconName=colorset"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "unused", "--path", t.TempDir()}, args...), &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("%v: expected exit code 2, got %d", args, exitCode)
+		}
+		if !strings.Contains(stderr.String(), "--ib-attr") {
+			t.Fatalf("%v: unexpected stderr: %s", args, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_ActiveConfigIgnoresDebugOnlyReferences(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "debugMenu.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	source := "#if DEBUG\nlet icon = UIImage(named: \"debugMenu\")\n#endif\n"
+	if err := os.WriteFile(filepath.Join(root, "DebugMenu.swift"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		exitCode int
+	}{
+		{args: []string{"assets", "unused", "--path", root}, exitCode: 0},
+		{args: []string{"assets", "unused", "--path", root, "--active-config", "DEBUG"}, exitCode: 0},
+		{args: []string{"assets", "unused", "--path", root, "--active-config", "RELEASE"}, exitCode: 3},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := Execute(tc.args, &stdout, &stderr); exitCode != tc.exitCode {
+			t.Fatalf("%v: expected exit code %d, got %d, stderr=%s", tc.args, tc.exitCode, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestAssetsUnused_ActiveConfigInvalidValue_IsUsageError(t *testing.T) {
+	for _, value := range []string{"", "DEBUG || RELEASE", "os(iOS)", "true"} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute([]string{"assets", "unused", "--path", t.TempDir(), "--active-config", value}, &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("%q: expected exit code 2, got %d", value, exitCode)
+		}
+		if !strings.Contains(stderr.String(), "--active-config") {
+			t.Fatalf("%q: unexpected stderr: %s", value, stderr.String())
+		}
+	}
+}
+
+func TestAssetsPrune_DryRunReportsCandidatesWithoutDeleting(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	usedPath := filepath.Join(catalog, "used.imageset")
+	unusedPath := filepath.Join(catalog, "unused.imageset")
+	if err := os.MkdirAll(usedPath, 0o755); err != nil {
+		t.Fatalf("mkdir used asset set: %v", err)
+	}
+	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
+		t.Fatalf("mkdir unused asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if payload["dryRun"] != true || payload["appliedCount"] != float64(0) {
+		t.Fatalf("expected dryRun=true and appliedCount=0, got %v and %v", payload["dryRun"], payload["appliedCount"])
+	}
+	if payload["unusedCount"] != float64(1) {
+		t.Fatalf("expected unusedCount=1, got %v", payload["unusedCount"])
+	}
+	if payload["pruneCandidateCount"] != float64(1) {
+		t.Fatalf("expected pruneCandidateCount=1, got %v", payload["pruneCandidateCount"])
+	}
+	deleted, ok := payload["deleted"].([]any)
+	if !ok || len(deleted) != 1 || deleted[0] != unusedPath {
+		t.Fatalf("unexpected deleted payload: %#v", payload["deleted"])
+	}
+
+	if _, err := os.Stat(unusedPath); err != nil {
+		t.Fatalf("expected dry-run to keep %s, stat err=%v", unusedPath, err)
+	}
+}
+
+func TestAssetsPrune_ApplyDeletesUnreferencedAppIconSet(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	primaryIcon := filepath.Join(catalog, "AppIcon.appiconset")
+	legacyIcon := filepath.Join(catalog, "AppIcon-Legacy.appiconset")
+	for _, dir := range []string{primaryIcon, legacyIcon} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir app icon set: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "Contents.json"), []byte(`{}`), 0o644); err != nil {
+			t.Fatalf("write contents: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "App.xcconfig"), []byte("ASSETCATALOG_COMPILER_APPICON_NAME = AppIcon\n"), 0o644); err != nil {
+		t.Fatalf("write xcconfig: %v", err)
+	}
+	initCleanGitRepo(t, root)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	deleted, ok := payload["deleted"].([]any)
+	if !ok || len(deleted) != 1 || deleted[0] != legacyIcon {
+		t.Fatalf("unexpected deleted payload: %#v", payload["deleted"])
+	}
+	if _, err := os.Stat(primaryIcon); err != nil {
+		t.Fatalf("expected primary icon to remain, stat err=%v", err)
+	}
+	if _, err := os.Stat(legacyIcon); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be deleted, stat err=%v", legacyIcon, err)
+	}
+}
+
+func TestAssetsPrune_ExplicitCountsWhenPruneCandidatesExceedUnusedNames(t *testing.T) {
+	root := t.TempDir()
+
+	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
+	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
+	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module a asset: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir module b asset: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	}
+	if payload["unusedCount"] != float64(1) {
+		t.Fatalf("expected unusedCount=1, got %v", payload["unusedCount"])
+	}
+	if payload["pruneCandidateCount"] != float64(2) {
+		t.Fatalf("expected pruneCandidateCount=2, got %v", payload["pruneCandidateCount"])
+	}
+}
+
+func TestAssetsPrune_ApplyDeletesUnusedAssetsWhenGitTreeClean(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	usedPath := filepath.Join(catalog, "used.imageset")
+	unusedA := filepath.Join(catalog, "a.imageset")
+	unusedB := filepath.Join(catalog, "z.colorset")
+	if err := os.MkdirAll(usedPath, 0o755); err != nil {
+		t.Fatalf("mkdir used asset set: %v", err)
+	}
+	if err := os.MkdirAll(unusedA, 0o755); err != nil {
+		t.Fatalf("mkdir unused asset set A: %v", err)
+	}
+	if err := os.MkdirAll(unusedB, 0o755); err != nil {
+		t.Fatalf("mkdir unused asset set B: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	initCleanGitRepo(t, root)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	deleted, ok := payload["deleted"].([]any)
+	if !ok || len(deleted) != 2 {
+		t.Fatalf("unexpected deleted payload: %#v", payload["deleted"])
+	}
+	if deleted[0] != unusedA || deleted[1] != unusedB {
+		t.Fatalf("expected deterministic sorted deleted payload [%s %s], got %#v", unusedA, unusedB, deleted)
+	}
+	if payload["dryRun"] != false || payload["appliedCount"] != float64(2) {
+		t.Fatalf("expected dryRun=false and appliedCount=2, got %v and %v", payload["dryRun"], payload["appliedCount"])
+	}
+	if _, err := os.Stat(usedPath); err != nil {
+		t.Fatalf("expected used asset set to remain, stat err=%v", err)
+	}
+	if _, err := os.Stat(unusedA); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be deleted, stat err=%v", unusedA, err)
+	}
+	if _, err := os.Stat(unusedB); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be deleted, stat err=%v", unusedB, err)
+	}
+}
+
+func TestAssetsPrune_ApplyWithoutCandidatesReportsZeroAppliedCount(t *testing.T) {
+	root := t.TempDir()
+	usedPath := filepath.Join(root, "Assets.xcassets", "used.imageset")
+	if err := os.MkdirAll(usedPath, 0o755); err != nil {
+		t.Fatalf("mkdir used asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	initCleanGitRepo(t, root)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if payload["dryRun"] != false || payload["apply"] != true {
+		t.Fatalf("expected an applied run, got dryRun=%v apply=%v", payload["dryRun"], payload["apply"])
+	}
+	if payload["appliedCount"] != float64(0) || payload["pruneCandidateCount"] != float64(0) {
+		t.Fatalf("expected appliedCount=0 and pruneCandidateCount=0, got %v and %v", payload["appliedCount"], payload["pruneCandidateCount"])
+	}
+	if deleted, ok := payload["deleted"].([]any); !ok || len(deleted) != 0 {
+		t.Fatalf("expected empty deleted array, got %#v", payload["deleted"])
+	}
+	if _, err := os.Stat(usedPath); err != nil {
+		t.Fatalf("expected used asset set to remain, stat err=%v", err)
+	}
+}
+
+func TestAssetsPrune_ApplyRequiresCleanGitTreeAndReportsPlan(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	unusedPath := filepath.Join(catalog, "unused.imageset")
+	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
+		t.Fatalf("mkdir unused asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(unusedPath, "Contents.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("write contents: %v", err)
+	}
+	initCleanGitRepo(t, root)
+	if err := os.WriteFile(filepath.Join(root, "dirty.txt"), []byte("dirty"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
+	if exitCode != 1 {
+		t.Fatalf("expected exit code 1, got %d", exitCode)
+	}
+
+	var report map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON prune plan on stdout, got err: %v, stdout=%s", err, stdout.String())
+	}
+	if report["dryRun"] != true {
+		t.Fatalf("expected dryRun=true for rejected apply, got %v", report["dryRun"])
+	}
+	candidates, ok := report["deleted"].([]any)
+	if !ok || len(candidates) != 1 || candidates[0] != unusedPath {
+		t.Fatalf("expected prune candidates in stdout, got %#v", report["deleted"])
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	}
+	errVal, ok := payload["error"].(map[string]any)
 	if !ok {
 		t.Fatalf("missing error object: %v", payload)
 	}
-	if errVal["code"] != "usage_error" {
+	if errVal["code"] != "git_dirty" {
 		t.Fatalf("unexpected error code: %v", errVal["code"])
 	}
 	message, _ := errVal["message"].(string)
-	if !strings.Contains(message, "invalid value for --exclude") {
-		t.Fatalf("expected exclude validation message, got: %q", message)
+	if !strings.Contains(message, "git working tree is not clean") {
+		t.Fatalf("unexpected error message: %q", message)
+	}
+	if _, err := os.Stat(unusedPath); err != nil {
+		t.Fatalf("expected apply rejection to keep %s, stat err=%v", unusedPath, err)
 	}
 }
 
-func TestAssetsScan_ReadErrorReturnsRuntimeError(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skip("permission model differs on windows")
+func TestAssetsPrune_ApplyForceOverridesDirtyGitTree(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	unusedPath := filepath.Join(catalog, "unused.imageset")
+	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
+		t.Fatalf("mkdir unused asset set: %v", err)
 	}
-	if os.Geteuid() == 0 {
-		t.Skip("permission test is unreliable when running as root")
+	if err := os.WriteFile(filepath.Join(unusedPath, "Contents.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("write contents: %v", err)
+	}
+	initCleanGitRepo(t, root)
+	if err := os.WriteFile(filepath.Join(root, "dirty.txt"), []byte("dirty"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	}
+	if _, err := os.Stat(unusedPath); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be deleted, stat err=%v", unusedPath, err)
 	}
+}
 
+func TestAssetsPrune_ApplyBackupDirMovesAssetSetsPreservingStructure(t *testing.T) {
 	root := t.TempDir()
-	catalog := filepath.Join(root, "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "used.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir asset set: %v", err)
+	catalog := filepath.Join(root, "Modules", "Feature", "Assets.xcassets")
+	unusedPath := filepath.Join(catalog, "Icons", "unused.imageset")
+	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
+		t.Fatalf("mkdir unused asset set: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(unusedPath, "Contents.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("write contents: %v", err)
 	}
+	initCleanGitRepo(t, root)
+	backupDir := filepath.Join(t.TempDir(), "backup")
 
-	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
-		t.Fatalf("write source file: %v", err)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--backup-dir", backupDir}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
 
-	unreadable := filepath.Join(root, "Unreadable.swift")
-	if err := os.WriteFile(unreadable, []byte("let value = 1"), 0o644); err != nil {
-		t.Fatalf("write unreadable file: %v", err)
+	var payload pruneResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	if err := os.Chmod(unreadable, 0o000); err != nil {
-		t.Fatalf("chmod unreadable file: %v", err)
+	if payload.BackupDir != backupDir {
+		t.Fatalf("expected backupDir %q, got %q", backupDir, payload.BackupDir)
 	}
-	t.Cleanup(func() {
-		_ = os.Chmod(unreadable, 0o644)
-	})
+	if _, err := os.Stat(unusedPath); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be moved out of the project, stat err=%v", unusedPath, err)
+	}
+	movedContents := filepath.Join(backupDir, "Modules", "Feature", "Assets.xcassets", "Icons", "unused.imageset", "Contents.json")
+	if _, err := os.Stat(movedContents); err != nil {
+		t.Fatalf("expected asset set to be preserved at %s, stat err=%v", movedContents, err)
+	}
+}
 
+func TestAssetsPrune_BackupDirRequiresApply(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	done := make(chan int, 1)
-	go func() {
-		done <- Execute([]string{"assets", "scan", "--path", root}, &stdout, &stderr)
-	}()
-
-	timeout := 10 * time.Second
-	if testing.Short() {
-		timeout = 3 * time.Second
+	exitCode := Execute([]string{"assets", "prune", "--path", t.TempDir(), "--backup-dir", t.TempDir()}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "--backup-dir requires --apply") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
 	}
+}
 
-	select {
-	case exitCode := <-done:
-		if exitCode != 1 {
-			t.Fatalf("expected exit code 1, got %d", exitCode)
+func TestAssetsPrune_ApplyTypeLimitsDeletionToSelectedAssetType(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	unusedImage := filepath.Join(catalog, "a.imageset")
+	unusedColor := filepath.Join(catalog, "z.colorset")
+	for _, dir := range []string{unusedImage, unusedColor} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
 		}
-	case <-time.After(timeout):
-		t.Fatal("execute deadlocked after file read error")
 	}
+	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte("let _ = 1"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	initCleanGitRepo(t, root)
 
-	if stdout.Len() != 0 {
-		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--type", "colorset"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	var payload pruneResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	errVal, ok := payload["error"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing error object: %v", payload)
+	if payload.UnusedCount != 2 {
+		t.Fatalf("expected unusedCount to report all unused assets, got %d", payload.UnusedCount)
 	}
-	if errVal["code"] != "runtime_error" {
-		t.Fatalf("unexpected error code: %v", errVal["code"])
+	if payload.PruneCandidateCount != 1 || !slices.Equal(payload.Deleted, []string{unusedColor}) {
+		t.Fatalf("expected only the colorset to be pruned, got count=%d deleted=%v", payload.PruneCandidateCount, payload.Deleted)
+	}
+	if !slices.Equal(payload.Types, []string{"colorset"}) {
+		t.Fatalf("expected types [colorset], got %v", payload.Types)
+	}
+	if _, err := os.Stat(unusedColor); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be deleted, stat err=%v", unusedColor, err)
+	}
+	if _, err := os.Stat(unusedImage); err != nil {
+		t.Fatalf("expected unused imageset to remain, stat err=%v", err)
 	}
 }
 
-func TestAssetsUnused_DuplicateNamesAcrossCatalogs(t *testing.T) {
-	root := t.TempDir()
+func TestAssetsPrune_InvalidType_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", t.TempDir(), "--type", "texture"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "invalid value for --type") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
 
-	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
-	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module a asset: %v", err)
+func TestAssetsUnused_TemplateOutputRendersCustomReport(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"used.imageset", "beta.imageset", "alpha.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
-	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module b asset: %v", err)
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
 	}
-	if err := os.WriteFile(filepath.Join(root, "Modules", "ModuleA", "Feature.swift"), []byte(`let _ = UIImage(named: "icon")`), 0o644); err != nil {
-		t.Fatalf("write source: %v", err)
+	templatePath := filepath.Join(t.TempDir(), "slack.tmpl")
+	if err := os.WriteFile(templatePath, []byte(`:warning: {{.UnusedCount}} unused assets, first: {{index .Unused 0}}`), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	exitCode := Execute([]string{"--output", "template", "--template-file", templatePath, "assets", "unused", "--path", root}, &stdout, &stderr)
 	if exitCode != 3 {
 		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	if got, want := stdout.String(), ":warning: 2 unused assets, first: alpha"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
 	}
+}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
-	}
-	unused, ok := payload["unused"].([]any)
-	if !ok || len(unused) != 1 || unused[0] != "icon" {
-		t.Fatalf("unexpected unused payload: %#v", payload["unused"])
-	}
-	grouped, ok := payload["unusedByFile"].(map[string]any)
-	if !ok || len(grouped) != 1 {
-		t.Fatalf("expected one grouped unused catalog, got %#v", payload["unusedByFile"])
+func TestAssetsScan_TemplateOutputWithoutTemplate_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"--output", "template", "assets", "scan", "--path", t.TempDir()}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
 	}
-	if _, exists := grouped[moduleBCatalog]; !exists {
-		t.Fatalf("expected grouped key %s, got %#v", moduleBCatalog, grouped)
+	if !strings.Contains(stderr.String(), "--output template requires --template or --template-file") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
 	}
 }
 
-func TestAssetsUnused_JSONGroupingDistinctCatalogsWithSameBasename(t *testing.T) {
+func TestAssetsPrune_ApplyGitAddStagesRemovals(t *testing.T) {
 	root := t.TempDir()
-
-	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
-	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(moduleACatalog, "a.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module a asset: %v", err)
+	catalog := filepath.Join(root, "Assets.xcassets")
+	unused := filepath.Join(catalog, "unused.imageset")
+	if err := os.MkdirAll(unused, 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
 	}
-	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "b.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module b asset: %v", err)
+	if err := os.WriteFile(filepath.Join(unused, "Contents.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("write asset contents: %v", err)
 	}
+	initCleanGitRepo(t, root)
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
-	if exitCode != 3 {
-		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
-	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--git-add"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-
-	var payload map[string]any
+	var payload pruneResult
 	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	grouped, ok := payload["unusedByFile"].(map[string]any)
-	if !ok || len(grouped) != 2 {
-		t.Fatalf("expected two grouped unused catalogs, got %#v", payload["unusedByFile"])
+	if !payload.Staged {
+		t.Fatalf("expected staged=true, got %s", stdout.String())
 	}
-	if _, exists := grouped[moduleACatalog]; !exists {
-		t.Fatalf("expected grouped key %s, got %#v", moduleACatalog, grouped)
+
+	cmd := exec.Command("git", "-C", root, "diff", "--cached", "--name-status")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git diff --cached: %v", err)
 	}
-	if _, exists := grouped[moduleBCatalog]; !exists {
-		t.Fatalf("expected grouped key %s, got %#v", moduleBCatalog, grouped)
+	if got, want := strings.TrimSpace(string(out)), "D\tAssets.xcassets/unused.imageset/Contents.json"; got != want {
+		t.Fatalf("expected staged deletion %q, got %q", want, got)
 	}
 }
 
-func TestAssetsUnused_ExplicitCountsWhenPruneCandidatesExceedUnusedNames(t *testing.T) {
+func TestAssetsPrune_GitAddOutsideGitWorkTreeWarns(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
 	root := t.TempDir()
+	unused := filepath.Join(root, "Assets.xcassets", "unused.imageset")
+	if err := os.MkdirAll(unused, 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
 
-	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
-	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module a asset: %v", err)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force", "--git-add"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module b asset: %v", err)
+	if !strings.Contains(stderr.String(), "--git-add ignored") {
+		t.Fatalf("expected --git-add warning, got stderr=%s", stderr.String())
+	}
+	if _, err := os.Stat(unused); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be deleted, stat err=%v", unused, err)
 	}
+}
 
+func TestAssetsPrune_GitAddRequiresApply(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
-	if exitCode != 3 {
-		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
+	exitCode := Execute([]string{"assets", "prune", "--path", t.TempDir(), "--git-add"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	if !strings.Contains(stderr.String(), "--git-add requires --apply") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
 	}
+}
 
-	var payload map[string]any
+func TestAssetsPrune_ApplyCommitCommitsOnlyRemovals(t *testing.T) {
+	root := t.TempDir()
+	unused := filepath.Join(root, "Assets.xcassets", "unused.imageset")
+	if err := os.MkdirAll(unused, 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
+	}
+	for _, name := range []string{"Contents.json", "unused.png"} {
+		if err := os.WriteFile(filepath.Join(unused, name), []byte(`{}`), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	notes := filepath.Join(root, "notes.txt")
+	if err := os.WriteFile(notes, []byte("v1\n"), 0o644); err != nil {
+		t.Fatalf("write notes: %v", err)
+	}
+	initCleanGitRepo(t, root)
+	// An unrelated staged change must stay out of the prune commit.
+	if err := os.WriteFile(notes, []byte("v2\n"), 0o644); err != nil {
+		t.Fatalf("update notes: %v", err)
+	}
+	runGit(t, root, "add", "notes.txt")
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force", "--commit", "Prune unused assets"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
+	}
+	var payload pruneResult
 	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	if payload["unusedCount"] != float64(1) {
-		t.Fatalf("expected unusedCount=1, got %v", payload["unusedCount"])
+	if !payload.Staged || payload.Commit == "" {
+		t.Fatalf("expected staged removals and a commit hash, got %s", stdout.String())
 	}
-	if payload["pruneCandidateCount"] != float64(2) {
-		t.Fatalf("expected pruneCandidateCount=2, got %v", payload["pruneCandidateCount"])
+
+	out, err := exec.Command("git", "-C", root, "show", "--name-status", "--format=%H %s", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git show: %v", err)
+	}
+	want := payload.Commit + " Prune unused assets\n\nD\tAssets.xcassets/unused.imageset/Contents.json\nD\tAssets.xcassets/unused.imageset/unused.png"
+	if got := strings.TrimSpace(string(out)); got != want {
+		t.Fatalf("expected commit %q, got %q", want, got)
+	}
+	out, err = exec.Command("git", "-C", root, "diff", "--cached", "--name-only").Output()
+	if err != nil {
+		t.Fatalf("git diff --cached: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "notes.txt" {
+		t.Fatalf("expected notes.txt to stay staged, got %q", got)
 	}
 }
 
-func TestAssetsUnused_InvalidExcludeGlobReturnsUsageError(t *testing.T) {
+func TestAssetsPrune_CommitOutsideGitWorkTreeFailsBeforeDeleting(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
 	root := t.TempDir()
-	catalog := filepath.Join(root, "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "unused.imageset"), 0o755); err != nil {
+	unused := filepath.Join(root, "Assets.xcassets", "unused.imageset")
+	if err := os.MkdirAll(unused, 0o755); err != nil {
 		t.Fatalf("mkdir asset set: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "unused", "--path", root, "--exclude", "["}, &stdout, &stderr)
-	if exitCode != 2 {
-		t.Fatalf("expected exit code 2, got %d", exitCode)
-	}
-	if stdout.Len() != 0 {
-		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force", "--commit", "Prune"}, &stdout, &stderr)
+	if exitCode != 1 {
+		t.Fatalf("expected exit code 1, got %d, stderr=%s", exitCode, stderr.String())
 	}
-
-	var payload map[string]any
-	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	if !strings.Contains(stderr.String(), "--commit requires") || !strings.Contains(stderr.String(), "inside a git work tree") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
 	}
-	errVal, ok := payload["error"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing error object: %v", payload)
+	if _, err := os.Stat(unused); err != nil {
+		t.Fatalf("expected %s to be kept, stat err=%v", unused, err)
 	}
-	if errVal["code"] != "usage_error" {
-		t.Fatalf("unexpected error code: %v", errVal["code"])
+}
+
+func TestAssetsPrune_CommitRequiresApply(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "prune", "--path", t.TempDir(), "--commit", "Prune"}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
 	}
-	message, _ := errVal["message"].(string)
-	if !strings.Contains(message, "invalid value for --exclude") {
-		t.Fatalf("expected exclude validation message, got: %q", message)
+	if !strings.Contains(stderr.String(), "--commit requires --apply") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
 	}
 }
 
-func TestAssetsUnused_ExcludeFlag_CommaSeparatedValuesExcludeMultiplePaths(t *testing.T) {
+func TestAssetsPrune_ApplyRemoveEmptyCatalogsRemovesEmptiedCatalog(t *testing.T) {
 	root := t.TempDir()
-	externalAssetDir := filepath.Join(root, "ExternalLib", "Assets.xcassets", "externalIcon.imageset")
-	vendorAssetDir := filepath.Join(root, "vendor", "SomeLib", "Assets.xcassets", "vendorIcon.imageset")
-	if err := os.MkdirAll(externalAssetDir, 0o755); err != nil {
-		t.Fatalf("mkdir external asset set: %v", err)
+	emptied := filepath.Join(root, "Legacy", "Legacy.xcassets")
+	kept := filepath.Join(root, "App", "Assets.xcassets")
+	untouched := filepath.Join(root, "Widgets", "Empty.xcassets")
+	for _, dir := range []string{
+		filepath.Join(emptied, "Icons", "stale.imageset"),
+		filepath.Join(kept, "used.imageset"),
+		filepath.Join(kept, "unused.imageset"),
+		untouched,
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
 	}
-	if err := os.MkdirAll(vendorAssetDir, 0o755); err != nil {
-		t.Fatalf("mkdir vendor asset set: %v", err)
+	for _, path := range []string{
+		filepath.Join(emptied, "Contents.json"),
+		filepath.Join(emptied, "Icons", "Contents.json"),
+		filepath.Join(untouched, "Contents.json"),
+	} {
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "unused", "--path", root, "--exclude", "ExternalLib/,vendor/"}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force", "--remove-empty-catalogs"}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	var result pruneResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	if !reflect.DeepEqual(result.RemovedCatalogs, []string{emptied}) {
+		t.Fatalf("expected only the emptied catalog to be removed, got %#v", result.RemovedCatalogs)
 	}
-	if payload["unusedCount"] != float64(0) {
-		t.Fatalf("expected unusedCount=0 with comma-separated --exclude, got %v", payload["unusedCount"])
+	if _, err := os.Stat(emptied); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, stat err=%v", emptied, err)
 	}
-	if payload["pruneCandidateCount"] != float64(0) {
-		t.Fatalf("expected pruneCandidateCount=0 with comma-separated --exclude, got %v", payload["pruneCandidateCount"])
+	if _, err := os.Stat(filepath.Join(kept, "used.imageset")); err != nil {
+		t.Fatalf("expected catalog with a used asset to be kept: %v", err)
+	}
+	if _, err := os.Stat(untouched); err != nil {
+		t.Fatalf("expected catalog untouched by the prune to be kept: %v", err)
 	}
 }
 
-func TestAssetsUnused_UnusedByFilePreservesTypeDistinctNames(t *testing.T) {
+func TestAssetsPrune_ApplyRemovesEmptiedNamespaceFolder(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(catalog, "logo.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir image asset set: %v", err)
+	emptied := filepath.Join(catalog, "Legacy")
+	kept := filepath.Join(catalog, "Icons")
+	for _, dir := range []string{
+		filepath.Join(emptied, "Old", "stale.imageset"),
+		filepath.Join(kept, "used.imageset"),
+		filepath.Join(kept, "unused.imageset"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
 	}
-	if err := os.MkdirAll(filepath.Join(catalog, "logo.colorset"), 0o755); err != nil {
-		t.Fatalf("mkdir color asset set: %v", err)
+	namespace := []byte(`{"info":{"author":"xcode","version":1},"properties":{"provides-namespace":true}}`)
+	for _, path := range []string{
+		filepath.Join(emptied, "Contents.json"),
+		filepath.Join(emptied, "Old", "Contents.json"),
+		filepath.Join(kept, "Contents.json"),
+	} {
+		if err := os.WriteFile(path, namespace, 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
-	if exitCode != 3 {
-		t.Fatalf("expected exit code 3, got %d, stderr=%s", exitCode, stderr.String())
-	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	var result pruneResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	grouped, ok := payload["unusedByFile"].(map[string]any)
-	if !ok {
-		t.Fatalf("unexpected unusedByFile payload: %#v", payload["unusedByFile"])
+	wantGroups := []string{filepath.Join(emptied, "Old"), emptied}
+	if !reflect.DeepEqual(result.RemovedGroups, wantGroups) {
+		t.Fatalf("expected emptied namespace folders %#v to be removed, got %#v", wantGroups, result.RemovedGroups)
 	}
-	rawEntry, ok := grouped[catalog]
-	if !ok {
-		t.Fatalf("expected catalog %q in unusedByFile payload, got %#v", catalog, grouped)
+	if _, err := os.Stat(emptied); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, stat err=%v", emptied, err)
 	}
-	entry, ok := rawEntry.(map[string]any)
-	if !ok {
-		t.Fatalf("unexpected entry type for catalog %s: %#v", catalog, rawEntry)
+	if _, err := os.Stat(filepath.Join(kept, "unused.imageset")); !os.IsNotExist(err) {
+		t.Fatalf("expected unused asset set to be pruned, stat err=%v", err)
 	}
-	unusedAssets, ok := entry["unusedAssets"].([]any)
-	if !ok {
-		t.Fatalf("unexpected unusedAssets payload: %#v", entry["unusedAssets"])
+	for _, path := range []string{filepath.Join(kept, "Contents.json"), filepath.Join(kept, "used.imageset")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected namespace folder with a used asset to be kept: %v", err)
+		}
 	}
-	if len(unusedAssets) != 2 || unusedAssets[0] != "logo.colorset" || unusedAssets[1] != "logo.imageset" {
-		t.Fatalf("expected type-distinct unusedAssets, got %#v", unusedAssets)
+	if _, err := os.Stat(catalog); err != nil {
+		t.Fatalf("expected catalog to be kept without --remove-empty-catalogs: %v", err)
 	}
 }
 
-func TestAssetsPrune_DryRunReportsCandidatesWithoutDeleting(t *testing.T) {
+func TestAssetsPrune_ApplyKeepsEmptiedCatalogWithoutFlag(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
-	usedPath := filepath.Join(catalog, "used.imageset")
-	unusedPath := filepath.Join(catalog, "unused.imageset")
-	if err := os.MkdirAll(usedPath, 0o755); err != nil {
-		t.Fatalf("mkdir used asset set: %v", err)
-	}
-	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
-		t.Fatalf("mkdir unused asset set: %v", err)
-	}
-	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
-		t.Fatalf("write source: %v", err)
+	if err := os.MkdirAll(filepath.Join(catalog, "stale.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
 	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "prune", "--path", root}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force"}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	if _, err := os.Stat(catalog); err != nil {
+		t.Fatalf("expected emptied catalog to be kept without --remove-empty-catalogs: %v", err)
 	}
+}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v", err)
+func TestAssetsPrune_RemoveEmptyCatalogsRequiresApply(t *testing.T) {
+	for _, args := range [][]string{
+		{"--remove-empty-catalogs"},
+		{"--apply", "--force", "--remove-empty-catalogs=yes"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "prune", "--path", t.TempDir()}, args...), &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("%v: expected exit code 2, got %d", args, exitCode)
+		}
 	}
-	if payload["dryRun"] != true {
-		t.Fatalf("expected dryRun=true, got %v", payload["dryRun"])
+}
+
+func TestAssetsWatch_MaxScansStopsAfterInitialReport(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Assets.xcassets", "stale.imageset"), 0o755); err != nil {
+		t.Fatalf("mkdir asset set: %v", err)
 	}
-	if payload["unusedCount"] != float64(1) {
-		t.Fatalf("expected unusedCount=1, got %v", payload["unusedCount"])
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"assets", "watch", "--path", root, "--max-scans", "1"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if payload["pruneCandidateCount"] != float64(1) {
-		t.Fatalf("expected pruneCandidateCount=1, got %v", payload["pruneCandidateCount"])
+	var result watchResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected a single JSON report, got err: %v (%s)", err, stdout.String())
 	}
-	deleted, ok := payload["deleted"].([]any)
-	if !ok || len(deleted) != 1 || deleted[0] != unusedPath {
-		t.Fatalf("unexpected deleted payload: %#v", payload["deleted"])
+	if result.Scan != 1 || result.UnusedCount != 1 || !reflect.DeepEqual(result.Unused, []string{"stale"}) {
+		t.Fatalf("unexpected watch report: %#v", result)
 	}
+}
 
-	if _, err := os.Stat(unusedPath); err != nil {
-		t.Fatalf("expected dry-run to keep %s, stat err=%v", unusedPath, err)
+func TestAssetsWatch_InvalidFlagValues_AreUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--debounce", "0s"},
+		{"--debounce", "soon"},
+		{"--max-scans", "-1"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := Execute(append([]string{"assets", "watch", "--path", t.TempDir()}, args...), &stdout, &stderr)
+		if exitCode != 2 {
+			t.Fatalf("%v: expected exit code 2, got %d", args, exitCode)
+		}
 	}
 }
 
-func TestAssetsPrune_ExplicitCountsWhenPruneCandidatesExceedUnusedNames(t *testing.T) {
+func TestAssetsDoctor_EmptyDirWarnsAboutMissingCatalogs(t *testing.T) {
 	root := t.TempDir()
 
-	moduleACatalog := filepath.Join(root, "Modules", "ModuleA", "Assets.xcassets")
-	moduleBCatalog := filepath.Join(root, "Modules", "ModuleB", "Assets.xcassets")
-	if err := os.MkdirAll(filepath.Join(moduleACatalog, "icon.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module a asset: %v", err)
-	}
-	if err := os.MkdirAll(filepath.Join(moduleBCatalog, "icon.imageset"), 0o755); err != nil {
-		t.Fatalf("mkdir module b asset: %v", err)
-	}
-
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "prune", "--path", root}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "doctor", "--path", root}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
-	}
-
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON output, got err: %v, stdout=%s", err, stdout.String())
+	var result doctorResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	if payload["unusedCount"] != float64(1) {
-		t.Fatalf("expected unusedCount=1, got %v", payload["unusedCount"])
+	if result.Catalogs != 0 || result.DefaultExcludesMatched || len(result.SourceFiles) != 0 {
+		t.Fatalf("unexpected doctor report: %#v", result)
 	}
-	if payload["pruneCandidateCount"] != float64(2) {
-		t.Fatalf("expected pruneCandidateCount=2, got %v", payload["pruneCandidateCount"])
+	if len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], "0 asset catalogs found") || !strings.Contains(result.Warnings[0], "--path") {
+		t.Fatalf("expected a missing catalog warning first, got %#v", result.Warnings)
 	}
 }
 
-func TestAssetsPrune_ApplyDeletesUnusedAssetsWhenGitTreeClean(t *testing.T) {
+func TestAssetsDoctor_ReportsExcludeMatchesAndSourceCounts(t *testing.T) {
 	root := t.TempDir()
-	catalog := filepath.Join(root, "Assets.xcassets")
-	usedPath := filepath.Join(catalog, "used.imageset")
-	unusedA := filepath.Join(catalog, "a.imageset")
-	unusedB := filepath.Join(catalog, "z.colorset")
-	if err := os.MkdirAll(usedPath, 0o755); err != nil {
-		t.Fatalf("mkdir used asset set: %v", err)
-	}
-	if err := os.MkdirAll(unusedA, 0o755); err != nil {
-		t.Fatalf("mkdir unused asset set A: %v", err)
-	}
-	if err := os.MkdirAll(unusedB, 0o755); err != nil {
-		t.Fatalf("mkdir unused asset set B: %v", err)
+	for _, dir := range []string{
+		filepath.Join(root, "App", "Assets.xcassets", "hero.imageset"),
+		filepath.Join(root, "Pods", "Lib", "Lib.xcassets"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
 	}
-	if err := os.WriteFile(filepath.Join(root, "Main.swift"), []byte(`let _ = UIImage(named: "used")`), 0o644); err != nil {
-		t.Fatalf("write source: %v", err)
+	for _, name := range []string{"App/View.swift", "App/Model.swift", "App/Main.storyboard", "App/README.txt", "Pods/Lib/Lib.swift"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("x"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
 	}
-	initCleanGitRepo(t, root)
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
+	exitCode := Execute([]string{"assets", "doctor", "--path", root, "--exclude", "Pods/,Legacy/"}, &stdout, &stderr)
 	if exitCode != 0 {
 		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
-	}
-
-	var payload map[string]any
-	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+	var result doctorResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
 		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	deleted, ok := payload["deleted"].([]any)
-	if !ok || len(deleted) != 2 {
-		t.Fatalf("unexpected deleted payload: %#v", payload["deleted"])
+	if result.Catalogs != 1 || !result.DefaultExcludesMatched {
+		t.Fatalf("unexpected doctor summary: %#v", result)
 	}
-	if deleted[0] != unusedA || deleted[1] != unusedB {
-		t.Fatalf("expected deterministic sorted deleted payload [%s %s], got %#v", unusedA, unusedB, deleted)
+	if !reflect.DeepEqual(result.SourceFiles, map[string]int{".storyboard": 1, ".swift": 2}) {
+		t.Fatalf("unexpected source file counts: %#v", result.SourceFiles)
 	}
-	if payload["dryRun"] != false {
-		t.Fatalf("expected dryRun=false, got %v", payload["dryRun"])
+	wantExcludes := []doctorExcludeResult{{Pattern: "Legacy/", Matches: 0}, {Pattern: "Pods/", Default: true, Matches: 1}}
+	if !reflect.DeepEqual(result.Excludes, wantExcludes) {
+		t.Fatalf("unexpected excludes: %#v", result.Excludes)
 	}
-	if _, err := os.Stat(usedPath); err != nil {
-		t.Fatalf("expected used asset set to remain, stat err=%v", err)
+	if !slices.Contains(result.Warnings, `--exclude pattern "Legacy/" matched nothing`) {
+		t.Fatalf("expected a warning for the unmatched exclude, got %#v", result.Warnings)
 	}
-	if _, err := os.Stat(unusedA); !os.IsNotExist(err) {
-		t.Fatalf("expected %s to be deleted, stat err=%v", unusedA, err)
+}
+
+func TestAssetsCommands_CompactFalseIndentsJSON(t *testing.T) {
+	root := t.TempDir()
+	catalog := filepath.Join(root, "Assets.xcassets")
+	for _, name := range []string{"used.imageset", "unused.imageset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
-	if _, err := os.Stat(unusedB); !os.IsNotExist(err) {
-		t.Fatalf("expected %s to be deleted, stat err=%v", unusedB, err)
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
+	}
+
+	// --deterministic drops durationMs, which differs between the two runs.
+	for _, command := range [][]string{
+		{"assets", "scan", "--path", root, "--deterministic"},
+		{"assets", "unused", "--path", root, "--deterministic"},
+		{"assets", "prune", "--path", root, "--deterministic"},
+	} {
+		var compact bytes.Buffer
+		var stderr bytes.Buffer
+		compactExit := Execute(command, &compact, &stderr)
+
+		var indented bytes.Buffer
+		indentedExit := Execute(append([]string{"--compact=false"}, command...), &indented, &stderr)
+		if indentedExit != compactExit {
+			t.Fatalf("%v: expected exit code %d, got %d, stderr=%s", command, compactExit, indentedExit, stderr.String())
+		}
+		if strings.Count(strings.TrimSpace(compact.String()), "\n") != 0 {
+			t.Fatalf("%v: expected minified JSON by default, got %s", command, compact.String())
+		}
+		if !strings.HasPrefix(indented.String(), "{\n  \"command\": ") {
+			t.Fatalf("%v: expected two-space indented JSON, got %s", command, indented.String())
+		}
+		var want bytes.Buffer
+		if err := json.Indent(&want, bytes.TrimSpace(compact.Bytes()), "", "  "); err != nil {
+			t.Fatalf("%v: indent compact payload: %v", command, err)
+		}
+		if got := strings.TrimSpace(indented.String()); got != want.String() {
+			t.Fatalf("%v: expected indented payload\n%s\ngot\n%s", command, want.String(), got)
+		}
+	}
+}
+
+func TestCompactFlag_InvalidValue_IsUsageError(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	exitCode := Execute([]string{"--compact=pretty", "assets", "scan", "--path", t.TempDir()}, &stdout, &stderr)
+	if exitCode != 2 {
+		t.Fatalf("expected exit code 2, got %d", exitCode)
 	}
 }
 
-func TestAssetsPrune_ApplyRequiresCleanGitTree(t *testing.T) {
+func TestAssetsPrune_ApplyNeverDeletesDynamicNameFamilyMatches(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
-	unusedPath := filepath.Join(catalog, "unused.imageset")
-	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
-		t.Fatalf("mkdir unused asset set: %v", err)
+	flagUS := filepath.Join(catalog, "flag_us.imageset")
+	flagFR := filepath.Join(catalog, "flag_fr.imageset")
+	unused := filepath.Join(catalog, "unused.imageset")
+	for _, dir := range []string{flagUS, flagFR, unused} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
-	if err := os.WriteFile(filepath.Join(unusedPath, "Contents.json"), []byte(`{}`), 0o644); err != nil {
-		t.Fatalf("write contents: %v", err)
+	if err := os.WriteFile(filepath.Join(root, "Flags.swift"), []byte(`let flag = UIImage(named: "flag_\(code)")`), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
 	}
 	initCleanGitRepo(t, root)
-	if err := os.WriteFile(filepath.Join(root, "dirty.txt"), []byte("dirty"), 0o644); err != nil {
-		t.Fatalf("write dirty file: %v", err)
-	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
-	if exitCode != 1 {
-		t.Fatalf("expected exit code 1, got %d", exitCode)
+	exitCode := Execute([]string{"assets", "unused", "--path", root}, &stdout, &stderr)
+	if exitCode != 3 {
+		t.Fatalf("expected family matches to be reported unused with exit code 3, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	if stdout.Len() != 0 {
-		t.Fatalf("expected empty stdout, got %s", stdout.String())
+	var unusedPayload unusedResult
+	if err := json.Unmarshal(stdout.Bytes(), &unusedPayload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
+	}
+	if unusedPayload.UnusedCount != 3 || unusedPayload.PruneCandidateCount != 1 {
+		t.Fatalf("expected 3 unused and 1 prune candidate, got unused=%d candidates=%d", unusedPayload.UnusedCount, unusedPayload.PruneCandidateCount)
 	}
 
-	var payload map[string]any
-	if err := json.Unmarshal(stderr.Bytes(), &payload); err != nil {
-		t.Fatalf("expected JSON error output, got err: %v, stderr=%s", err, stderr.String())
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = Execute([]string{"assets", "prune", "--path", root, "--apply"}, &stdout, &stderr)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
 	}
-	errVal, ok := payload["error"].(map[string]any)
-	if !ok {
-		t.Fatalf("missing error object: %v", payload)
+	var payload pruneResult
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("expected JSON output, got err: %v", err)
 	}
-	if errVal["code"] != "runtime_error" {
-		t.Fatalf("unexpected error code: %v", errVal["code"])
+	if !slices.Equal(payload.Deleted, []string{unused}) {
+		t.Fatalf("expected only %s to be deleted, got %v", unused, payload.Deleted)
 	}
-	message, _ := errVal["message"].(string)
-	if !strings.Contains(message, "git working tree is not clean") {
-		t.Fatalf("unexpected error message: %q", message)
+	if !slices.Equal(payload.Protected, []string{flagFR, flagUS}) {
+		t.Fatalf("expected flag family to be protected, got %v", payload.Protected)
 	}
-	if _, err := os.Stat(unusedPath); err != nil {
-		t.Fatalf("expected apply rejection to keep %s, stat err=%v", unusedPath, err)
+	for _, dir := range []string{flagUS, flagFR} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Fatalf("expected %s to remain, stat err=%v", dir, err)
+		}
 	}
 }
 
-func TestAssetsPrune_ApplyForceOverridesDirtyGitTree(t *testing.T) {
+func TestAssetsCommands_YAMLOutputMatchesJSON(t *testing.T) {
 	root := t.TempDir()
 	catalog := filepath.Join(root, "Assets.xcassets")
-	unusedPath := filepath.Join(catalog, "unused.imageset")
-	if err := os.MkdirAll(unusedPath, 0o755); err != nil {
-		t.Fatalf("mkdir unused asset set: %v", err)
-	}
-	if err := os.WriteFile(filepath.Join(unusedPath, "Contents.json"), []byte(`{}`), 0o644); err != nil {
-		t.Fatalf("write contents: %v", err)
+	for _, name := range []string{"used.imageset", "unused.imageset", "true.colorset"} {
+		if err := os.MkdirAll(filepath.Join(catalog, name), 0o755); err != nil {
+			t.Fatalf("mkdir asset set: %v", err)
+		}
 	}
-	initCleanGitRepo(t, root)
-	if err := os.WriteFile(filepath.Join(root, "dirty.txt"), []byte("dirty"), 0o644); err != nil {
-		t.Fatalf("write dirty file: %v", err)
+	if err := os.WriteFile(filepath.Join(root, "View.swift"), []byte(`let image = UIImage(named: "used")`), 0o644); err != nil {
+		t.Fatalf("write swift source: %v", err)
 	}
 
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	exitCode := Execute([]string{"assets", "prune", "--path", root, "--apply", "--force"}, &stdout, &stderr)
-	if exitCode != 0 {
-		t.Fatalf("expected exit code 0, got %d, stderr=%s", exitCode, stderr.String())
-	}
-	if stderr.Len() != 0 {
-		t.Fatalf("expected empty stderr, got %s", stderr.String())
+	commands := []struct {
+		args   []string
+		decode func([]byte) (any, error)
+	}{
+		{args: []string{"assets", "scan", "--path", root, "--deterministic"}, decode: decodeAs[scanResult]},
+		{args: []string{"assets", "unused", "--path", root, "--deterministic"}, decode: decodeAs[unusedResult]},
+		{args: []string{"assets", "prune", "--path", root, "--deterministic"}, decode: decodeAs[pruneResult]},
 	}
-	if _, err := os.Stat(unusedPath); !os.IsNotExist(err) {
-		t.Fatalf("expected %s to be deleted, stat err=%v", unusedPath, err)
+	for _, command := range commands {
+		var jsonOut bytes.Buffer
+		var stderr bytes.Buffer
+		jsonExit := Execute(command.args, &jsonOut, &stderr)
+
+		var yamlOut bytes.Buffer
+		yamlExit := Execute(append([]string{"--output", "yaml"}, command.args...), &yamlOut, &stderr)
+		if yamlExit != jsonExit {
+			t.Fatalf("%v: expected yaml exit code %d to match json, got %d, stderr=%s", command.args, jsonExit, yamlExit, stderr.String())
+		}
+
+		var generic any
+		if err := yaml.Unmarshal(yamlOut.Bytes(), &generic); err != nil {
+			t.Fatalf("%v: parse yaml: %v\n%s", command.args, err, yamlOut.String())
+		}
+		converted, err := json.Marshal(generic)
+		if err != nil {
+			t.Fatalf("%v: convert yaml: %v", command.args, err)
+		}
+		fromYAML, err := command.decode(converted)
+		if err != nil {
+			t.Fatalf("%v: decode yaml payload: %v", command.args, err)
+		}
+		fromJSON, err := command.decode(jsonOut.Bytes())
+		if err != nil {
+			t.Fatalf("%v: decode json payload: %v", command.args, err)
+		}
+		if !reflect.DeepEqual(fromYAML, fromJSON) {
+			t.Fatalf("%v: yaml payload %#v does not match json payload %#v", command.args, fromYAML, fromJSON)
+		}
 	}
 }
 
+func decodeAs[T any](payload []byte) (any, error) {
+	var value T
+	err := json.Unmarshal(payload, &value)
+	return value, err
+}
+
 func initCleanGitRepo(t *testing.T, root string) {
 	t.Helper()
 	if _, err := exec.LookPath("git"); err != nil {
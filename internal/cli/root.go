@@ -4,29 +4,54 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"text/template"
 
 	"github.com/spf13/cobra"
+	"xcwrap/internal/assets"
 )
 
 const (
 	outputJSON     = "json"
 	outputTable    = "table"
 	outputMarkdown = "markdown"
+	outputCSV      = "csv"
+	outputYAML     = "yaml"
+	outputTemplate = "template"
 )
 
+// version is the xcwrap release, set at build time with
+// -ldflags "-X xcwrap/internal/cli.version=<version>".
+var version = "dev"
+
+type versionResult struct {
+	Version      string `json:"version"`
+	RulesVersion int    `json:"rulesVersion"`
+}
+
 type runContext struct {
 	stdout io.Writer
 	stderr io.Writer
 
 	output string
+	// compact selects minified JSON; --compact=false indents it.
+	compact bool
+	// deterministic omits run-dependent fields such as durationMs.
+	deterministic bool
+
+	// template is the parsed --template/--template-file body used when
+	// output is outputTemplate.
+	template *template.Template
 }
 
 func newRootCommand(stdout io.Writer, stderr io.Writer) *cobra.Command {
 	ctx := &runContext{
-		stdout: stdout,
-		stderr: stderr,
-		output: defaultOutput(),
+		stdout:  stdout,
+		stderr:  stderr,
+		output:  defaultOutput(),
+		compact: true,
 	}
 
 	cmd := &cobra.Command{
@@ -35,16 +60,34 @@ func newRootCommand(stdout io.Writer, stderr io.Writer) *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 	}
+	var showVersion bool
+	cmd.RunE = func(c *cobra.Command, _ []string) error {
+		if !showVersion {
+			return c.Help()
+		}
+		return render(ctx, versionResult{Version: version, RulesVersion: assets.RulesVersion}, renderVersionResult)
+	}
+	cmd.Flags().BoolVar(&showVersion, "version", false, "Print the xcwrap version and detection rules version")
 
 	cmd.SetOut(stdout)
 	cmd.SetErr(stderr)
-	cmd.PersistentFlags().StringVar(&ctx.output, "output", ctx.output, "Output format: json|table|markdown")
-	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
+	var templateText string
+	var templateFile string
+	cmd.PersistentFlags().StringVar(&ctx.output, "output", ctx.output, "Output format: json|table|markdown|csv|yaml|template")
+	cmd.PersistentFlags().BoolVar(&ctx.compact, "compact", ctx.compact, "Write minified JSON; set false to indent JSON output")
+	cmd.PersistentFlags().BoolVar(&ctx.deterministic, "deterministic", false, "Omit run-dependent fields such as durationMs so identical inputs produce identical output")
+	cmd.PersistentFlags().Bool("json-errors", defaultJSONErrors(), "Write errors to stderr as a JSON envelope; set false for plain-text \"error: <message>\" lines")
+	cmd.PersistentFlags().StringVar(&templateText, "template", "", "Go text/template executed over the result (requires --output template)")
+	cmd.PersistentFlags().StringVar(&templateFile, "template-file", "", "File containing a Go text/template executed over the result (requires --output template)")
+	cmd.PersistentPreRunE = func(c *cobra.Command, _ []string) error {
 		if !isAllowedOutput(ctx.output) {
-			return usageError{
-				Message: fmt.Sprintf("invalid value for --output: %q (allowed: json, table, markdown)", ctx.output),
-			}
+			return invalidOutputError(ctx.output)
+		}
+		tmpl, err := parseOutputTemplate(ctx.output, templateText, templateFile, c.Flags().Changed("template"))
+		if err != nil {
+			return err
 		}
+		ctx.template = tmpl
 		return nil
 	}
 	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
@@ -62,17 +105,114 @@ func defaultOutput() string {
 		return outputJSON
 	}
 	normalized := strings.ToLower(strings.TrimSpace(v))
-	if !isAllowedOutput(normalized) {
+	// template needs a per-invocation --template, so it cannot be a default.
+	if !isAllowedOutput(normalized) || normalized == outputTemplate {
 		return outputJSON
 	}
 	return normalized
 }
 
+// defaultJSONErrors reads XCWRAP_JSON_ERRORS, keeping JSON errors unless it
+// holds a valid false boolean.
+func defaultJSONErrors() bool {
+	v, ok := os.LookupEnv("XCWRAP_JSON_ERRORS")
+	if !ok {
+		return true
+	}
+	enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return true
+	}
+	return enabled
+}
+
 func isAllowedOutput(v string) bool {
 	switch v {
-	case outputJSON, outputTable, outputMarkdown:
+	case outputJSON, outputTable, outputMarkdown, outputCSV, outputYAML, outputTemplate:
 		return true
 	default:
 		return false
 	}
 }
+
+func invalidOutputError(output string) error {
+	return usageError{
+		Message: fmt.Sprintf("invalid value for --output: %q (allowed: json, table, markdown, csv, yaml, template)", output),
+	}
+}
+
+// parseOutputTemplate parses the template supplied via --template or
+// --template-file. It returns nil when output is not outputTemplate.
+func parseOutputTemplate(output, text, file string, textSet bool) (*template.Template, error) {
+	hasTemplate := textSet || file != ""
+	if output != outputTemplate {
+		if hasTemplate {
+			return nil, usageError{Message: "--template and --template-file require --output template"}
+		}
+		return nil, nil
+	}
+	if textSet && file != "" {
+		return nil, usageError{Message: "--template and --template-file are mutually exclusive"}
+	}
+	if !hasTemplate {
+		return nil, usageError{Message: "--output template requires --template or --template-file"}
+	}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, usageError{Message: fmt.Sprintf("failed to read --template-file: %v", err)}
+		}
+		text = string(data)
+	}
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, usageError{Message: fmt.Sprintf("invalid template: %v", err)}
+	}
+	return tmpl, nil
+}
+
+// outputFormat is the resolved output selection passed to renderers.
+type outputFormat struct {
+	name string
+	// indent is the JSON indentation; empty writes minified JSON.
+	indent string
+}
+
+func (ctx *runContext) format() outputFormat {
+	format := outputFormat{name: ctx.output}
+	if !ctx.compact {
+		format.indent = "  "
+	}
+	return format
+}
+
+// render writes result with the user template when output is
+// outputTemplate, and with the command's own renderer otherwise.
+func render[T any](ctx *runContext, result T, renderer func(io.Writer, outputFormat, T) error) error {
+	if ctx.output == outputTemplate {
+		return ctx.template.Execute(ctx.stdout, result)
+	}
+	return renderer(ctx.stdout, ctx.format(), result)
+}
+
+func renderVersionResult(w io.Writer, format outputFormat, result versionResult) error {
+	switch format.name {
+	case outputJSON:
+		return writeJSON(w, result, format.indent)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintf(tw, "version\trules_version\n%s\t%d\n", result.Version, result.RulesVersion); err != nil {
+			return err
+		}
+		return tw.Flush()
+	case outputMarkdown:
+		_, err := fmt.Fprintf(w, "| version | rules_version |\n|---|---:|\n| %s | %d |\n", result.Version, result.RulesVersion)
+		return err
+	case outputYAML:
+		return writeYAML(w, result)
+	case outputCSV:
+		return writeCSV(w, []string{"version", "rules_version"}, [][]string{{result.Version, strconv.Itoa(result.RulesVersion)}})
+	default:
+		return invalidOutputError(format.name)
+	}
+}